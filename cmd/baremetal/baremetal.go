@@ -15,14 +15,43 @@
 package baremetal
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 
 	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/pkg/log"
 	"opendev.org/airship/airshipctl/pkg/remote"
+	"opendev.org/airship/airshipctl/pkg/util"
 )
 
 const (
+	flagAll            = "all"
+	flagAllDescription = "Select all baremetal hosts of the phase instead of filtering them"
+
+	flagAnnotations            = "annotations"
+	flagAnnotationsDescription = "Annotation(s) to filter desired baremetal host documents"
+
+	flagBMCAddress            = "bmc-address"
+	flagBMCAddressDescription = "Regular expression the BMC address of desired baremetal hosts must match"
+
+	flagConcurrency            = "concurrency"
+	flagConcurrencyDescription = "Number of baremetal hosts to perform the action on at once. Use 1 to act on one " +
+		"host after the other"
+
+	flagDryRun            = "dry-run"
+	flagDryRunDescription = "Print the Redfish requests actions would send to each host instead of sending the " +
+		"requests that change the state of hosts"
+
 	flagLabel            = "labels"
 	flagLabelShort       = "l"
 	flagLabelDescription = "Label(s) to filter desired baremetal host documents"
@@ -31,8 +60,26 @@ const (
 	flagNameShort       = "n"
 	flagNameDescription = "Name to filter desired baremetal host document"
 
+	flagNamespace            = "namespace"
+	flagNamespaceDescription = "Namespace to filter desired baremetal host documents"
+
+	flagOutput            = "output"
+	flagOutputShort       = "o"
+	flagOutputDescription = "Output format. One of: yaml, json"
+
 	flagPhase            = "phase"
 	flagPhaseDescription = "airshipctl phase that contains the desired baremetal host document(s)"
+
+	flagUnion            = "union"
+	flagUnionDescription = "Select hosts matching any of the filters rather than all of them"
+)
+
+// Output formats of commands that print documents, tables or lines of text
+const (
+	outputYAML  = "yaml"
+	outputJSON  = "json"
+	outputTable = "table"
+	outputText  = "text"
 )
 
 // NewBaremetalCommand creates a new command for interacting with baremetal using airshipctl.
@@ -48,9 +95,32 @@ func NewBaremetalCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Co
 		},
 	}
 
+	baremetalRootCmd.PersistentFlags().Bool(flagDryRun, false, flagDryRunDescription)
+
+	biosCmd := NewBIOSCommand(rootSettings)
+	baremetalRootCmd.AddCommand(biosCmd)
+
+	bootDeviceCmd := NewBootDeviceCommand(rootSettings)
+	baremetalRootCmd.AddCommand(bootDeviceCmd)
+
 	ejectMediaCmd := NewEjectMediaCommand(rootSettings)
 	baremetalRootCmd.AddCommand(ejectMediaCmd)
 
+	emulateCmd := NewEmulateCommand(rootSettings)
+	baremetalRootCmd.AddCommand(emulateCmd)
+
+	firmwareCmd := NewFirmwareCommand(rootSettings)
+	baremetalRootCmd.AddCommand(firmwareCmd)
+
+	historyCmd := NewHistoryCommand(rootSettings)
+	baremetalRootCmd.AddCommand(historyCmd)
+
+	insertMediaCmd := NewInsertMediaCommand(rootSettings)
+	baremetalRootCmd.AddCommand(insertMediaCmd)
+
+	inventoryCmd := NewInventoryCommand(rootSettings)
+	baremetalRootCmd.AddCommand(inventoryCmd)
+
 	powerOffCmd := NewPowerOffCommand(rootSettings)
 	baremetalRootCmd.AddCommand(powerOffCmd)
 
@@ -66,6 +136,9 @@ func NewBaremetalCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Co
 	remoteDirectCmd := NewRemoteDirectCommand(rootSettings)
 	baremetalRootCmd.AddCommand(remoteDirectCmd)
 
+	verifyCmd := NewVerifyCommand(rootSettings)
+	baremetalRootCmd.AddCommand(verifyCmd)
+
 	return baremetalRootCmd
 }
 
@@ -83,3 +156,193 @@ func GetHostSelections(name string, labels string) []remote.HostSelector {
 
 	return selectors
 }
+
+// hostSelectionFlags holds the flags commands use to select the baremetal hosts to act on. Filters are combined by
+// intersection unless union is set. When neither filters nor all are supplied, no hosts are selected unless
+// allByDefault is set, so that commands which change the state of hosts never act on every host by accident.
+type hostSelectionFlags struct {
+	allByDefault bool
+
+	all         bool
+	annotations string
+	bmcAddress  string
+	labels      string
+	name        string
+	namespace   string
+	union       bool
+}
+
+// register adds the host selection flags to a command.
+func (f *hostSelectionFlags) register(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.BoolVar(&f.all, flagAll, false, flagAllDescription)
+	flags.StringVar(&f.annotations, flagAnnotations, "", flagAnnotationsDescription)
+	flags.StringVar(&f.bmcAddress, flagBMCAddress, "", flagBMCAddressDescription)
+	flags.StringVarP(&f.labels, flagLabel, flagLabelShort, "", flagLabelDescription)
+	flags.StringVarP(&f.name, flagName, flagNameShort, "", flagNameDescription)
+	flags.StringVar(&f.namespace, flagNamespace, "", flagNamespaceDescription)
+	flags.BoolVar(&f.union, flagUnion, false, flagUnionDescription)
+}
+
+// selector builds the host selector described by the host selection flags.
+func (f *hostSelectionFlags) selector() (remote.HostSelector, error) {
+	selectors := GetHostSelections(f.name, f.labels)
+	if f.namespace != "" {
+		selectors = append(selectors, remote.ByNamespace(f.namespace))
+	}
+
+	if f.annotations != "" {
+		selectors = append(selectors, remote.ByAnnotation(f.annotations))
+	}
+
+	if f.bmcAddress != "" {
+		selectors = append(selectors, remote.ByBMCAddress(f.bmcAddress))
+	}
+
+	switch {
+	case f.all && (len(selectors) > 0 || f.union):
+		return nil, fmt.Errorf("flag --%s cannot be combined with host filters", flagAll)
+	case f.all, len(selectors) == 0 && f.allByDefault:
+		return remote.AllHosts(), nil
+	case f.union:
+		return remote.AnyOf(selectors...), nil
+	default:
+		return remote.AllOf(selectors...), nil
+	}
+}
+
+// newManager provides a manager for the baremetal hosts of a phase selected by selector. When the --dry-run flag of
+// the baremetal command group is set, the manager's hosts are put in dry run so that actions only record the requests
+// that change the state of the hosts.
+func newManager(cmd *cobra.Command, rootSettings *environment.AirshipCTLSettings, phase string,
+	selector remote.HostSelector) (*remote.Manager, error) {
+	m, err := remote.NewManager(rootSettings, phase, selector)
+	if err != nil {
+		return m, err
+	}
+
+	// The flag is only defined when the command is run as part of the baremetal command group
+	if dryRun, flagErr := cmd.Flags().GetBool(flagDryRun); flagErr == nil && dryRun {
+		err = m.DryRun()
+	}
+
+	return m, err
+}
+
+// rejectDryRun returns an error when the --dry-run flag of the baremetal command group is set for a command, or a
+// mode of a command, described by usage that cannot be run in dry run, so that the flag is never silently ignored.
+func rejectDryRun(cmd *cobra.Command, usage string) error {
+	if dryRun, err := cmd.Flags().GetBool(flagDryRun); err == nil && dryRun {
+		return fmt.Errorf("flag --%s cannot be used %s", flagDryRun, usage)
+	}
+
+	return nil
+}
+
+// closeOnExit binds the actions performed on a manager's hosts to a context that is cancelled when airshipctl is
+// interrupted, so that an interrupted command unwinds and returns, and ensures the resources held by the manager's
+// clients, e.g. Redfish sessions, are released when the command returns. Commands should defer the returned function,
+// and stop any other work, e.g. watching the hosts, once the returned context is done.
+func closeOnExit(m *remote.Manager) (context.Context, func()) {
+	ctx, stop := notifyContext(context.Background())
+	m.WithContext(ctx)
+
+	return ctx, func() {
+		stop()
+		m.Close()
+	}
+}
+
+// notifyContext returns a copy of parent that is cancelled when airshipctl receives SIGINT or SIGTERM, or when the
+// returned stop function is called. It behaves like signal.NotifyContext, which the Go release airshipctl is built
+// with does not provide.
+func notifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-interrupts:
+			log.Printf("Received signal '%s'. Stopping the actions in progress on baremetal hosts.", sig)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(interrupts)
+		cancel()
+	}
+}
+
+// printDocument writes a value to out as a YAML or JSON document.
+func printDocument(out io.Writer, format string, v interface{}) error {
+	var data []byte
+	var err error
+	switch format {
+	case outputYAML:
+		data, err = yaml.Marshal(v)
+	case outputJSON:
+		data, err = json.MarshalIndent(v, "", "  ")
+		data = append(data, '\n')
+	default:
+		return fmt.Errorf("unsupported output format %q, must be one of: %s, %s", format, outputYAML, outputJSON)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(data)
+	return err
+}
+
+// printHostResults writes a summary table describing the outcome of an action performed on each baremetal host,
+// followed by the requests the action made to each host in dry run.
+func printHostResults(out io.Writer, results []remote.HostResult) {
+	tw := util.NewTabWriter(out)
+	fmt.Fprintf(tw, "HOST\tBMC ADDRESS\tACTION\tRESULT\tDURATION\n")
+	for _, result := range results {
+		outcome := "OK"
+		switch {
+		case result.Err != nil:
+			// Collapse multi-line BMC error messages so that each host occupies a single row
+			outcome = "FAILED: " + strings.Join(strings.Fields(result.Err.Error()), " ")
+		case result.Output != "":
+			outcome = result.Output
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", result.HostName, result.BMCAddress, result.Action, outcome,
+			result.Duration.Round(time.Millisecond))
+	}
+	tw.Flush()
+
+	for _, result := range results {
+		if len(result.Calls) > 0 {
+			printDryRunCalls(out, result)
+		}
+	}
+}
+
+// printDryRunCalls writes the requests made to the BMC of a baremetal host in dry run, in the order they were made,
+// marking those that were not sent because they change the state of the host. The management type of the host and
+// the source of its credentials are written first, so that they can be checked before the requests are sent.
+func printDryRunCalls(out io.Writer, result remote.HostResult) {
+	fmt.Fprintf(out, "\nDry run: requests to host '%s'\n", result.HostName)
+	fmt.Fprintf(out, "Management type: %s\n", result.ManagementType)
+	fmt.Fprintf(out, "Credentials: %s\n", result.CredentialsSource)
+
+	tw := util.NewTabWriter(out)
+	fmt.Fprintf(tw, "METHOD\tURL\tSENT\tBODY\n")
+	for _, call := range result.Calls {
+		sent := "yes"
+		if call.Pending {
+			sent = "no"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", call.Method, call.URL, sent, call.Body)
+	}
+	tw.Flush()
+}
@@ -15,11 +15,21 @@
 package baremetal_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"opendev.org/airship/airshipctl/cmd/baremetal"
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
 	"opendev.org/airship/airshipctl/testutil"
 )
 
@@ -30,11 +40,76 @@ func TestBaremetal(t *testing.T) {
 			CmdLine: "-h",
 			Cmd:     baremetal.NewBaremetalCommand(nil),
 		},
+		{
+			Name:    "baremetal-bios-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewBIOSCommand(nil),
+		},
+		{
+			Name:    "baremetal-bios-get-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewBIOSGetCommand(nil),
+		},
+		{
+			Name:    "baremetal-bios-set-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewBIOSSetCommand(nil),
+		},
+		{
+			Name:    "baremetal-bootdevice-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewBootDeviceCommand(nil),
+		},
+		{
+			Name:    "baremetal-bootdevice-get-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewBootDeviceGetCommand(nil),
+		},
+		{
+			Name:    "baremetal-bootdevice-set-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewBootDeviceSetCommand(nil),
+		},
 		{
 			Name:    "baremetal-ejectmedia-with-help",
 			CmdLine: "-h",
 			Cmd:     baremetal.NewEjectMediaCommand(nil),
 		},
+		{
+			Name:    "baremetal-emulate-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewEmulateCommand(nil),
+		},
+		{
+			Name:    "baremetal-firmware-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewFirmwareCommand(nil),
+		},
+		{
+			Name:    "baremetal-firmware-list-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewFirmwareListCommand(nil),
+		},
+		{
+			Name:    "baremetal-firmware-update-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewFirmwareUpdateCommand(nil),
+		},
+		{
+			Name:    "baremetal-history-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewHistoryCommand(nil),
+		},
+		{
+			Name:    "baremetal-insertmedia-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewInsertMediaCommand(nil),
+		},
+		{
+			Name:    "baremetal-inventory-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewInventoryCommand(nil),
+		},
 		{
 			Name:    "baremetal-poweroff-with-help",
 			CmdLine: "-h",
@@ -60,6 +135,11 @@ func TestBaremetal(t *testing.T) {
 			CmdLine: "-h",
 			Cmd:     baremetal.NewRemoteDirectCommand(nil),
 		},
+		{
+			Name:    "baremetal-verify-with-help",
+			CmdLine: "-h",
+			Cmd:     baremetal.NewVerifyCommand(nil),
+		},
 	}
 
 	for _, tt := range tests {
@@ -81,3 +161,149 @@ func TestGetHostSelectionsNone(t *testing.T) {
 	selectors := baremetal.GetHostSelections("", "")
 	assert.Len(t, selectors, 0)
 }
+
+func TestHostSelectionAllWithFilters(t *testing.T) {
+	for _, args := range [][]string{
+		{"--all", "--name", "node0"},
+		{"--all", "--bmc-address", "10.23.25.1"},
+		{"--all", "--union"},
+	} {
+		cmd := baremetal.NewPowerOnCommand(nil)
+		cmd.SetArgs(args)
+		cmd.SetOut(ioutil.Discard)
+		cmd.SetErr(ioutil.Discard)
+
+		err := cmd.Execute()
+		assert.EqualError(t, err, "flag --all cannot be combined with host filters")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airshipctl-history-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	auditLog := `{"timestamp":"2020-06-01T22:00:00Z","user":"operator","context":"prod","host":"node-1",` +
+		`"bmcAddress":"redfish+https://10.23.25.1/redfish/v1/Systems/1","action":"power off","outcome":"succeeded"}
+{"timestamp":"2020-06-01T23:00:00Z","user":"operator","context":"prod","host":"node-2",` +
+		`"bmcAddress":"redfish+https://10.23.25.2/redfish/v1/Systems/1","action":"power off","outcome":"failed",` +
+		`"error":"BMC unavailable"}
+{"timestamp":"2020-06-02T01:00:00Z","user":"operator","context":"prod","host":"node-1",` +
+		`"bmcAddress":"redfish+https://10.23.25.1/redfish/v1/Systems/1","action":"power on","outcome":"succeeded"}
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, config.AirshipAuditLog), []byte(auditLog), 0600))
+
+	settings := &environment.AirshipCTLSettings{AirshipConfigPath: filepath.Join(dir, config.AirshipConfig)}
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{args: []string{"-o", "json"}, expected: []string{"power off", "power off", "power on"}},
+		{args: []string{"-o", "json", "--name", "node-1"}, expected: []string{"power off", "power on"}},
+		{
+			args:     []string{"-o", "json", "--since", "2020-06-01T22:30:00Z", "--until", "2020-06-02T00:00:00Z"},
+			expected: []string{"power off"},
+		},
+	}
+
+	for _, tt := range tests {
+		out := &bytes.Buffer{}
+		cmd := baremetal.NewHistoryCommand(settings)
+		cmd.SetArgs(tt.args)
+		cmd.SetOut(out)
+		cmd.SetErr(ioutil.Discard)
+		require.NoError(t, cmd.Execute())
+
+		var records []remote.AuditRecord
+		require.NoError(t, json.Unmarshal(out.Bytes(), &records))
+
+		actions := []string{}
+		for _, record := range records {
+			actions = append(actions, record.Action)
+		}
+		assert.Equal(t, tt.expected, actions, tt.args)
+	}
+}
+
+func TestHistoryInvalidTime(t *testing.T) {
+	cmd := baremetal.NewHistoryCommand(&environment.AirshipCTLSettings{})
+	cmd.SetArgs([]string{"--since", "yesterday"})
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+
+	err := cmd.Execute()
+	assert.EqualError(t, err, `invalid value "yesterday" for flag --since, must be an RFC 3339 timestamp or a `+
+		"positive duration")
+}
+
+func TestPowerStatusInvalidOutput(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{
+			args:     []string{"-o", "csv"},
+			expected: `unsupported output format "csv", must be one of: text, table, json, yaml`,
+		},
+		{
+			args:     []string{"--watch", "-o", "table"},
+			expected: `unsupported output format "table" with --watch, must be one of: text, json`,
+		},
+	}
+
+	for _, tt := range tests {
+		cmd := baremetal.NewPowerStatusCommand(&environment.AirshipCTLSettings{})
+		cmd.SetArgs(tt.args)
+		cmd.SetOut(ioutil.Discard)
+		cmd.SetErr(ioutil.Discard)
+
+		assert.EqualError(t, cmd.Execute(), tt.expected)
+	}
+}
+
+func TestBootDeviceGetInvalidOutput(t *testing.T) {
+	cmd := baremetal.NewBootDeviceGetCommand(&environment.AirshipCTLSettings{})
+	cmd.SetArgs([]string{"-o", "csv"})
+	cmd.SetOut(ioutil.Discard)
+	cmd.SetErr(ioutil.Discard)
+
+	assert.EqualError(t, cmd.Execute(), `unsupported output format "csv", must be one of: text, table, json, yaml`)
+}
+
+func TestRejectDryRun(t *testing.T) {
+	settings := &environment.AirshipCTLSettings{}
+	tests := []struct {
+		cmd      *cobra.Command
+		args     []string
+		expected string
+	}{
+		{
+			cmd:      baremetal.NewHistoryCommand(settings),
+			expected: "flag --dry-run cannot be used with the history command, which makes no requests to hosts",
+		},
+		{
+			cmd:      baremetal.NewEmulateCommand(settings),
+			expected: "flag --dry-run cannot be used with the emulate command, which makes no requests to hosts",
+		},
+		{
+			cmd:      baremetal.NewVerifyCommand(settings),
+			expected: "flag --dry-run cannot be used with the verify command, which only retrieves information",
+		},
+		{
+			cmd:      baremetal.NewPowerStatusCommand(settings),
+			args:     []string{"--watch"},
+			expected: "flag --dry-run cannot be used with --watch",
+		},
+	}
+
+	for _, tt := range tests {
+		// The flag is defined by the baremetal command group
+		tt.cmd.Flags().Bool("dry-run", false, "")
+		tt.cmd.SetArgs(append(tt.args, "--dry-run"))
+		tt.cmd.SetOut(ioutil.Discard)
+		tt.cmd.SetErr(ioutil.Discard)
+
+		assert.EqualError(t, tt.cmd.Execute(), tt.expected)
+	}
+}
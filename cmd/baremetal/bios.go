@@ -0,0 +1,166 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+	"opendev.org/airship/airshipctl/pkg/remote/bios"
+)
+
+const (
+	flagAttribute            = "attribute"
+	flagAttributeDescription = "Name of a BIOS attribute to retrieve. May be repeated; all attributes are retrieved " +
+		"by default"
+
+	flagReboot            = "reboot"
+	flagRebootDescription = "Reboot hosts on which BIOS attributes were staged so that they are applied"
+)
+
+// NewBIOSCommand provides a command to manage the BIOS settings of baremetal hosts.
+func NewBIOSCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bios",
+		Short: "Manage the BIOS settings of baremetal hosts",
+	}
+
+	cmd.AddCommand(NewBIOSGetCommand(rootSettings))
+	cmd.AddCommand(NewBIOSSetCommand(rootSettings))
+
+	return cmd
+}
+
+// NewBIOSGetCommand provides a command to retrieve the BIOS settings of baremetal hosts.
+func NewBIOSGetCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var attributes []string
+	var concurrency int
+	var hosts hostSelectionFlags
+	var output string
+	var phase string
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Retrieve the BIOS settings of baremetal hosts",
+		Long: `Retrieve the current BIOS attributes of baremetal hosts from their BMCs, along with the attributes that have
+been staged and are applied on the next reboot of the hosts.`,
+		Example: `
+# Retrieve every BIOS attribute of the host node01
+airshipctl baremetal bios get --name node01
+
+# Retrieve the virtualization and SR-IOV settings of the ephemeral host
+airshipctl baremetal bios get --attribute ProcVirtualization --attribute SriovGlobalEnable \
+    --labels airshipit.org/ephemeral-node=true
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			_, done := closeOnExit(m)
+			defer done()
+
+			settings, err := m.BIOSSettings(concurrency, attributes...)
+			if printErr := printDocument(cmd.OutOrStdout(), output, settings); printErr != nil {
+				return printErr
+			}
+
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringArrayVar(&attributes, flagAttribute, nil, flagAttributeDescription)
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	hosts.register(cmd)
+	flags.StringVarP(&output, flagOutput, flagOutputShort, outputYAML, flagOutputDescription)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+
+	return cmd
+}
+
+// NewBIOSSetCommand provides a command to change the BIOS settings of baremetal hosts.
+func NewBIOSSetCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var concurrency int
+	var hosts hostSelectionFlags
+	var phase string
+	var reboot bool
+
+	cmd := &cobra.Command{
+		Use:   "set [NAME=VALUE...]",
+		Short: "Change the BIOS settings of baremetal hosts",
+		Long: `Stage BIOS attributes on baremetal hosts. The desired attributes of a host are read from the
+HostFirmwareSettings document with the same name as its BareMetalHost document, if any, and may be overridden by
+NAME=VALUE arguments. Only attributes whose values differ from the current ones are staged. Staged attributes are
+applied on the next reboot of a host, which can be performed immediately with --reboot.
+
+A HostFirmwareSettings document lists the desired attributes under spec.settings, e.g.
+
+  apiVersion: metal3.io/v1alpha1
+  kind: HostFirmwareSettings
+  metadata:
+    name: node01
+  spec:
+    settings:
+      ProcVirtualization: Enabled
+      SriovGlobalEnable: "true"`,
+		Example: `
+# Apply the BIOS settings of the HostFirmwareSettings documents of the ephemeral host
+airshipctl baremetal bios set --reboot --labels airshipit.org/ephemeral-node=true
+
+# Enable virtualization on the host node01 on its next reboot
+airshipctl baremetal bios set ProcVirtualization=Enabled --name node01
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			attributes, err := bios.ParseAttributes(args)
+			if err != nil {
+				return err
+			}
+
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			_, done := closeOnExit(m)
+			defer done()
+
+			results, err := m.SetBIOSSettings(concurrency, attributes, reboot)
+			printHostResults(cmd.OutOrStdout(), results)
+
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	hosts.register(cmd)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.BoolVar(&reboot, flagReboot, false, flagRebootDescription)
+
+	return cmd
+}
@@ -0,0 +1,214 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+const (
+	flagBootDeviceOutputDescription = "Output format. One of: text, table, json, yaml"
+
+	flagDevice            = "device"
+	flagDeviceDescription = "Device to boot from. One of: pxe, disk, cd, bios, none"
+
+	flagOnce            = "once"
+	flagOnceDescription = "Boot from the device on the next boot only (default)"
+
+	flagPersistent            = "persistent"
+	flagPersistentDescription = "Boot from the device on every boot"
+)
+
+// NewBootDeviceCommand provides a command to manage the device baremetal hosts boot from.
+func NewBootDeviceCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "boot-device",
+		Short: "Manage the device baremetal hosts boot from",
+	}
+
+	cmd.AddCommand(NewBootDeviceGetCommand(rootSettings))
+	cmd.AddCommand(NewBootDeviceSetCommand(rootSettings))
+
+	return cmd
+}
+
+// NewBootDeviceGetCommand provides a command to retrieve the boot device override of baremetal hosts.
+func NewBootDeviceGetCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var concurrency int
+	var hosts hostSelectionFlags
+	var output string
+	var phase string
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Retrieve the device baremetal hosts are instructed to boot from",
+		Long: `Retrieve the device baremetal hosts are instructed to boot from in place of their boot order, and whether
+they boot from it on their next boot only or on every boot. Hosts that boot according to their boot order report none.
+With --output table, json or yaml, a record is printed for each selected host holding its name, BMC address, node ID,
+and its boot device and mode or the reason they could not be retrieved.`,
+		Example: `
+# Retrieve the boot device of every control plane host as JSON, for consumption by scripts
+airshipctl baremetal boot-device get --labels airshipit.org/k8s-role=controlplane-host -o json
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateStatusOutput(output, false); err != nil {
+				return err
+			}
+
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			_, done := closeOnExit(m)
+			defer done()
+
+			overrides, err := m.BootOverrides(concurrency)
+			if printErr := printBootOverrides(cmd.OutOrStdout(), output, overrides); printErr != nil {
+				return printErr
+			}
+
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	hosts.register(cmd)
+	flags.StringVarP(&output, flagOutput, flagOutputShort, outputText, flagBootDeviceOutputDescription)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+
+	return cmd
+}
+
+// printBootOverrides writes the boot device override of each baremetal host as a line of text, a table row, or a
+// record of a YAML or JSON document. Lines of text are only written for the hosts whose override was retrieved.
+func printBootOverrides(out io.Writer, format string, overrides []remote.HostBootOverride) error {
+	switch format {
+	case outputText:
+		for _, override := range overrides {
+			switch {
+			case override.Error != "":
+			case override.Mode == "":
+				fmt.Fprintf(out, "Host '%s' boots from: '%s'\n", override.HostName, override.Device)
+			default:
+				fmt.Fprintf(out, "Host '%s' boots from: '%s' (%s)\n", override.HostName, override.Device,
+					override.Mode)
+			}
+		}
+	case outputTable:
+		tw := util.NewTabWriter(out)
+		fmt.Fprintf(tw, "HOST\tBMC ADDRESS\tNODE ID\tDEVICE\tMODE\tERROR\n")
+		for _, override := range overrides {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", override.HostName, override.BMCAddress, override.NodeID,
+				override.Device, override.Mode, override.Error)
+		}
+		tw.Flush()
+	default:
+		return printDocument(out, format, overrides)
+	}
+
+	return nil
+}
+
+// NewBootDeviceSetCommand provides a command to override the device baremetal hosts boot from.
+func NewBootDeviceSetCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var concurrency int
+	var device string
+	var hosts hostSelectionFlags
+	var once bool
+	var persistent bool
+	var phase string
+
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Instruct baremetal hosts to boot from a device",
+		Long: `Instruct baremetal hosts to boot from a device in place of their boot order, either on their next boot only
+(--once) or on every boot (--persistent). The device none clears the override so that hosts boot according to their
+boot order.`,
+		Example: `
+# Boot the host node01 from the network on its next boot
+airshipctl baremetal boot-device set --device pxe --once --name node01
+
+# Boot the ephemeral host from its disk on every boot
+airshipctl baremetal boot-device set --device disk --persistent --labels airshipit.org/ephemeral-node=true
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if once && persistent {
+				return fmt.Errorf("flags --%s and --%s are mutually exclusive", flagOnce, flagPersistent)
+			}
+
+			bootDevice, err := boot.ParseDevice(device)
+			if err != nil {
+				return err
+			}
+
+			override := boot.Override{Device: bootDevice, Persistent: persistent}
+
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			_, done := closeOnExit(m)
+			defer done()
+
+			setBootOverride := func(ctx context.Context, client remote.Client) (string, error) {
+				return override.String(), client.SetBootOverride(ctx, override)
+			}
+
+			results, err := m.Execute("set boot device", concurrency, setBootOverride)
+			printHostResults(cmd.OutOrStdout(), results)
+
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	flags.StringVar(&device, flagDevice, "", flagDeviceDescription)
+	hosts.register(cmd)
+	flags.BoolVar(&once, flagOnce, false, flagOnceDescription)
+	flags.BoolVar(&persistent, flagPersistent, false, flagPersistentDescription)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+
+	err := cmd.MarkFlagRequired(flagDevice)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return cmd
+}
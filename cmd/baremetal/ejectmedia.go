@@ -15,7 +15,7 @@
 package baremetal
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/spf13/cobra"
 
@@ -26,8 +26,8 @@ import (
 
 // NewEjectMediaCommand provides a command to eject media attached to a baremetal host.
 func NewEjectMediaCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
-	var labels string
-	var name string
+	var concurrency int
+	var hosts hostSelectionFlags
 	var phase string
 
 	cmd := &cobra.Command{
@@ -35,27 +35,32 @@ func NewEjectMediaCommand(rootSettings *environment.AirshipCTLSettings) *cobra.C
 		Short: "Eject media attached to a baremetal host",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			selectors := GetHostSelections(name, labels)
-			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			selector, err := hosts.selector()
 			if err != nil {
 				return err
 			}
 
-			for _, host := range m.Hosts {
-				if err := host.EjectVirtualMedia(host.Context); err != nil {
-					return err
-				}
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			_, done := closeOnExit(m)
+			defer done()
 
-				fmt.Fprintf(cmd.OutOrStdout(), "All media ejected from host '%s'.\n", host.HostName)
+			ejectMedia := func(ctx context.Context, client remote.Client) (string, error) {
+				return "", client.EjectVirtualMedia(ctx)
 			}
 
-			return nil
+			results, err := m.Execute("eject media", concurrency, ejectMedia)
+			printHostResults(cmd.OutOrStdout(), results)
+
+			return err
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.StringVarP(&labels, flagLabel, flagLabelShort, "", flagLabelDescription)
-	flags.StringVarP(&name, flagName, flagNameShort, "", flagNameDescription)
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	hosts.register(cmd)
 	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
 
 	return cmd
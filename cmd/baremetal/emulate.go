@@ -0,0 +1,107 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+const (
+	flagBindAddress            = "bind-address"
+	flagBindAddressDescription = "Address to serve the emulated BMCs on instead of the host of their BMC addresses, " +
+		"e.g. 0.0.0.0"
+)
+
+// NewEmulateCommand provides a command to emulate the BMCs of baremetal hosts.
+func NewEmulateCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var bindAddress string
+	var phase string
+
+	hosts := hostSelectionFlags{allByDefault: true}
+	cmd := &cobra.Command{
+		Use:   "emulate",
+		Short: "Emulate the BMCs of baremetal hosts",
+		Long: `Emulate the BMCs of baremetal hosts, so that baremetal commands and remote direct can be exercised without
+lab hardware. A Redfish service is served at the BMC address of each selected baremetal host document until
+airshipctl is interrupted. Hosts whose BMC addresses share a host and port are served by the same endpoint.
+
+Each emulated BMC accepts the BMC credentials of its host, with basic authentication or a Redfish session, and
+provides a system with a power state, a boot override and a virtual CD/DVD device, all held in memory. Power changes
+take effect immediately. BIOS settings, firmware and hardware inventory are not emulated.
+
+BMC addresses must use the redfish+http, redfish+https or redfish scheme. HTTPS endpoints present a self-signed
+certificate, so the management configuration must set insecure to true.`,
+		Example: `
+# Emulate the BMCs of all hosts of the bootstrap phase, then power on the ephemeral host from another terminal
+airshipctl baremetal emulate
+airshipctl baremetal poweron -l airshipit.org/ephemeral-node=true
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := rejectDryRun(cmd, "with the emulate command, which makes no requests to hosts"); err != nil {
+				return err
+			}
+
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			e, err := remote.EmulateHosts(rootSettings, phase, bindAddress, selector)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if closeErr := e.Close(); closeErr != nil {
+					log.Printf("Unable to stop the BMC emulator: %v", closeErr)
+				}
+			}()
+
+			tw := util.NewTabWriter(cmd.OutOrStdout())
+			fmt.Fprintf(tw, "HOST\tBMC ADDRESS\n")
+			for _, host := range e.HostNames() {
+				fmt.Fprintf(tw, "%s\t%s\n", host, e.Address(host))
+			}
+			tw.Flush()
+
+			interrupts := make(chan os.Signal, 1)
+			signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+			defer signal.Stop(interrupts)
+
+			sig := <-interrupts
+			log.Printf("Received signal '%s'. Stopping the BMC emulator.", sig)
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&bindAddress, flagBindAddress, "", flagBindAddressDescription)
+	hosts.register(cmd)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+
+	return cmd
+}
@@ -0,0 +1,155 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	flagImageURL            = "image-url"
+	flagImageURLDescription = "URL of the firmware image to apply. The URL must be accessible to the BMC"
+
+	flagTarget            = "target"
+	flagTargetDescription = "ID of a firmware component to update, as reported by the firmware list command. May " +
+		"be repeated; the BMC selects the components to update from the image by default"
+
+	flagTimeout                    = "timeout"
+	flagFirmwareTimeoutDescription = "Time to wait for the firmware update of each host to finish"
+)
+
+// NewFirmwareCommand provides a command to manage the firmware of baremetal hosts.
+func NewFirmwareCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "firmware",
+		Short: "Manage the firmware of baremetal hosts",
+	}
+
+	cmd.AddCommand(NewFirmwareListCommand(rootSettings))
+	cmd.AddCommand(NewFirmwareUpdateCommand(rootSettings))
+
+	return cmd
+}
+
+// NewFirmwareListCommand provides a command to retrieve the firmware inventory of baremetal hosts.
+func NewFirmwareListCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var concurrency int
+	var hosts hostSelectionFlags
+	var output string
+	var phase string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the firmware components of baremetal hosts and their versions",
+		Long: `List the firmware components of baremetal hosts, e.g. their BIOS, BMC and NIC firmware, and their versions
+as reported by the firmware inventory of their BMCs. The output can be recorded as the firmware baseline of a site.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			_, done := closeOnExit(m)
+			defer done()
+
+			firmware, err := m.FirmwareInventory(concurrency)
+			if printErr := printDocument(cmd.OutOrStdout(), output, firmware); printErr != nil {
+				return printErr
+			}
+
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	hosts.register(cmd)
+	flags.StringVarP(&output, flagOutput, flagOutputShort, outputYAML, flagOutputDescription)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+
+	return cmd
+}
+
+// NewFirmwareUpdateCommand provides a command to update the firmware of baremetal hosts.
+func NewFirmwareUpdateCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var concurrency int
+	var imageURL string
+	var hosts hostSelectionFlags
+	var phase string
+	var targets []string
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update the firmware of baremetal hosts",
+		Long: `Instruct the BMCs of baremetal hosts to download a firmware image and apply it, and wait for the updates to
+finish. Some firmware components, e.g. the BIOS, are only updated on the next reboot of a host.`,
+		Example: `
+# Update the BIOS of the host node01
+airshipctl baremetal firmware update --image-url http://10.23.24.1/firmware/bios-2.8.1.exe --target BIOS \
+    --name node01
+
+# Update the firmware of every worker, two at a time
+airshipctl baremetal firmware update --image-url http://10.23.24.1/firmware/bundle.bin --concurrency 2 \
+    --labels airshipit.org/k8s-role=worker
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			_, done := closeOnExit(m)
+			defer done()
+
+			results, err := m.UpdateFirmware(concurrency, imageURL, targets, timeout)
+			printHostResults(cmd.OutOrStdout(), results)
+
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	flags.StringVar(&imageURL, flagImageURL, "", flagImageURLDescription)
+	hosts.register(cmd)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.StringArrayVar(&targets, flagTarget, nil, flagTargetDescription)
+	flags.DurationVar(&timeout, flagTimeout, remote.DefaultFirmwareUpdateTimeout, flagFirmwareTimeoutDescription)
+
+	err := cmd.MarkFlagRequired(flagImageURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return cmd
+}
@@ -0,0 +1,149 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+const (
+	flagHistoryNameDescription   = "Name of the baremetal host whose actions are listed"
+	flagHistoryOutputDescription = "Output format. One of: table, yaml, json"
+
+	flagSince            = "since"
+	flagSinceDescription = "List actions performed at or after this time, given as an RFC 3339 timestamp or as a " +
+		"duration before now, e.g. 24h"
+
+	flagUntil            = "until"
+	flagUntilDescription = "List actions performed at or before this time, given as an RFC 3339 timestamp or as a " +
+		"duration before now, e.g. 1h"
+)
+
+// NewHistoryCommand provides a command to list the actions recorded in the baremetal audit log.
+func NewHistoryCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var name string
+	var output string
+	var since string
+	var until string
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List the actions performed on baremetal hosts",
+		Long: `List the out-of-band actions performed on baremetal hosts by airshipctl, as recorded in the audit log kept in
+the airshipctl configuration directory. Each record holds the time of the action, the OS user and airshipctl context
+that performed it, the host and BMC address it was performed on, and its outcome.`,
+		Example: `
+# List the actions performed on host node-1 during the last day
+airshipctl baremetal history --name node-1 --since 24h
+
+# Export the actions performed during a maintenance window
+airshipctl baremetal history --since 2020-06-01T22:00:00Z --until 2020-06-02T02:00:00Z -o json
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := rejectDryRun(cmd, "with the history command, which makes no requests to hosts"); err != nil {
+				return err
+			}
+
+			if output != outputTable && output != outputYAML && output != outputJSON {
+				return fmt.Errorf("unsupported output format %q, must be one of: %s, %s, %s", output, outputTable,
+					outputYAML, outputJSON)
+			}
+
+			now := time.Now()
+			filter := remote.AuditFilter{HostName: name}
+
+			var err error
+			if filter.Since, err = parseHistoryTime(flagSince, since, now); err != nil {
+				return err
+			}
+
+			if filter.Until, err = parseHistoryTime(flagUntil, until, now); err != nil {
+				return err
+			}
+
+			path := remote.AuditLogPath(rootSettings)
+			if path == "" {
+				return fmt.Errorf("unable to locate the audit log: the airshipctl configuration path is not set")
+			}
+
+			records, err := remote.ReadAuditLog(path, filter)
+			if err != nil {
+				return err
+			}
+
+			if output != outputTable {
+				return printDocument(cmd.OutOrStdout(), output, records)
+			}
+
+			printAuditRecords(cmd.OutOrStdout(), records)
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&name, flagName, flagNameShort, "", flagHistoryNameDescription)
+	flags.StringVarP(&output, flagOutput, flagOutputShort, outputTable, flagHistoryOutputDescription)
+	flags.StringVar(&since, flagSince, "", flagSinceDescription)
+	flags.StringVar(&until, flagUntil, "", flagUntilDescription)
+
+	return cmd
+}
+
+// parseHistoryTime parses the value of a time flag, given either as an RFC 3339 timestamp or as a duration before
+// now. An empty value yields the zero time, which does not restrict the records listed.
+func parseHistoryTime(flag, value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid value %q for flag --%s, must be an RFC 3339 timestamp or a "+
+			"positive duration", value, flag)
+	}
+
+	return now.Add(-d), nil
+}
+
+// printAuditRecords writes a table describing each action recorded in the audit log.
+func printAuditRecords(out io.Writer, records []remote.AuditRecord) {
+	tw := util.NewTabWriter(out)
+	fmt.Fprintf(tw, "TIME\tUSER\tCONTEXT\tHOST\tBMC ADDRESS\tACTION\tRESULT\n")
+	for _, record := range records {
+		outcome := "OK"
+		if record.Outcome == remote.AuditFailed {
+			// Collapse multi-line BMC error messages so that each record occupies a single row
+			outcome = "FAILED: " + strings.Join(strings.Fields(record.Error), " ")
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", record.Timestamp.Local().Format(time.RFC3339), record.User,
+			record.Context, record.HostName, record.BMCAddress, record.Action, outcome)
+	}
+	tw.Flush()
+}
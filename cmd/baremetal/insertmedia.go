@@ -0,0 +1,105 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	flagEject            = "eject"
+	flagEjectDescription = "Eject media attached to the baremetal host before inserting the ISO image"
+
+	flagISOURL            = "iso-url"
+	flagISOURLDescription = "URL of the ISO image to insert. The URL must be accessible to the BMC"
+)
+
+// NewInsertMediaCommand provides a command to insert an ISO image into the virtual media device of a baremetal host.
+func NewInsertMediaCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var concurrency int
+	var eject bool
+	var isoURL string
+	var hosts hostSelectionFlags
+	var phase string
+
+	cmd := &cobra.Command{
+		Use:     "insertmedia",
+		Aliases: []string{"insert-media"},
+		Short:   "Insert an ISO image into the virtual media device of a baremetal host",
+		Long: `Insert an ISO image into the CD or DVD virtual media device of a baremetal host without changing its power
+state or boot device. The virtual media device that holds the image is reported for each host. Media already attached
+to a host is only ejected when --eject is set; otherwise, inserting the image into an occupied device fails.`,
+		Example: `
+# Attach a recovery ISO image to the host node01, replacing any attached media
+airshipctl baremetal insertmedia --iso-url http://10.23.24.1:8099/recovery.iso --eject --name node01
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			_, done := closeOnExit(m)
+			defer done()
+
+			insertMedia := func(ctx context.Context, client remote.Client) (string, error) {
+				if eject {
+					if err := client.EjectVirtualMedia(ctx); err != nil {
+						return "", err
+					}
+				}
+
+				mediaID, err := client.InsertVirtualMedia(ctx, isoURL)
+				if err != nil {
+					return "", err
+				}
+
+				return fmt.Sprintf("inserted into virtual media '%s'", mediaID), nil
+			}
+
+			results, err := m.Execute("insert media", concurrency, insertMedia)
+			printHostResults(cmd.OutOrStdout(), results)
+
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	flags.BoolVar(&eject, flagEject, false, flagEjectDescription)
+	flags.StringVar(&isoURL, flagISOURL, "", flagISOURLDescription)
+	hosts.register(cmd)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+
+	err := cmd.MarkFlagRequired(flagISOURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return cmd
+}
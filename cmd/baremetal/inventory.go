@@ -0,0 +1,83 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+)
+
+const (
+	flagCheckBootMAC            = "check-boot-mac"
+	flagCheckBootMACDescription = "Fail when the boot MAC address of a baremetal host document does not match any of " +
+		"the host's NICs"
+)
+
+// NewInventoryCommand provides a command to retrieve the hardware inventory of baremetal hosts.
+func NewInventoryCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var checkBootMAC bool
+	var concurrency int
+	var hosts hostSelectionFlags
+	var output string
+	var phase string
+
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Retrieve the hardware inventory of baremetal hosts",
+		Long: `Retrieve the processors, memory, NICs and storage of baremetal hosts from their BMCs. Hosts whose NICs do
+not include the boot MAC address of their baremetal host document are flagged with bootMACAddressMismatch.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			_, done := closeOnExit(m)
+			defer done()
+
+			inventories, err := m.Inventory(concurrency)
+			if printErr := printDocument(cmd.OutOrStdout(), output, inventories); printErr != nil {
+				return printErr
+			}
+
+			if err != nil {
+				return err
+			}
+
+			if checkBootMAC {
+				return remote.CheckBootMACAddresses(inventories)
+			}
+
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&checkBootMAC, flagCheckBootMAC, false, flagCheckBootMACDescription)
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	hosts.register(cmd)
+	flags.StringVarP(&output, flagOutput, flagOutputShort, outputYAML, flagOutputDescription)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+
+	return cmd
+}
@@ -15,7 +15,7 @@
 package baremetal
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/spf13/cobra"
 
@@ -24,38 +24,59 @@ import (
 	"opendev.org/airship/airshipctl/pkg/remote"
 )
 
+const (
+	flagGraceful                    = "graceful"
+	flagGracefulPowerOffDescription = "Shut down the operating system of the hosts before forcing them off"
+)
+
 // NewPowerOffCommand provides a command to shutdown a remote host.
 func NewPowerOffCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
-	var labels string
-	var name string
+	var concurrency int
+	var graceful bool
+	var hosts hostSelectionFlags
 	var phase string
 
 	cmd := &cobra.Command{
 		Use:   "poweroff",
 		Short: "Shutdown a baremetal host",
-		Args:  cobra.NoArgs,
+		Long: `Shut down baremetal hosts. By default, hosts are forced off immediately. With --graceful, the operating system
+of each host is asked to shut down first, and the host is only forced off when it is still powered on once the
+gracefulShutdownTimeout of the management configuration expires. Reset types that the system of a host does not
+advertise as supported are not used.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			selectors := GetHostSelections(name, labels)
-			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			selector, err := hosts.selector()
 			if err != nil {
 				return err
 			}
 
-			for _, host := range m.Hosts {
-				if err := host.SystemPowerOff(host.Context); err != nil {
-					return err
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			_, done := closeOnExit(m)
+			defer done()
+
+			timeout := m.Config.ShutdownTimeout()
+			powerOff := func(ctx context.Context, client remote.Client) (string, error) {
+				if graceful {
+					return "", client.SystemPowerOffGraceful(ctx, timeout)
 				}
 
-				fmt.Fprintf(cmd.OutOrStdout(), "Powered off host '%s'.\n", host.HostName)
+				return "", client.SystemPowerOff(ctx)
 			}
 
-			return nil
+			results, err := m.Execute("power off", concurrency, powerOff)
+			printHostResults(cmd.OutOrStdout(), results)
+
+			return err
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.StringVarP(&labels, flagLabel, flagLabelShort, "", flagLabelDescription)
-	flags.StringVarP(&name, flagName, flagNameShort, "", flagNameDescription)
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	flags.BoolVar(&graceful, flagGraceful, false, flagGracefulPowerOffDescription)
+	hosts.register(cmd)
 	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
 
 	return cmd
@@ -15,7 +15,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 package baremetal
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/spf13/cobra"
 
@@ -26,8 +26,8 @@ import (
 
 // NewPowerOnCommand provides a command with the capability to power on baremetal hosts.
 func NewPowerOnCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
-	var labels string
-	var name string
+	var concurrency int
+	var hosts hostSelectionFlags
 	var phase string
 
 	cmd := &cobra.Command{
@@ -35,27 +35,32 @@ func NewPowerOnCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Comm
 		Short: "Power on a host",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			selectors := GetHostSelections(name, labels)
-			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			selector, err := hosts.selector()
 			if err != nil {
 				return err
 			}
 
-			for _, host := range m.Hosts {
-				if err := host.SystemPowerOn(host.Context); err != nil {
-					return err
-				}
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			_, done := closeOnExit(m)
+			defer done()
 
-				fmt.Fprintf(cmd.OutOrStdout(), "Powered on host '%s'.\n", host.HostName)
+			powerOn := func(ctx context.Context, client remote.Client) (string, error) {
+				return "", client.SystemPowerOn(ctx)
 			}
 
-			return nil
+			results, err := m.Execute("power on", concurrency, powerOn)
+			printHostResults(cmd.OutOrStdout(), results)
+
+			return err
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.StringVarP(&labels, flagLabel, flagLabelShort, "", flagLabelDescription)
-	flags.StringVarP(&name, flagName, flagNameShort, "", flagNameDescription)
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	hosts.register(cmd)
 	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
 
 	return cmd
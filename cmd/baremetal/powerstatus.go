@@ -15,50 +15,169 @@
 package baremetal
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
 	"opendev.org/airship/airshipctl/pkg/remote"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+const (
+	flagInterval            = "interval"
+	flagIntervalDescription = "Interval between polls of the power status of the hosts when watching them"
+
+	flagWatch            = "watch"
+	flagWatchDescription = "Keep polling the power status of the hosts and print each change of their power status"
+
+	flagPowerStatusOutputDescription = "Output format. One of: text, table, json, yaml. With --watch, one of: text, " +
+		"json (one JSON object per line)"
 )
 
 // NewPowerStatusCommand provides a command to retrieve the power status of a baremetal host.
 func NewPowerStatusCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
-	var labels string
-	var name string
+	var concurrency int
+	var interval time.Duration
+	var hosts hostSelectionFlags
+	var output string
 	var phase string
+	var watch bool
 
 	cmd := &cobra.Command{
 		Use:   "powerstatus",
 		Short: "Retrieve the power status of a baremetal host",
-		Args:  cobra.NoArgs,
+		Long: `Retrieve the power status of baremetal hosts. With --output table, json or yaml, a record is printed for each
+selected host holding its name, BMC address, node ID, and its power status or the reason it could not be retrieved.
+With --watch, the power status of the hosts is polled until the command is interrupted, and only changes of their power
+status are printed, starting with the status first observed for each host. An interrupted watch exits successfully.`,
+		Example: `
+# Retrieve the power status of every control plane host as JSON, for consumption by scripts
+airshipctl baremetal powerstatus --labels airshipit.org/k8s-role=controlplane-host -o json
+
+# Follow the power status of every worker during a maintenance window, for consumption by a dashboard
+airshipctl baremetal powerstatus --labels airshipit.org/k8s-role=worker --watch --interval 30s -o json
+`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			selectors := GetHostSelections(name, labels)
-			m, err := remote.NewManager(rootSettings, phase, selectors...)
-			if err != nil {
+			if err := validateStatusOutput(output, watch); err != nil {
 				return err
 			}
 
-			for _, host := range m.Hosts {
-				powerStatus, err := host.SystemPowerStatus(host.Context)
-				if err != nil {
+			if watch {
+				if err := rejectDryRun(cmd, "with --"+flagWatch); err != nil {
 					return err
 				}
+			}
+
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			m, err := newManager(cmd, rootSettings, phase, selector)
+			if err != nil {
+				return err
+			}
+			ctx, done := closeOnExit(m)
+			defer done()
+
+			if watch {
+				out := cmd.OutOrStdout()
+				m.WatchPowerStatus(ctx, concurrency, interval, func(t remote.PowerTransition) {
+					if printErr := printPowerTransition(out, output, t); printErr != nil {
+						log.Printf("Unable to print power status change: %v", printErr)
+					}
+				})
+
+				return nil
+			}
 
-				fmt.Fprintf(cmd.OutOrStdout(), "Host '%s' has power status: '%s'\n",
-					host.HostName, powerStatus)
+			statuses, err := m.PowerStatus(concurrency)
+			if printErr := printPowerStatus(cmd.OutOrStdout(), output, statuses); printErr != nil {
+				return printErr
 			}
 
-			return nil
+			return err
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.StringVarP(&labels, flagLabel, flagLabelShort, "", flagLabelDescription)
-	flags.StringVarP(&name, flagName, flagNameShort, "", flagNameDescription)
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	flags.DurationVar(&interval, flagInterval, remote.DefaultPowerWatchInterval, flagIntervalDescription)
+	hosts.register(cmd)
+	flags.StringVarP(&output, flagOutput, flagOutputShort, outputText, flagPowerStatusOutputDescription)
 	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+	flags.BoolVar(&watch, flagWatch, false, flagWatchDescription)
 
 	return cmd
 }
+
+// validateStatusOutput verifies that the status of hosts, e.g. their power status, can be printed in format. The
+// changes of power status printed by --watch are streamed as they happen, and can only be printed as lines of text or
+// of JSON.
+func validateStatusOutput(format string, watch bool) error {
+	switch {
+	case watch && format != outputText && format != outputJSON:
+		return fmt.Errorf("unsupported output format %q with --%s, must be one of: %s, %s", format, flagWatch,
+			outputText, outputJSON)
+	case format != outputText && format != outputTable && format != outputJSON && format != outputYAML:
+		return fmt.Errorf("unsupported output format %q, must be one of: %s, %s, %s, %s", format, outputText,
+			outputTable, outputJSON, outputYAML)
+	}
+
+	return nil
+}
+
+// printPowerStatus writes the power status of each baremetal host as a line of text, a table row, or a record of a
+// YAML or JSON document. Lines of text are only written for the hosts whose power status was retrieved.
+func printPowerStatus(out io.Writer, format string, statuses []remote.HostPowerStatus) error {
+	switch format {
+	case outputText:
+		for _, status := range statuses {
+			if status.Error == "" {
+				fmt.Fprintf(out, "Host '%s' has power status: '%s'\n", status.HostName, status.PowerState)
+			}
+		}
+	case outputTable:
+		tw := util.NewTabWriter(out)
+		fmt.Fprintf(tw, "HOST\tBMC ADDRESS\tNODE ID\tPOWER STATE\tERROR\n")
+		for _, status := range statuses {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", status.HostName, status.BMCAddress, status.NodeID,
+				status.PowerState, status.Error)
+		}
+		tw.Flush()
+	default:
+		return printDocument(out, format, statuses)
+	}
+
+	return nil
+}
+
+// printPowerTransition writes a change in the power status of a baremetal host as a line of text or of JSON.
+func printPowerTransition(out io.Writer, format string, t remote.PowerTransition) error {
+	if format == outputJSON {
+		data, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	}
+
+	timestamp := t.Time.Format(time.RFC3339)
+	if t.OldStatus == "" {
+		_, err := fmt.Fprintf(out, "%s Host '%s' has power status: '%s'\n", timestamp, t.HostName, t.NewStatus)
+		return err
+	}
+
+	_, err := fmt.Fprintf(out, "%s Host '%s' power status changed from '%s' to '%s'\n", timestamp, t.HostName,
+		t.OldStatus, t.NewStatus)
+	return err
+}
@@ -15,7 +15,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 package baremetal
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/spf13/cobra"
 
@@ -24,38 +24,58 @@ import (
 	"opendev.org/airship/airshipctl/pkg/remote"
 )
 
+const flagGracefulRebootDescription = "Restart the operating system of the hosts gracefully, or shut it down before " +
+	"forcing the hosts off and powering them on"
+
 // NewRebootCommand provides a command with the capability to reboot baremetal hosts.
 func NewRebootCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
-	var labels string
-	var name string
+	var concurrency int
+	var graceful bool
+	var hosts hostSelectionFlags
 	var phase string
 
 	cmd := &cobra.Command{
 		Use:   "reboot",
 		Short: "Reboot a host",
-		Args:  cobra.NoArgs,
+		Long: `Reboot baremetal hosts by powering them off and on again. By default, hosts are forced off immediately. With
+--graceful, the operating system of a host whose system advertises the GracefulRestart reset type is asked to restart.
+The operating system of other hosts is asked to shut down first, and the host is only forced off when it is still
+powered on once the gracefulShutdownTimeout of the management configuration expires. Reset types that the system of a
+host does not advertise as supported are not used.`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			selectors := GetHostSelections(name, labels)
-			m, err := remote.NewManager(rootSettings, phase, selectors...)
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			m, err := newManager(cmd, rootSettings, phase, selector)
 			if err != nil {
 				return err
 			}
+			_, done := closeOnExit(m)
+			defer done()
 
-			for _, host := range m.Hosts {
-				if err := host.RebootSystem(host.Context); err != nil {
-					return err
+			timeout := m.Config.ShutdownTimeout()
+			reboot := func(ctx context.Context, client remote.Client) (string, error) {
+				if graceful {
+					return "", client.RebootSystemGraceful(ctx, timeout)
 				}
 
-				fmt.Fprintf(cmd.OutOrStdout(), "Rebooted host '%s'.\n", host.HostName)
+				return "", client.RebootSystem(ctx)
 			}
 
-			return nil
+			results, err := m.Execute("reboot", concurrency, reboot)
+			printHostResults(cmd.OutOrStdout(), results)
+
+			return err
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.StringVarP(&labels, flagLabel, flagLabelShort, "", flagLabelDescription)
-	flags.StringVarP(&name, flagName, flagNameShort, "", flagNameDescription)
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	flags.BoolVar(&graceful, flagGraceful, false, flagGracefulRebootDescription)
+	hosts.register(cmd)
 	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
 
 	return cmd
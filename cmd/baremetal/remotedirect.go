@@ -15,35 +15,111 @@
 package baremetal
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/log"
 	"opendev.org/airship/airshipctl/pkg/remote"
 )
 
+const (
+	flagRemoteDirectEjectDescription = "Eject the virtual media of the ephemeral host once the ephemeral cluster is " +
+		"ready. Requires --wait"
+	flagRemoteDirectTimeoutDescription = "Time to wait for the ephemeral cluster to become ready"
+
+	flagWait            = "wait"
+	flagWaitDescription = "Wait for the API server of the ephemeral cluster to answer and its nodes to report Ready"
+)
+
 // NewRemoteDirectCommand provides a command with the capability to perform remote direct operations.
 func NewRemoteDirectCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var eject bool
+	var timeout time.Duration
+	var wait bool
+
 	cmd := &cobra.Command{
 		Use:   "remotedirect",
 		Short: "Bootstrap the ephemeral host",
+		Long: `Bootstrap the ephemeral host by booting it from the ephemeral ISO image over virtual media. When --wait is
+set, the command only returns once the API server of the ephemeral cluster, as defined by the current airship
+kubeconfig, answers and every node of the cluster reports Ready.
+
+When the isoServer options of the remoteDirect bootstrap configuration are set, airshipctl serves the ISO image
+itself and hands its URL to the BMC instead of the configured isoUrl. The image is then served until the ephemeral
+cluster is ready, as if --wait was set.
+
+With --dry-run, the requests that would be sent to the BMC of the ephemeral host are printed instead, and the
+ephemeral cluster is not waited for. The ISO image is not served; the requests hold the URL it would be served at.`,
+		Example: `
+# Bootstrap the ephemeral host, wait up to an hour for the ephemeral cluster and eject the ISO image
+airshipctl baremetal remotedirect --wait --timeout 1h --eject
+`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			manager, err := remote.NewManager(rootSettings,
-				config.BootstrapPhase,
+			manager, err := newManager(cmd, rootSettings, config.BootstrapPhase,
 				remote.ByLabel(document.EphemeralHostSelector))
 			if err != nil {
 				return err
 			}
+			_, done := closeOnExit(manager)
+			defer done()
 
 			if len(manager.Hosts) != 1 {
 				return remote.NewRemoteDirectErrorf("more than one node defined as the ephemeral node")
 			}
 
 			ephemeralHost := manager.Hosts[0]
-			return ephemeralHost.DoRemoteDirect(rootSettings)
+			isoServer, err := remote.ServeEphemeralISO(rootSettings, ephemeralHost.DryRun())
+			if err != nil {
+				return err
+			}
+
+			if isoServer != nil && !ephemeralHost.DryRun() {
+				defer func() {
+					if closeErr := isoServer.Close(); closeErr != nil {
+						log.Printf("Unable to stop serving the ISO image: %v", closeErr)
+					}
+				}()
+
+				// The ephemeral host reads the image while it boots, so it is served until the cluster is ready
+				wait = true
+			}
+
+			if eject && !wait {
+				return fmt.Errorf("flag --%s requires --%s", flagEject, flagWait)
+			}
+
+			err = ephemeralHost.DoRemoteDirect(rootSettings, isoServer)
+			if ephemeralHost.DryRun() {
+				printDryRunCalls(cmd.OutOrStdout(), remote.HostResult{
+					HostName:          ephemeralHost.HostName,
+					BMCAddress:        ephemeralHost.BMCAddress,
+					Calls:             ephemeralHost.DryRunCalls(),
+					ManagementType:    ephemeralHost.ManagementType,
+					CredentialsSource: ephemeralHost.CredentialsSource,
+				})
+				return err
+			}
+
+			if err != nil || !wait {
+				return err
+			}
+
+			policy := manager.Config.RetryPolicy()
+			policy.Timeout = timeout
+			return ephemeralHost.WaitForEphemeralCluster(rootSettings, client.DefaultClient, policy, eject)
 		},
 	}
 
+	flags := cmd.Flags()
+	flags.BoolVar(&eject, flagEject, false, flagRemoteDirectEjectDescription)
+	flags.DurationVar(&timeout, flagTimeout, remote.DefaultEphemeralClusterTimeout, flagRemoteDirectTimeoutDescription)
+	flags.BoolVar(&wait, flagWait, false, flagWaitDescription)
+
 	return cmd
 }
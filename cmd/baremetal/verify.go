@@ -0,0 +1,88 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package baremetal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote"
+	"opendev.org/airship/airshipctl/pkg/util"
+)
+
+// NewVerifyCommand provides a command to verify that baremetal hosts are ready for remote direct.
+func NewVerifyCommand(rootSettings *environment.AirshipCTLSettings) *cobra.Command {
+	var concurrency int
+	var phase string
+
+	hosts := hostSelectionFlags{allByDefault: true}
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify that baremetal hosts are ready for remote direct",
+		Long: `Verify that the BMC credentials of baremetal hosts resolve, that their BMCs are reachable and accept the
+credentials, and that their BMCs provide a virtual media device that can boot an ISO image. A pass/fail matrix is
+printed for the selected hosts, followed by the reason for each failed check. The command fails when any check fails
+on any host.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := rejectDryRun(cmd, "with the verify command, which only retrieves information"); err != nil {
+				return err
+			}
+
+			selector, err := hosts.selector()
+			if err != nil {
+				return err
+			}
+
+			results, err := remote.Verify(rootSettings, phase, selector, concurrency)
+			if len(results) > 0 {
+				printVerification(cmd.OutOrStdout(), results)
+			}
+
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&concurrency, flagConcurrency, remote.DefaultConcurrency, flagConcurrencyDescription)
+	hosts.register(cmd)
+	flags.StringVar(&phase, flagPhase, config.BootstrapPhase, flagPhaseDescription)
+
+	return cmd
+}
+
+// printVerification writes a matrix of the outcome of each verification check performed on each baremetal host.
+func printVerification(out io.Writer, results []remote.HostVerification) {
+	tw := util.NewTabWriter(out)
+	fmt.Fprint(tw, "HOST\tBMC ADDRESS")
+	for _, check := range remote.Checks {
+		fmt.Fprintf(tw, "\t%s", strings.ToUpper(strings.Replace(check, "-", " ", -1)))
+	}
+	fmt.Fprintln(tw)
+
+	for _, result := range results {
+		fmt.Fprintf(tw, "%s\t%s", result.HostName, result.BMCAddress)
+		for _, check := range remote.Checks {
+			fmt.Fprintf(tw, "\t%s", result.Check(check).Status)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
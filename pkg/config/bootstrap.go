@@ -52,6 +52,22 @@ type Builder struct {
 type RemoteDirect struct {
 	// IsoURL specifies url to download ISO image for ephemeral node
 	IsoURL string `json:"isoUrl,omitempty"`
+	// IsoServer configures the HTTP server started by airshipctl to serve the ephemeral ISO image to the BMC
+	// during remote direct. When set, the URL of the served image is used instead of IsoURL
+	IsoServer *IsoServer `json:"isoServer,omitempty"`
+}
+
+// IsoServer configuration options
+type IsoServer struct {
+	// BindAddress specifies the address the server listens on. The server listens on all addresses by default
+	BindAddress string `json:"bindAddress,omitempty"`
+	// Port specifies the port the server listens on. Defaults to 8099
+	Port int `json:"port,omitempty"`
+	// AdvertiseAddress specifies the address the BMC uses to reach the server. Defaults to the bind address or,
+	// when the server listens on all addresses, to the local address used to reach the BMC
+	AdvertiseAddress string `json:"advertiseAddress,omitempty"`
+	// IsoPath specifies the path of the ISO image to serve. Defaults to the image built in the container volume
+	IsoPath string `json:"isoPath,omitempty"`
 }
 
 // Bootstrap functions
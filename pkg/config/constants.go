@@ -36,6 +36,7 @@ var AllClusterTypes = [2]string{Ephemeral, Target}
 
 // Constants defining default values
 const (
+	AirshipAuditLog                       = "baremetal-audit.log"
 	AirshipConfig                         = "config"
 	AirshipConfigAPIVersion               = AirshipConfigGroup + "/" + AirshipConfigVersion
 	AirshipConfigDir                      = ".airship"
@@ -57,6 +58,8 @@ const (
 	// Modules
 	AirshipDefaultBootstrapImage = "quay.io/airshipit/isogen:latest-debian_stable"
 	AirshipDefaultIsoURL         = "http://localhost:8099/debian-custom.iso"
+	AirshipDefaultIsoName        = "debian-custom.iso"
+	AirshipDefaultIsoServerPort  = 8099
 	AirshipDefaultManagementType = redfish.ClientType
 )
 
@@ -64,4 +67,20 @@ const (
 const (
 	DefaultSystemActionRetries = 30
 	DefaultSystemRebootDelay   = 30
+
+	// Seconds to wait for a remote operation to complete, and between polls of its status
+	DefaultOperationTimeout  = 900
+	DefaultPollInterval      = 2
+	DefaultMaxPollInterval   = 30
+	DefaultBackoffMultiplier = 2.0
+	DefaultJitter            = 0.1
+
+	// Seconds to wait for the operating system of a host to shut down gracefully before forcing the host off
+	DefaultGracefulShutdownTimeout = 300
+
+	// Seconds to wait for the command of the exec credential provider to print the BMC credentials of a host
+	DefaultCredentialsExecTimeout = 30
+
+	// Prefix of the environment variables read by the env credential provider
+	DefaultCredentialsEnvPrefix = "AIRSHIP_BMC"
 )
@@ -18,7 +18,9 @@ import (
 	"fmt"
 	"strings"
 
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish/vendors"
 	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
 )
 
@@ -199,6 +201,36 @@ type ErrUnknownManagementType struct {
 }
 
 func (e ErrUnknownManagementType) Error() string {
-	return fmt.Sprintf("Unknown management type '%s'. Known types include '%s' and '%s'.", e.Type,
-		redfish.ClientType, redfishdell.ClientType)
+	return fmt.Sprintf("Unknown management type '%s'. Known types include '%s', '%s', '%s' and '%s'.", e.Type,
+		redfish.ClientType, redfishdell.ClientType, ipmi.ClientType, vendors.ClientType)
+}
+
+// ErrInvalidRetryConfiguration describes a retry configuration option with a value that is out of range.
+type ErrInvalidRetryConfiguration struct {
+	Option string
+	Reason string
+}
+
+func (e ErrInvalidRetryConfiguration) Error() string {
+	return fmt.Sprintf("Invalid retry configuration option '%s': %s.", e.Option, e.Reason)
+}
+
+// ErrInvalidCredentialsConfiguration describes a credentials configuration option with an invalid or missing value.
+type ErrInvalidCredentialsConfiguration struct {
+	Option string
+	Reason string
+}
+
+func (e ErrInvalidCredentialsConfiguration) Error() string {
+	return fmt.Sprintf("Invalid credentials configuration option '%s': %s.", e.Option, e.Reason)
+}
+
+// ErrInvalidTLSConfiguration describes a TLS configuration option with an invalid or missing value.
+type ErrInvalidTLSConfiguration struct {
+	Option string
+	Reason string
+}
+
+func (e ErrInvalidTLSConfiguration) Error() string {
+	return fmt.Sprintf("Invalid TLS configuration option '%s': %s.", e.Option, e.Reason)
 }
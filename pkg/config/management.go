@@ -15,10 +15,17 @@
 package config
 
 import (
+	"crypto/tls"
+	"fmt"
+	"time"
+
 	"sigs.k8s.io/yaml"
 
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish/vendors"
 	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
 )
 
 const (
@@ -26,18 +33,57 @@ const (
 	useProxyDefaultValue = false
 )
 
+// Providers from which the BMC credentials of hosts can be read
+const (
+	// CredentialProviderSecret reads credentials from the Secret document a baremetal host document references.
+	CredentialProviderSecret = "secret"
+	// CredentialProviderEnv reads credentials from environment variables.
+	CredentialProviderEnv = "env"
+	// CredentialProviderFile reads credentials from a local file or directory.
+	CredentialProviderFile = "file"
+	// CredentialProviderExec reads credentials from the output of an external command.
+	CredentialProviderExec = "exec"
+)
+
 // ManagementConfiguration defines configuration data for all remote systems within a context.
 type ManagementConfiguration struct {
+	// Credentials configures where the BMC credentials of hosts are read from. By default, they are read from the
+	// Secret documents referenced by baremetal host documents.
+	Credentials *CredentialsConfiguration `json:"credentials,omitempty"`
+
+	// GracefulShutdownTimeout is the number of seconds to wait for the operating system of a host to shut down when a
+	// graceful shutdown is requested, before the host is forced off.
+	GracefulShutdownTimeout int `json:"gracefulShutdownTimeout,omitempty"`
+
 	// Insecure indicates whether the SSL certificate should be checked on remote management requests.
 	Insecure bool `json:"insecure,omitempty"`
 
+	// TLS configures how the certificates of BMCs are verified and the client certificate presented to BMCs, as an
+	// alternative to disabling verification with Insecure. It applies to Redfish management types.
+	TLS *TLSConfiguration `json:"tls,omitempty"`
+
 	// SystemActionRetries is the number of attempts to poll a host for a status.
+	// Deprecated: use Retry. When no retry timeout is configured, the timeout is SystemActionRetries times
+	// SystemRebootDelay seconds.
 	SystemActionRetries int `json:"systemActionRetries,omitempty"`
 
 	// SystemRebootDelay is the number of seconds to wait between power actions (e.g. shutdown, startup).
+	// Deprecated: use Retry. When it differs from DefaultSystemRebootDelay and no retry poll interval is configured,
+	// hosts are first polled again after SystemRebootDelay seconds, as they were before Retry was introduced.
 	SystemRebootDelay int `json:"systemRebootDelay,omitempty"`
 
-	// Type the type of out-of-band management that will be used for baremetal orchestration, e.g. redfish.
+	// Retry configures how long and how often hosts are polled until an operation, e.g. a power state change, ejecting
+	// virtual media or applying a boot setting, completes.
+	Retry *RetryConfiguration `json:"retry,omitempty"`
+
+	// SessionAuth indicates whether Redfish requests should be authenticated with a session token obtained from the
+	// BMC's SessionService instead of sending basic authentication credentials with every request. The vendor of the
+	// BMCs of hosts with the auto management type is always detected with basic authentication credentials.
+	SessionAuth bool `json:"sessionAuth,omitempty"`
+
+	// Type the type of out-of-band management that will be used for baremetal orchestration, e.g. redfish. BMC
+	// addresses whose scheme names a driver, e.g. ipmi:// or idrac-virtualmedia://, select the client of a host
+	// regardless; Type applies to hosts whose scheme names a protocol only, e.g. redfish+https://.
 	Type string `json:"type"`
 
 	// UseProxy indicates whether airshipctl should transmit remote management requests through a proxy server when
@@ -45,6 +91,140 @@ type ManagementConfiguration struct {
 	UseProxy bool `json:"useproxy,omitempty"`
 }
 
+// CredentialsConfiguration defines where the BMC credentials of hosts are read from. Omitted fields take default
+// values.
+type CredentialsConfiguration struct {
+	// Provider is the source of the credentials. One of: secret, env, file, exec. Defaults to secret.
+	Provider string `json:"provider,omitempty"`
+
+	// EnvPrefix is the prefix of the environment variables read by the env provider. Defaults to AIRSHIP_BMC.
+	EnvPrefix string `json:"envPrefix,omitempty"`
+
+	// Path is the file or directory read by the file provider.
+	Path string `json:"path,omitempty"`
+
+	// Command is the executable, followed by its arguments, run by the exec provider.
+	Command []string `json:"command,omitempty"`
+
+	// Timeout is the number of seconds the exec provider waits for its command to complete.
+	Timeout int `json:"timeout,omitempty"`
+}
+
+// TLSConfiguration defines the certificates used to secure connections to BMCs. Paths are local to airshipctl.
+type TLSConfiguration struct {
+	// CABundle is the path of a PEM file of the CA certificates trusted to sign BMC certificates, replacing the CAs
+	// trusted by the system.
+	CABundle string `json:"caBundle,omitempty"`
+
+	// ClientCertificate is the path of a PEM certificate presented to BMCs that require client authentication.
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+
+	// ClientKey is the path of the PEM private key of the client certificate.
+	ClientKey string `json:"clientKey,omitempty"`
+
+	// PinnedFingerprints are the SHA-256 fingerprints of the certificates BMCs may present, e.g. as printed by
+	// "openssl x509 -fingerprint -sha256". A BMC presenting a pinned certificate is trusted even when the certificate
+	// is self-signed, and neither its chain nor its host name is verified; pins therefore cannot be combined with
+	// CABundle.
+	PinnedFingerprints []string `json:"pinnedFingerprints,omitempty"`
+}
+
+// RetryConfiguration defines how remote operations are polled until they complete. Omitted fields take default values.
+type RetryConfiguration struct {
+	// Timeout is the number of seconds to wait for an operation to complete.
+	Timeout int `json:"timeout,omitempty"`
+
+	// PollInterval is the number of seconds to wait before polling an operation again for the first time.
+	PollInterval int `json:"pollInterval,omitempty"`
+
+	// MaxPollInterval is the maximum number of seconds to wait between polls.
+	MaxPollInterval int `json:"maxPollInterval,omitempty"`
+
+	// BackoffMultiplier is the factor applied to the poll interval after every poll. A value of 1 polls at a constant
+	// interval.
+	BackoffMultiplier float64 `json:"backoffMultiplier,omitempty"`
+
+	// Jitter is the fraction, between 0 and 1, by which each poll interval is randomly lengthened or shortened.
+	Jitter *float64 `json:"jitter,omitempty"`
+}
+
+// RetryPolicy returns the policy used to poll hosts until remote operations complete. Options that are not configured
+// take default values; the timeout is derived from SystemActionRetries and SystemRebootDelay, and the poll interval
+// from a SystemRebootDelay other than the default, when they are not set.
+func (m *ManagementConfiguration) RetryPolicy() retry.Policy {
+	policy := retry.Policy{
+		Timeout:     DefaultOperationTimeout * time.Second,
+		Interval:    DefaultPollInterval * time.Second,
+		MaxInterval: DefaultMaxPollInterval * time.Second,
+		Multiplier:  DefaultBackoffMultiplier,
+		Jitter:      DefaultJitter,
+	}
+
+	if m.SystemActionRetries > 0 && m.SystemRebootDelay > 0 {
+		policy.Timeout = time.Duration(m.SystemActionRetries*m.SystemRebootDelay) * time.Second
+	}
+
+	// The default reboot delay is set on every new configuration, so only a customized delay is taken as the interval
+	// the hosts should be polled at
+	if m.SystemRebootDelay > 0 && m.SystemRebootDelay != DefaultSystemRebootDelay &&
+		(m.Retry == nil || m.Retry.PollInterval == 0) {
+		policy.Interval = time.Duration(m.SystemRebootDelay) * time.Second
+		if policy.MaxInterval < policy.Interval {
+			policy.MaxInterval = policy.Interval
+		}
+	}
+
+	if m.Retry == nil {
+		return policy
+	}
+
+	if m.Retry.Timeout > 0 {
+		policy.Timeout = time.Duration(m.Retry.Timeout) * time.Second
+	}
+
+	if m.Retry.PollInterval > 0 {
+		policy.Interval = time.Duration(m.Retry.PollInterval) * time.Second
+	}
+
+	if m.Retry.MaxPollInterval > 0 {
+		policy.MaxInterval = time.Duration(m.Retry.MaxPollInterval) * time.Second
+	}
+
+	if m.Retry.BackoffMultiplier > 0 {
+		policy.Multiplier = m.Retry.BackoffMultiplier
+	}
+
+	if m.Retry.Jitter != nil {
+		policy.Jitter = *m.Retry.Jitter
+	}
+
+	return policy
+}
+
+// TLSConfig returns the TLS configuration used to connect to BMCs, reading the CA bundle and client certificate files
+// the management configuration refers to.
+func (m *ManagementConfiguration) TLSConfig() (*tls.Config, error) {
+	opts := redfish.TLSOptions{Insecure: m.Insecure}
+	if m.TLS != nil {
+		opts.CABundle = m.TLS.CABundle
+		opts.ClientCertificate = m.TLS.ClientCertificate
+		opts.ClientKey = m.TLS.ClientKey
+		opts.PinnedFingerprints = m.TLS.PinnedFingerprints
+	}
+
+	return redfish.NewTLSConfig(opts)
+}
+
+// ShutdownTimeout returns how long to wait for the operating system of a host to shut down gracefully before the host
+// is forced off.
+func (m *ManagementConfiguration) ShutdownTimeout() time.Duration {
+	if m.GracefulShutdownTimeout > 0 {
+		return time.Duration(m.GracefulShutdownTimeout) * time.Second
+	}
+
+	return DefaultGracefulShutdownTimeout * time.Second
+}
+
 // SetType is a helper function that sets and validates the management type.
 func (m *ManagementConfiguration) SetType(managementType string) error {
 	prev := m.Type
@@ -68,14 +248,31 @@ func (m *ManagementConfiguration) String() string {
 	return string(yamlData)
 }
 
-// Validate validates that a management configuration is valid. Currently, this only checks the value of the management
-// type as the other fields have appropriate zero values and may be omitted.
+// Validate validates that a management configuration is valid. Currently, this checks the value of the management type
+// and the credentials, TLS and retry configurations as the other fields have appropriate zero values and may be
+// omitted.
 func (m *ManagementConfiguration) Validate() error {
+	if err := m.Credentials.Validate(); err != nil {
+		return err
+	}
+
+	if err := m.TLS.Validate(m.Insecure); err != nil {
+		return err
+	}
+
+	if err := m.Retry.Validate(); err != nil {
+		return err
+	}
+
 	switch m.Type {
 	case redfish.ClientType:
 		m.Type = redfish.ClientType
 	case redfishdell.ClientType:
 		m.Type = redfishdell.ClientType
+	case ipmi.ClientType:
+		m.Type = ipmi.ClientType
+	case vendors.ClientType:
+		m.Type = vendors.ClientType
 	default:
 		return ErrUnknownManagementType{Type: m.Type}
 	}
@@ -83,6 +280,90 @@ func (m *ManagementConfiguration) Validate() error {
 	return nil
 }
 
+// Validate validates that a credentials configuration names a known provider along with the options that provider
+// requires. A nil configuration is valid.
+func (c *CredentialsConfiguration) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	switch c.Provider {
+	case "", CredentialProviderSecret, CredentialProviderEnv:
+	case CredentialProviderFile:
+		if c.Path == "" {
+			return ErrInvalidCredentialsConfiguration{Option: "path", Reason: "is required by the file provider"}
+		}
+	case CredentialProviderExec:
+		if len(c.Command) == 0 {
+			return ErrInvalidCredentialsConfiguration{Option: "command", Reason: "is required by the exec provider"}
+		}
+	default:
+		return ErrInvalidCredentialsConfiguration{Option: "provider", Reason: fmt.Sprintf("unknown provider '%s'",
+			c.Provider)}
+	}
+
+	if c.Timeout < 0 {
+		return ErrInvalidCredentialsConfiguration{Option: "timeout", Reason: "must not be negative"}
+	}
+
+	return nil
+}
+
+// ExecTimeout returns how long the exec provider waits for its command to complete.
+func (c *CredentialsConfiguration) ExecTimeout() time.Duration {
+	if c != nil && c.Timeout > 0 {
+		return time.Duration(c.Timeout) * time.Second
+	}
+
+	return DefaultCredentialsExecTimeout * time.Second
+}
+
+// Validate validates that a TLS configuration provides both a client certificate and its key, or neither, that it
+// verifies BMC certificates either against a CA bundle or against pinned fingerprints, and that it does not verify
+// them when verification is disabled by insecure. A nil configuration is valid.
+func (t *TLSConfiguration) Validate(insecure bool) error {
+	if t == nil {
+		return nil
+	}
+
+	switch {
+	case t.ClientCertificate != "" && t.ClientKey == "":
+		return ErrInvalidTLSConfiguration{Option: "clientKey", Reason: "is required with clientCertificate"}
+	case t.ClientKey != "" && t.ClientCertificate == "":
+		return ErrInvalidTLSConfiguration{Option: "clientCertificate", Reason: "is required with clientKey"}
+	case t.CABundle != "" && len(t.PinnedFingerprints) > 0:
+		return ErrInvalidTLSConfiguration{Option: "pinnedFingerprints", Reason: "cannot be combined with caBundle, " +
+			"since pinned certificates are trusted without verifying their chain"}
+	case insecure && (t.CABundle != "" || len(t.PinnedFingerprints) > 0):
+		return ErrInvalidTLSConfiguration{Option: "insecure", Reason: "cannot be combined with caBundle or " +
+			"pinnedFingerprints"}
+	}
+
+	return nil
+}
+
+// Validate validates that the options of a retry configuration are within range. A nil configuration is valid.
+func (r *RetryConfiguration) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	switch {
+	case r.Timeout < 0:
+		return ErrInvalidRetryConfiguration{Option: "timeout", Reason: "must not be negative"}
+	case r.PollInterval < 0:
+		return ErrInvalidRetryConfiguration{Option: "pollInterval", Reason: "must not be negative"}
+	case r.MaxPollInterval < 0:
+		return ErrInvalidRetryConfiguration{Option: "maxPollInterval", Reason: "must not be negative"}
+	case r.BackoffMultiplier < 0:
+		return ErrInvalidRetryConfiguration{Option: "backoffMultiplier", Reason: "must not be negative"}
+	case r.Jitter != nil && (*r.Jitter < 0 || *r.Jitter > 1):
+		return ErrInvalidRetryConfiguration{Option: "jitter", Reason: "must be between 0 and 1"}
+	}
+
+	return nil
+}
+
 // NewManagementConfiguration returns a management configuration with default values.
 func NewManagementConfiguration() *ManagementConfiguration {
 	return &ManagementConfiguration{
@@ -16,12 +16,16 @@ package config_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish/vendors"
 	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
 )
 
 func TestNewManagementConfiguration(t *testing.T) {
@@ -61,6 +65,22 @@ func TestValidateRedfishDell(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestValidateIPMI(t *testing.T) {
+	cfg := config.NewManagementConfiguration()
+	cfg.Type = ipmi.ClientType
+
+	err := cfg.Validate()
+	assert.NoError(t, err)
+}
+
+func TestValidateAuto(t *testing.T) {
+	cfg := config.NewManagementConfiguration()
+	cfg.Type = vendors.ClientType
+
+	err := cfg.Validate()
+	assert.NoError(t, err)
+}
+
 func TestValidateInvalidManagementType(t *testing.T) {
 	cfg := config.NewManagementConfiguration()
 	cfg.Type = "invalid"
@@ -68,3 +88,240 @@ func TestValidateInvalidManagementType(t *testing.T) {
 	err := cfg.Validate()
 	assert.Error(t, err)
 }
+
+func TestValidateInvalidRetryConfiguration(t *testing.T) {
+	jitter := 1.5
+
+	cfg := config.NewManagementConfiguration()
+	cfg.Retry = &config.RetryConfiguration{Jitter: &jitter}
+
+	err := cfg.Validate()
+	assert.Equal(t, config.ErrInvalidRetryConfiguration{Option: "jitter", Reason: "must be between 0 and 1"}, err)
+}
+
+func TestValidateCredentialsConfiguration(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials *config.CredentialsConfiguration
+		expectedErr error
+	}{
+		{
+			name:        "default",
+			credentials: &config.CredentialsConfiguration{},
+		},
+		{
+			name:        "env",
+			credentials: &config.CredentialsConfiguration{Provider: config.CredentialProviderEnv},
+		},
+		{
+			name:        "file",
+			credentials: &config.CredentialsConfiguration{Provider: config.CredentialProviderFile, Path: "/etc/bmc"},
+		},
+		{
+			name:        "file without path",
+			credentials: &config.CredentialsConfiguration{Provider: config.CredentialProviderFile},
+			expectedErr: config.ErrInvalidCredentialsConfiguration{
+				Option: "path",
+				Reason: "is required by the file provider",
+			},
+		},
+		{
+			name:        "exec without command",
+			credentials: &config.CredentialsConfiguration{Provider: config.CredentialProviderExec},
+			expectedErr: config.ErrInvalidCredentialsConfiguration{
+				Option: "command",
+				Reason: "is required by the exec provider",
+			},
+		},
+		{
+			name: "negative exec timeout",
+			credentials: &config.CredentialsConfiguration{
+				Provider: config.CredentialProviderExec,
+				Command:  []string{"bmc-credentials"},
+				Timeout:  -1,
+			},
+			expectedErr: config.ErrInvalidCredentialsConfiguration{Option: "timeout", Reason: "must not be negative"},
+		},
+		{
+			name:        "unknown provider",
+			credentials: &config.CredentialsConfiguration{Provider: "vault"},
+			expectedErr: config.ErrInvalidCredentialsConfiguration{
+				Option: "provider",
+				Reason: "unknown provider 'vault'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewManagementConfiguration()
+			cfg.Credentials = tt.credentials
+
+			assert.Equal(t, tt.expectedErr, cfg.Validate())
+		})
+	}
+}
+
+func TestCredentialsExecTimeout(t *testing.T) {
+	var credentials *config.CredentialsConfiguration
+	assert.Equal(t, config.DefaultCredentialsExecTimeout*time.Second, credentials.ExecTimeout())
+
+	credentials = &config.CredentialsConfiguration{Timeout: 5}
+	assert.Equal(t, 5*time.Second, credentials.ExecTimeout())
+}
+
+func TestValidateTLSConfiguration(t *testing.T) {
+	tests := []struct {
+		name        string
+		insecure    bool
+		tls         *config.TLSConfiguration
+		expectedErr error
+	}{
+		{
+			name: "CA bundle and client certificate",
+			tls: &config.TLSConfiguration{
+				CABundle:          "/etc/airship/bmc-ca.pem",
+				ClientCertificate: "/etc/airship/client.pem",
+				ClientKey:         "/etc/airship/client-key.pem",
+			},
+		},
+		{
+			name:     "insecure with client certificate",
+			insecure: true,
+			tls: &config.TLSConfiguration{
+				ClientCertificate: "/etc/airship/client.pem",
+				ClientKey:         "/etc/airship/client-key.pem",
+			},
+		},
+		{
+			name:        "client certificate without key",
+			tls:         &config.TLSConfiguration{ClientCertificate: "/etc/airship/client.pem"},
+			expectedErr: config.ErrInvalidTLSConfiguration{Option: "clientKey", Reason: "is required with clientCertificate"},
+		},
+		{
+			name:        "client key without certificate",
+			tls:         &config.TLSConfiguration{ClientKey: "/etc/airship/client-key.pem"},
+			expectedErr: config.ErrInvalidTLSConfiguration{Option: "clientCertificate", Reason: "is required with clientKey"},
+		},
+		{
+			name: "CA bundle with pinned fingerprints",
+			tls: &config.TLSConfiguration{
+				CABundle:           "/etc/airship/bmc-ca.pem",
+				PinnedFingerprints: []string{"AB:CD"},
+			},
+			expectedErr: config.ErrInvalidTLSConfiguration{
+				Option: "pinnedFingerprints",
+				Reason: "cannot be combined with caBundle, since pinned certificates are trusted without verifying " +
+					"their chain",
+			},
+		},
+		{
+			name:     "insecure with pinned fingerprints",
+			insecure: true,
+			tls:      &config.TLSConfiguration{PinnedFingerprints: []string{"AB:CD"}},
+			expectedErr: config.ErrInvalidTLSConfiguration{
+				Option: "insecure",
+				Reason: "cannot be combined with caBundle or pinnedFingerprints",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewManagementConfiguration()
+			cfg.Insecure = tt.insecure
+			cfg.TLS = tt.tls
+
+			assert.Equal(t, tt.expectedErr, cfg.Validate())
+		})
+	}
+}
+
+func TestTLSConfig(t *testing.T) {
+	cfg := config.NewManagementConfiguration()
+
+	tlsConfig, err := cfg.TLSConfig()
+	require.NoError(t, err)
+	assert.False(t, tlsConfig.InsecureSkipVerify)
+
+	cfg.Insecure = true
+
+	tlsConfig, err = cfg.TLSConfig()
+	require.NoError(t, err)
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+
+	cfg.TLS = &config.TLSConfiguration{CABundle: "/does/not/exist.pem"}
+
+	_, err = cfg.TLSConfig()
+	assert.Error(t, err)
+}
+
+func TestRetryPolicyDefault(t *testing.T) {
+	cfg := config.NewManagementConfiguration()
+
+	expected := retry.Policy{
+		Timeout:     config.DefaultOperationTimeout * time.Second,
+		Interval:    config.DefaultPollInterval * time.Second,
+		MaxInterval: config.DefaultMaxPollInterval * time.Second,
+		Multiplier:  config.DefaultBackoffMultiplier,
+		Jitter:      config.DefaultJitter,
+	}
+	assert.Equal(t, expected, cfg.RetryPolicy())
+}
+
+func TestRetryPolicyFromSystemActionRetries(t *testing.T) {
+	cfg := config.NewManagementConfiguration()
+	cfg.SystemActionRetries = 10
+	cfg.SystemRebootDelay = 6
+
+	assert.Equal(t, time.Minute, cfg.RetryPolicy().Timeout)
+}
+
+func TestRetryPolicyFromSystemRebootDelay(t *testing.T) {
+	cfg := config.NewManagementConfiguration()
+	cfg.SystemRebootDelay = 60
+
+	policy := cfg.RetryPolicy()
+	assert.Equal(t, time.Minute, policy.Interval)
+	assert.Equal(t, time.Minute, policy.MaxInterval)
+
+	cfg.SystemRebootDelay = 5
+	policy = cfg.RetryPolicy()
+	assert.Equal(t, 5*time.Second, policy.Interval)
+	assert.Equal(t, config.DefaultMaxPollInterval*time.Second, policy.MaxInterval)
+
+	// A configured poll interval takes precedence over the deprecated reboot delay
+	cfg.Retry = &config.RetryConfiguration{PollInterval: 1}
+	assert.Equal(t, time.Second, cfg.RetryPolicy().Interval)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	jitter := 0.0
+
+	cfg := config.NewManagementConfiguration()
+	cfg.Retry = &config.RetryConfiguration{
+		Timeout:           120,
+		PollInterval:      1,
+		MaxPollInterval:   10,
+		BackoffMultiplier: 1.5,
+		Jitter:            &jitter,
+	}
+
+	expected := retry.Policy{
+		Timeout:     2 * time.Minute,
+		Interval:    time.Second,
+		MaxInterval: 10 * time.Second,
+		Multiplier:  1.5,
+	}
+	assert.Equal(t, expected, cfg.RetryPolicy())
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	cfg := config.NewManagementConfiguration()
+	assert.Equal(t, config.DefaultGracefulShutdownTimeout*time.Second, cfg.ShutdownTimeout())
+
+	cfg.GracefulShutdownTimeout = 60
+	assert.Equal(t, time.Minute, cfg.ShutdownTimeout())
+}
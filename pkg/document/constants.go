@@ -31,6 +31,8 @@ const (
 	SecretKind        = "Secret"
 	BareMetalHostKind = "BareMetalHost"
 
+	HostFirmwareSettingsKind = "HostFirmwareSettings"
+
 	ConfigMapKind    = "ConfigMap"
 	ConfigMapVersion = "v1"
 
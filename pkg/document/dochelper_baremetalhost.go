@@ -44,6 +44,23 @@ func GetBMHBMCAddress(bmh Document) (string, error) {
 	return bmcAddress, nil
 }
 
+// GetBMHBootMACAddress returns the MAC address of the NIC used to boot the host described by the bmh document supplied
+func GetBMHBootMACAddress(bmh Document) (string, error) {
+	return bmh.GetString("spec.bootMACAddress")
+}
+
+// GetBMHFirmwareSettings returns the desired BIOS settings for the bmh document supplied from the HostFirmwareSettings
+// document of the same name and namespace within the supplied bundle
+func GetBMHFirmwareSettings(bmh Document, bundle Bundle) (map[string]interface{}, error) {
+	selector := NewHostFirmwareSettingsSelector(bmh)
+	doc, err := bundle.SelectOne(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.GetMap("spec.settings")
+}
+
 // GetBMHBMCCredentials returns the BMC credentials for the bmh document supplied from
 // the supplied bundle
 func GetBMHBMCCredentials(bmh Document, bundle Bundle) (username string, password string, err error) {
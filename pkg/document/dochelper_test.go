@@ -66,4 +66,15 @@ func TestDocHelpers(t *testing.T) {
 		assert.Equal(bmcUsername, "username")
 		assert.Equal(bmcPassword, "password")
 	})
+
+	t.Run("GetBMHFirmwareSettings", func(t *testing.T) {
+		// retrieve our single bmh in the dataset
+		selector := document.NewSelector().ByKind("BareMetalHost")
+		doc, err := bundle.SelectOne(selector)
+		require.NoError(err)
+
+		settings, err := document.GetBMHFirmwareSettings(doc, bundle)
+		require.NoError(err, "Unexpected error trying to GetBMHFirmwareSettings")
+		assert.Equal(map[string]interface{}{"ProcVirtualization": "Enabled", "SriovGlobalEnable": "true"}, settings)
+	})
 }
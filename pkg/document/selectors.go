@@ -129,6 +129,15 @@ func NewBMCCredentialsSelector(name string) Selector {
 	return NewSelector().ByKind(SecretKind).ByName(name)
 }
 
+// NewHostFirmwareSettingsSelector returns selector to get the HostFirmwareSettings document
+// holding the desired BIOS settings of the BaremetalHost supplied
+func NewHostFirmwareSettingsSelector(bmhDoc Document) Selector {
+	return NewSelector().
+		ByKind(HostFirmwareSettingsKind).
+		ByNamespace(bmhDoc.GetNamespace()).
+		ByName(bmhDoc.GetName())
+}
+
 // NewNetworkDataSelector returns selector that can be used to get secret with
 // network data bmhDoc argument is a document interface, that should hold fields
 // spec.networkData.name and spec.networkData.namespace where to find the secret,
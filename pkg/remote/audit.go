@@ -0,0 +1,200 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// Outcomes of an audited action
+const (
+	AuditSucceeded = "succeeded"
+	AuditFailed    = "failed"
+)
+
+// AuditRecord records an out-of-band action performed on a baremetal host, and by whom.
+type AuditRecord struct {
+	Timestamp  time.Time `json:"timestamp"`
+	User       string    `json:"user"`
+	Context    string    `json:"context"`
+	HostName   string    `json:"host"`
+	BMCAddress string    `json:"bmcAddress"`
+	Action     string    `json:"action"`
+	Outcome    string    `json:"outcome"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// AuditFilter selects audit records. Empty fields select every record.
+type AuditFilter struct {
+	HostName string
+	Since    time.Time
+	Until    time.Time
+}
+
+// matches reports whether the filter selects an audit record.
+func (f AuditFilter) matches(record AuditRecord) bool {
+	switch {
+	case f.HostName != "" && record.HostName != f.HostName:
+		return false
+	case !f.Since.IsZero() && record.Timestamp.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && record.Timestamp.After(f.Until):
+		return false
+	default:
+		return true
+	}
+}
+
+// AuditLog appends a JSON record of every out-of-band action performed on a baremetal host to a file, one record per
+// line, so that operators can trace which actions were performed on a host, when and by whom. A nil audit log records
+// nothing.
+type AuditLog struct {
+	Path    string
+	User    string
+	Context string
+
+	mu sync.Mutex
+}
+
+// AuditLogPath returns the path of the audit log, which is kept in the directory of the airshipctl configuration
+// file. An empty path is returned when the location of the configuration file is unknown.
+func AuditLogPath(settings *environment.AirshipCTLSettings) string {
+	if settings.AirshipConfigPath == "" {
+		return ""
+	}
+
+	return filepath.Join(filepath.Dir(settings.AirshipConfigPath), config.AirshipAuditLog)
+}
+
+// NewAuditLog provides the audit log of the airshipctl settings, recording actions as performed by the current OS user
+// in the current context. No audit log is provided when the location of the configuration file is unknown.
+func NewAuditLog(settings *environment.AirshipCTLSettings) *AuditLog {
+	path := AuditLogPath(settings)
+	if path == "" {
+		return nil
+	}
+
+	return &AuditLog{
+		Path:    path,
+		User:    currentUser(),
+		Context: settings.Config.CurrentContext,
+	}
+}
+
+// Record appends a record of an action performed on a baremetal host, and its outcome, to the audit log. The action
+// has already been performed, so a failure to write the record is logged rather than returned.
+func (a *AuditLog) Record(hostName, bmcAddress, action string, actionErr error) {
+	if a == nil {
+		return
+	}
+
+	record := AuditRecord{
+		Timestamp:  time.Now().UTC(),
+		User:       a.User,
+		Context:    a.Context,
+		HostName:   hostName,
+		BMCAddress: bmcAddress,
+		Action:     action,
+		Outcome:    AuditSucceeded,
+	}
+
+	if actionErr != nil {
+		record.Outcome = AuditFailed
+		record.Error = actionErr.Error()
+	}
+
+	if err := a.append(record); err != nil {
+		log.Printf("Unable to record action '%s' on host '%s' in audit log '%s': %v", action, hostName, a.Path, err)
+	}
+}
+
+func (a *AuditLog) append(record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err = os.MkdirAll(filepath.Dir(a.Path), 0700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(a.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// ReadAuditLog reads the records of the audit log at path that are selected by filter, in the order they were
+// recorded. A missing audit log holds no records. Lines that are not valid records, e.g. a record left incomplete by
+// an interrupted write, are skipped.
+func ReadAuditLog(path string, filter AuditFilter) ([]AuditRecord, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return []AuditRecord{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records := []AuditRecord{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record AuditRecord
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			log.Printf("Skipping invalid record on line %d of audit log '%s': %v", line, path, err)
+			continue
+		}
+
+		if filter.matches(record) {
+			records = append(records, record)
+		}
+	}
+
+	return records, scanner.Err()
+}
+
+// currentUser returns the name of the OS user running airshipctl.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
@@ -0,0 +1,152 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+)
+
+func newTestAuditLog(t *testing.T) (*AuditLog, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "airshipctl-audit-")
+	require.NoError(t, err)
+
+	return &AuditLog{Path: filepath.Join(dir, "audit", config.AirshipAuditLog), User: "operator", Context: "prod"},
+		func() { os.RemoveAll(dir) }
+}
+
+func TestAuditLogRecord(t *testing.T) {
+	audit, cleanup := newTestAuditLog(t)
+	defer cleanup()
+
+	audit.Record("node-1", redfishURL, "power off", nil)
+	audit.Record("node-2", redfishURL, "power off", errors.New("BMC unavailable"))
+
+	records, err := ReadAuditLog(audit.Path, AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "operator", records[0].User)
+	assert.Equal(t, "prod", records[0].Context)
+	assert.Equal(t, "node-1", records[0].HostName)
+	assert.Equal(t, redfishURL, records[0].BMCAddress)
+	assert.Equal(t, "power off", records[0].Action)
+	assert.Equal(t, AuditSucceeded, records[0].Outcome)
+	assert.Empty(t, records[0].Error)
+	assert.False(t, records[0].Timestamp.IsZero())
+
+	assert.Equal(t, AuditFailed, records[1].Outcome)
+	assert.Equal(t, "BMC unavailable", records[1].Error)
+
+	info, err := os.Stat(audit.Path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestAuditLogNil(t *testing.T) {
+	var audit *AuditLog
+	assert.NotPanics(t, func() { audit.Record("node-1", redfishURL, "power off", nil) })
+}
+
+func TestReadAuditLogFilter(t *testing.T) {
+	audit, cleanup := newTestAuditLog(t)
+	defer cleanup()
+
+	start := time.Now().UTC()
+	records := []AuditRecord{
+		{Timestamp: start.Add(-2 * time.Hour), HostName: "node-1", Action: "power off"},
+		{Timestamp: start.Add(-time.Hour), HostName: "node-2", Action: "power on"},
+		{Timestamp: start, HostName: "node-1", Action: "power on"},
+	}
+	for _, record := range records {
+		require.NoError(t, audit.append(record))
+	}
+
+	// Records left incomplete by an interrupted write are skipped
+	f, err := os.OpenFile(audit.Path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = f.WriteString("{\"timestamp\":\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	selected, err := ReadAuditLog(audit.Path, AuditFilter{HostName: "node-1"})
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	assert.Equal(t, "power off", selected[0].Action)
+	assert.Equal(t, "power on", selected[1].Action)
+
+	selected, err = ReadAuditLog(audit.Path, AuditFilter{
+		Since: start.Add(-90 * time.Minute),
+		Until: start.Add(-30 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "node-2", selected[0].HostName)
+}
+
+func TestReadAuditLogMissing(t *testing.T) {
+	records, err := ReadAuditLog(filepath.Join("testdata", "does-not-exist.log"), AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestNewAuditLog(t *testing.T) {
+	settings := initSettings(t)
+	assert.Nil(t, NewAuditLog(settings))
+
+	settings.AirshipConfigPath = filepath.Join("home", ".airship", config.AirshipConfig)
+	audit := NewAuditLog(settings)
+	require.NotNil(t, audit)
+	assert.Equal(t, filepath.Join("home", ".airship", config.AirshipAuditLog), audit.Path)
+	assert.Equal(t, settings.Config.CurrentContext, audit.Context)
+}
+
+func TestExecuteAudit(t *testing.T) {
+	audit, cleanup := newTestAuditLog(t)
+	defer cleanup()
+
+	host1, rMock1 := newMockHost(t, "node-1")
+	host2, rMock2 := newMockHost(t, "node-2")
+
+	rMock1.On("SystemPowerOff", host1.Context).Times(1).Return(nil)
+	rMock2.On("SystemPowerOff", host2.Context).Times(1).Return(errors.New("BMC unavailable"))
+
+	m := &Manager{Hosts: []baremetalHost{host1, host2}, audit: audit}
+
+	_, err := m.Execute("power off", 1, powerOffAction)
+	require.Error(t, err)
+
+	records, err := ReadAuditLog(audit.Path, AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "node-1", records[0].HostName)
+	assert.Equal(t, AuditSucceeded, records[0].Outcome)
+	assert.Equal(t, "node-2", records[1].HostName)
+	assert.Equal(t, AuditFailed, records[1].Outcome)
+
+	rMock1.AssertExpectations(t)
+	rMock2.AssertExpectations(t)
+}
@@ -0,0 +1,160 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/bios"
+)
+
+// HostBIOSSettings describes the BIOS settings of a baremetal host.
+type HostBIOSSettings struct {
+	HostName   string         `json:"name"`
+	BMCAddress string         `json:"bmcAddress"`
+	Settings   *bios.Settings `json:"bios,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// BIOSSettings retrieves the BIOS settings of every host selected by the manager, acting on up to concurrency hosts
+// at once. When attribute names are supplied, only those attributes are retrieved. Settings are returned for every
+// host; when they cannot be retrieved from some hosts, their settings record the failure and an ErrHostActionsFailed
+// error is returned alongside them.
+func (m *Manager) BIOSSettings(concurrency int, names ...string) ([]HostBIOSSettings, error) {
+	hostSettings := make([]bios.Settings, len(m.Hosts))
+
+	getSettings := func(i int) HostAction {
+		return func(ctx context.Context, client Client) (string, error) {
+			settings, err := client.BIOSSettings(ctx)
+			if err != nil {
+				return "", err
+			}
+
+			hostSettings[i] = settings.Filter(names...)
+			return "", nil
+		}
+	}
+
+	results, err := m.execute("get BIOS settings", concurrency, getSettings, m.audit)
+
+	settingsList := make([]HostBIOSSettings, 0, len(m.Hosts))
+	for i, host := range m.Hosts {
+		settings := HostBIOSSettings{HostName: host.HostName, BMCAddress: host.BMCAddress}
+
+		if result := results[i]; result.Err != nil {
+			settings.Error = collapse(result.Err)
+		} else {
+			settings.Settings = &hostSettings[i]
+		}
+
+		settingsList = append(settingsList, settings)
+	}
+
+	return settingsList, err
+}
+
+// SetBIOSSettings stages BIOS attributes on every host selected by the manager, acting on up to concurrency hosts at
+// once. The attributes staged on a host are those of its HostFirmwareSettings document, if any, overridden by the
+// supplied attributes; only attributes whose values differ from the current ones are staged. When reboot is true,
+// hosts on which attributes were staged are rebooted so that the BMC applies them.
+func (m *Manager) SetBIOSSettings(concurrency int, attributes bios.Attributes, reboot bool) ([]HostResult, error) {
+	desired := make([]bios.Attributes, len(m.Hosts))
+	for i, host := range m.Hosts {
+		hostAttributes, err := m.documentBIOSAttributes(host)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, value := range attributes {
+			hostAttributes[name] = value
+		}
+
+		desired[i] = hostAttributes
+	}
+
+	setSettings := func(i int) HostAction {
+		return func(ctx context.Context, client Client) (string, error) {
+			if len(desired[i]) == 0 {
+				return "", ErrNoBIOSSettings{}
+			}
+
+			settings, err := client.BIOSSettings(ctx)
+			if err != nil {
+				return "", err
+			}
+
+			changes, err := bios.Changes(settings.Current, desired[i])
+			if err != nil {
+				return "", err
+			}
+
+			if len(changes) == 0 {
+				return "BIOS settings up to date", nil
+			}
+
+			if err = client.SetBIOSAttributes(ctx, changes); err != nil {
+				return "", err
+			}
+
+			output := fmt.Sprintf("staged %s", strings.Join(changes.Names(), ", "))
+			if !reboot {
+				return output, nil
+			}
+
+			if err = client.RebootSystem(ctx); err != nil {
+				return output, err
+			}
+
+			return output + ", rebooted", nil
+		}
+	}
+
+	return m.execute("set BIOS settings", concurrency, setSettings, m.audit)
+}
+
+// documentBIOSAttributes retrieves the BIOS attributes defined for a host by the HostFirmwareSettings document of its
+// baremetal host document. An empty set of attributes is returned for hosts without such a document.
+func (m *Manager) documentBIOSAttributes(host baremetalHost) (bios.Attributes, error) {
+	attributes := bios.Attributes{}
+	if m.docBundle == nil {
+		return attributes, nil
+	}
+
+	// Hosts of different namespaces may share a name
+	selector := document.NewSelector().ByKind(document.BareMetalHostKind).ByNamespace(host.Namespace).ByName(host.HostName)
+	bmh, err := m.docBundle.SelectOne(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := document.GetBMHFirmwareSettings(bmh, m.docBundle)
+	if _, ok := err.(document.ErrDocNotFound); ok {
+		log.Debugf("Baremetal host '%s' does not have a HostFirmwareSettings document.", host.HostName)
+		return attributes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range settings {
+		attributes[name] = value
+	}
+
+	return attributes, nil
+}
@@ -0,0 +1,178 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package bios describes the BIOS settings of baremetal hosts independently of the management client used to
+// retrieve and change them.
+package bios
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Attributes maps the names of BIOS attributes, e.g. ProcVirtualization, to their values. Values are strings, numbers
+// or booleans.
+type Attributes map[string]interface{}
+
+// Settings describes the BIOS attributes of a baremetal host.
+type Settings struct {
+	// Current holds the attributes the host is running with.
+	Current Attributes `json:"current"`
+	// Pending holds the attributes that have been staged and are applied on the next reboot of the host.
+	Pending Attributes `json:"pending,omitempty"`
+}
+
+// Filter returns the settings restricted to the supplied attribute names. When no names are supplied, the settings
+// are returned unchanged.
+func (s Settings) Filter(names ...string) Settings {
+	if len(names) == 0 {
+		return s
+	}
+
+	return Settings{Current: s.Current.filter(names), Pending: s.Pending.filter(names)}
+}
+
+func (a Attributes) filter(names []string) Attributes {
+	if a == nil {
+		return nil
+	}
+
+	filtered := Attributes{}
+	for _, name := range names {
+		if value, ok := a[name]; ok {
+			filtered[name] = value
+		}
+	}
+
+	return filtered
+}
+
+// Names returns the attribute names in alphabetical order.
+func (a Attributes) Names() []string {
+	names := make([]string, 0, len(a))
+	for name := range a {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// ParseAttributes translates name=value pairs, e.g. ProcVirtualization=Enabled, into attributes. Values are kept as
+// strings; they are converted to the type of the attribute they set by Changes.
+func ParseAttributes(pairs []string) (Attributes, error) {
+	attributes := Attributes{}
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, ErrInvalidAttribute{Attribute: pair}
+		}
+
+		attributes[parts[0]] = parts[1]
+	}
+
+	return attributes, nil
+}
+
+// Changes returns the desired attributes whose values differ from the current attributes of a host. Desired values
+// are converted to the type of the current value of the attribute they set, so that e.g. the string "4" may set a
+// numeric attribute. An error is returned when a desired attribute does not exist or its value cannot be converted.
+func Changes(current, desired Attributes) (Attributes, error) {
+	changes := Attributes{}
+	for _, name := range desired.Names() {
+		currentValue, ok := current[name]
+		if !ok {
+			return nil, ErrUnknownAttribute{Attribute: name}
+		}
+
+		value, err := convert(desired[name], currentValue)
+		if err != nil {
+			return nil, ErrInvalidValue{Attribute: name, Value: desired[name], Err: err}
+		}
+
+		if value != currentValue {
+			changes[name] = value
+		}
+	}
+
+	return changes, nil
+}
+
+// convert translates a value to the type of a current attribute value. Numbers are represented as float64, the type
+// JSON numbers are decoded into.
+func convert(value, currentValue interface{}) (interface{}, error) {
+	switch currentValue.(type) {
+	case bool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case float64:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case string:
+			return strconv.ParseFloat(v, 64)
+		}
+	case string:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case bool, float64, int, int64:
+			return fmt.Sprint(v), nil
+		}
+	default:
+		// Attributes without a value, e.g. unset passwords, accept values of any type.
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("expected a value of type %T, got %T", currentValue, value)
+}
+
+// ErrInvalidAttribute is an error that indicates a BIOS attribute is not of the form name=value.
+type ErrInvalidAttribute struct {
+	Attribute string
+}
+
+func (e ErrInvalidAttribute) Error() string {
+	return fmt.Sprintf("invalid BIOS attribute %q, must be of the form name=value", e.Attribute)
+}
+
+// ErrUnknownAttribute is an error that indicates a baremetal host does not have a BIOS attribute.
+type ErrUnknownAttribute struct {
+	Attribute string
+}
+
+func (e ErrUnknownAttribute) Error() string {
+	return fmt.Sprintf("unknown BIOS attribute %q", e.Attribute)
+}
+
+// ErrInvalidValue is an error that indicates a value cannot be assigned to a BIOS attribute.
+type ErrInvalidValue struct {
+	Attribute string
+	Value     interface{}
+	Err       error
+}
+
+func (e ErrInvalidValue) Error() string {
+	return fmt.Sprintf("invalid value %v for BIOS attribute %q: %v", e.Value, e.Attribute, e.Err)
+}
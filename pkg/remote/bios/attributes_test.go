@@ -0,0 +1,88 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package bios
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var current = Attributes{
+	"ProcVirtualization": "Disabled",
+	"SriovGlobalEnable":  false,
+	"NumLock":            "On",
+	"ProcCores":          float64(8),
+	"SetupPassword":      nil,
+}
+
+func TestParseAttributes(t *testing.T) {
+	attributes, err := ParseAttributes([]string{"ProcVirtualization=Enabled", "BootArgs=quiet=1", "Empty="})
+	require.NoError(t, err)
+	assert.Equal(t, Attributes{"ProcVirtualization": "Enabled", "BootArgs": "quiet=1", "Empty": ""}, attributes)
+
+	for _, pair := range []string{"ProcVirtualization", "=Enabled"} {
+		_, err = ParseAttributes([]string{pair})
+		assert.Equal(t, ErrInvalidAttribute{Attribute: pair}, err)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	changes, err := Changes(current, Attributes{
+		"ProcVirtualization": "Enabled",
+		"SriovGlobalEnable":  "true",
+		"NumLock":            "On",
+		"ProcCores":          4,
+		"SetupPassword":      "secret",
+	})
+	require.NoError(t, err)
+
+	expected := Attributes{
+		"ProcVirtualization": "Enabled",
+		"SriovGlobalEnable":  true,
+		"ProcCores":          float64(4),
+		"SetupPassword":      "secret",
+	}
+	assert.Equal(t, expected, changes)
+}
+
+func TestChangesNone(t *testing.T) {
+	changes, err := Changes(current, Attributes{"SriovGlobalEnable": false, "ProcCores": "8"})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestChangesUnknownAttribute(t *testing.T) {
+	_, err := Changes(current, Attributes{"MemTest": "Enabled"})
+	assert.Equal(t, ErrUnknownAttribute{Attribute: "MemTest"}, err)
+}
+
+func TestChangesInvalidValue(t *testing.T) {
+	_, err := Changes(current, Attributes{"SriovGlobalEnable": "Enabled"})
+	assert.Error(t, err)
+	assert.IsType(t, ErrInvalidValue{}, err)
+	assert.Contains(t, err.Error(), `invalid value Enabled for BIOS attribute "SriovGlobalEnable"`)
+}
+
+func TestFilter(t *testing.T) {
+	settings := Settings{Current: current, Pending: Attributes{"ProcVirtualization": "Enabled"}}
+
+	assert.Equal(t, settings, settings.Filter())
+	assert.Equal(t, Settings{
+		Current: Attributes{"ProcVirtualization": "Disabled", "NumLock": "On"},
+		Pending: Attributes{"ProcVirtualization": "Enabled"},
+	}, settings.Filter("ProcVirtualization", "NumLock"))
+}
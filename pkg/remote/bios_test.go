@@ -0,0 +1,218 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/remote/bios"
+)
+
+var currentBIOSSettings = bios.Settings{
+	Current: bios.Attributes{"ProcVirtualization": "Disabled", "SriovGlobalEnable": false, "NumLock": "On"},
+}
+
+func TestBIOSSettings(t *testing.T) {
+	host1, rMock1 := newMockHost(t, "node-1")
+	host2, rMock2 := newMockHost(t, "node-2")
+
+	rMock1.On("BIOSSettings", host1.Context).Times(1).Return(currentBIOSSettings, nil)
+	rMock2.On("BIOSSettings", host2.Context).Times(1).Return(nil, errors.New("BMC\nunavailable"))
+
+	m := &Manager{Hosts: []baremetalHost{host1, host2}}
+
+	settings, err := m.BIOSSettings(2, "ProcVirtualization")
+	_, ok := err.(ErrHostActionsFailed)
+	assert.True(t, ok)
+
+	expected := []HostBIOSSettings{
+		{
+			HostName:   "node-1",
+			BMCAddress: redfishURL,
+			Settings:   &bios.Settings{Current: bios.Attributes{"ProcVirtualization": "Disabled"}},
+		},
+		{HostName: "node-2", BMCAddress: redfishURL, Error: "BMC unavailable"},
+	}
+	assert.Equal(t, expected, settings)
+
+	rMock1.AssertExpectations(t)
+	rMock2.AssertExpectations(t)
+}
+
+func TestSetBIOSSettings(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes bios.Attributes
+		reboot     bool
+		changes    bios.Attributes
+		output     string
+	}{
+		{
+			name:       "Staged",
+			attributes: bios.Attributes{"ProcVirtualization": "Enabled", "NumLock": "On"},
+			changes:    bios.Attributes{"ProcVirtualization": "Enabled"},
+			output:     "staged ProcVirtualization",
+		},
+		{
+			name:       "Rebooted",
+			attributes: bios.Attributes{"SriovGlobalEnable": "true"},
+			reboot:     true,
+			changes:    bios.Attributes{"SriovGlobalEnable": true},
+			output:     "staged SriovGlobalEnable, rebooted",
+		},
+		{
+			name:       "UpToDate",
+			attributes: bios.Attributes{"NumLock": "On"},
+			reboot:     true,
+			output:     "BIOS settings up to date",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			host, rMock := newMockHost(t, "node-1")
+			rMock.On("BIOSSettings", host.Context).Times(1).Return(currentBIOSSettings, nil)
+			if tt.changes != nil {
+				rMock.On("SetBIOSAttributes", host.Context, tt.changes).Times(1).Return(nil)
+			}
+			if tt.reboot && tt.changes != nil {
+				rMock.On("RebootSystem", host.Context).Times(1).Return(nil)
+			}
+
+			m := &Manager{Hosts: []baremetalHost{host}}
+
+			results, err := m.SetBIOSSettings(1, tt.attributes, tt.reboot)
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.output, results[0].Output)
+
+			rMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetBIOSSettingsFromDocument(t *testing.T) {
+	docBundle, err := document.NewBundleByPath(bootstrapDocsPath)
+	require.NoError(t, err)
+
+	host1, rMock1 := newMockHost(t, "master-0")
+	host2, rMock2 := newMockHost(t, "master-1")
+
+	rMock1.On("BIOSSettings", host1.Context).Times(1).Return(currentBIOSSettings, nil)
+	rMock1.On("SetBIOSAttributes", host1.Context,
+		bios.Attributes{"ProcVirtualization": "Enabled", "SriovGlobalEnable": true}).Times(1).Return(nil)
+
+	m := &Manager{Hosts: []baremetalHost{host1, host2}, docBundle: docBundle}
+
+	// Only master-0 has a HostFirmwareSettings document
+	results, err := m.SetBIOSSettings(1, nil, false)
+	_, ok := err.(ErrHostActionsFailed)
+	assert.True(t, ok)
+
+	require.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "staged ProcVirtualization, SriovGlobalEnable", results[0].Output)
+	assert.Equal(t, ErrNoBIOSSettings{}, results[1].Err)
+
+	rMock1.AssertExpectations(t)
+	rMock2.AssertExpectations(t)
+}
+
+func TestSetBIOSSettingsFromDocumentNamespace(t *testing.T) {
+	docBundle, err := document.NewBundleByPath("testdata/namespaces")
+	require.NoError(t, err)
+
+	// Both hosts are named master-0; only the host of namespace site-b has a HostFirmwareSettings document
+	hostA, rMockA := newMockHost(t, "master-0")
+	hostA.Namespace = "site-a"
+	hostB, rMockB := newMockHost(t, "master-0")
+	hostB.Namespace = "site-b"
+
+	rMockB.On("BIOSSettings", hostB.Context).Times(1).Return(currentBIOSSettings, nil)
+	rMockB.On("SetBIOSAttributes", hostB.Context, bios.Attributes{"NumLock": "Off"}).Times(1).Return(nil)
+
+	m := &Manager{Hosts: []baremetalHost{hostA, hostB}, docBundle: docBundle}
+
+	results, err := m.SetBIOSSettings(2, nil, false)
+	_, ok := err.(ErrHostActionsFailed)
+	assert.True(t, ok)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, ErrNoBIOSSettings{}, results[0].Err)
+	assert.Equal(t, "staged NumLock", results[1].Output)
+
+	rMockA.AssertExpectations(t)
+	rMockB.AssertExpectations(t)
+}
+
+func TestBIOSSettingsSharedClient(t *testing.T) {
+	host1, rMock := newMockHost(t, "node-1")
+	host2 := host1
+	host2.HostName = "node-2"
+
+	// Hosts sharing a client keep their own settings
+	rMock.On("BIOSSettings", host1.Context).Once().Return(currentBIOSSettings, nil)
+	rMock.On("BIOSSettings", host1.Context).Once().
+		Return(bios.Settings{Current: bios.Attributes{"NumLock": "Off"}}, nil)
+
+	m := &Manager{Hosts: []baremetalHost{host1, host2}}
+
+	settings, err := m.BIOSSettings(1, "NumLock")
+	require.NoError(t, err)
+	require.Len(t, settings, 2)
+	assert.Equal(t, bios.Attributes{"NumLock": "On"}, settings[0].Settings.Current)
+	assert.Equal(t, bios.Attributes{"NumLock": "Off"}, settings[1].Settings.Current)
+
+	rMock.AssertExpectations(t)
+}
+
+func TestSetBIOSSettingsOverrideDocument(t *testing.T) {
+	docBundle, err := document.NewBundleByPath(bootstrapDocsPath)
+	require.NoError(t, err)
+
+	host, rMock := newMockHost(t, "master-0")
+	rMock.On("BIOSSettings", host.Context).Times(1).Return(currentBIOSSettings, nil)
+	rMock.On("SetBIOSAttributes", host.Context,
+		bios.Attributes{"ProcVirtualization": "Enabled", "NumLock": "Off"}).Times(1).Return(nil)
+
+	m := &Manager{Hosts: []baremetalHost{host}, docBundle: docBundle}
+
+	// The supplied attributes override the HostFirmwareSettings document, which enables SR-IOV
+	results, err := m.SetBIOSSettings(1, bios.Attributes{"SriovGlobalEnable": "false", "NumLock": "Off"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "staged NumLock, ProcVirtualization", results[0].Output)
+
+	rMock.AssertExpectations(t)
+}
+
+func TestSetBIOSSettingsUnknownAttribute(t *testing.T) {
+	host, rMock := newMockHost(t, "node-1")
+	rMock.On("BIOSSettings", host.Context).Times(1).Return(currentBIOSSettings, nil)
+
+	m := &Manager{Hosts: []baremetalHost{host}}
+
+	results, err := m.SetBIOSSettings(1, bios.Attributes{"MemTest": "Enabled"}, true)
+	_, ok := err.(ErrHostActionsFailed)
+	assert.True(t, ok)
+	assert.Equal(t, bios.ErrUnknownAttribute{Attribute: "MemTest"}, results[0].Err)
+
+	rMock.AssertExpectations(t)
+}
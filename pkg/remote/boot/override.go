@@ -0,0 +1,94 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package boot safely translates boot override information between different management clients.
+package boot
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DeviceUnknown indicates that a baremetal host boots from a device that has no vendor-neutral equivalent.
+	DeviceUnknown Device = iota
+	// DeviceNone indicates that a baremetal host boots according to its boot order.
+	DeviceNone
+	// DevicePXE indicates that a baremetal host boots from the network using PXE.
+	DevicePXE
+	// DeviceDisk indicates that a baremetal host boots from its primary disk.
+	DeviceDisk
+	// DeviceCD indicates that a baremetal host boots from its CD/DVD device, e.g. a virtual CD.
+	DeviceCD
+	// DeviceBIOS indicates that a baremetal host boots into its BIOS setup utility.
+	DeviceBIOS
+)
+
+// Device indicates the device a baremetal host boots from e.g. pxe, disk, cd.
+type Device int
+
+var deviceMap = [6]string{
+	"unknown",
+	"none",
+	"pxe",
+	"disk",
+	"cd",
+	"bios",
+}
+
+// String provides a human-readable string value for a boot device.
+func (d Device) String() string {
+	return deviceMap[d]
+}
+
+// ParseDevice translates the name of a boot device, e.g. pxe, into a boot device. Only devices that a host can be
+// instructed to boot from may be parsed.
+func ParseDevice(name string) (Device, error) {
+	for device, deviceName := range deviceMap {
+		if Device(device) != DeviceUnknown && strings.EqualFold(name, deviceName) {
+			return Device(device), nil
+		}
+	}
+
+	return DeviceUnknown, ErrUnknownDevice{Device: name}
+}
+
+// Override describes the device a baremetal host boots from in place of its boot order, and whether it does so on
+// its next boot only or on every boot.
+type Override struct {
+	Device     Device
+	Persistent bool
+}
+
+// String provides a human-readable description of a boot override, e.g. "pxe (once)".
+func (o Override) String() string {
+	if o.Device == DeviceNone {
+		return o.Device.String()
+	}
+
+	if o.Persistent {
+		return fmt.Sprintf("%s (persistent)", o.Device)
+	}
+
+	return fmt.Sprintf("%s (once)", o.Device)
+}
+
+// ErrUnknownDevice is an error that indicates a boot device name does not describe a supported boot device.
+type ErrUnknownDevice struct {
+	Device string
+}
+
+func (e ErrUnknownDevice) Error() string {
+	return fmt.Sprintf("unknown boot device %q, must be one of: %s", e.Device, strings.Join(deviceMap[1:], ", "))
+}
@@ -0,0 +1,62 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package boot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDevice(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Device
+	}{
+		{name: "none", expected: DeviceNone},
+		{name: "pxe", expected: DevicePXE},
+		{name: "PXE", expected: DevicePXE},
+		{name: "disk", expected: DeviceDisk},
+		{name: "cd", expected: DeviceCD},
+		{name: "bios", expected: DeviceBIOS},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			device, err := ParseDevice(test.name)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, device)
+		})
+	}
+}
+
+func TestParseDeviceUnknown(t *testing.T) {
+	for _, name := range []string{"unknown", "usb", ""} {
+		device, err := ParseDevice(name)
+		assert.Equal(t, ErrUnknownDevice{Device: name}, err)
+		assert.Equal(t, DeviceUnknown, device)
+	}
+
+	assert.EqualError(t, ErrUnknownDevice{Device: "usb"},
+		`unknown boot device "usb", must be one of: none, pxe, disk, cd, bios`)
+}
+
+func TestOverrideString(t *testing.T) {
+	assert.Equal(t, "none", Override{Device: DeviceNone}.String())
+	assert.Equal(t, "pxe (once)", Override{Device: DevicePXE}.String())
+	assert.Equal(t, "disk (persistent)", Override{Device: DeviceDisk, Persistent: true}.String())
+	assert.Equal(t, "unknown (once)", Override{Device: DeviceUnknown}.String())
+}
@@ -0,0 +1,78 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+)
+
+// Boot override modes reported for hosts instructed to boot from a device on their next boot only, or on every boot
+const (
+	BootModeOnce       = "once"
+	BootModePersistent = "persistent"
+)
+
+// HostBootOverride describes the device a baremetal host is instructed to boot from in place of its boot order.
+type HostBootOverride struct {
+	HostName   string `json:"name"`
+	BMCAddress string `json:"bmcAddress"`
+	NodeID     string `json:"nodeID"`
+	Device     string `json:"device,omitempty"`
+	Mode       string `json:"mode,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// BootOverrides retrieves the boot device override of every host selected by the manager, acting on up to concurrency
+// hosts at once. Hosts that boot according to their boot order report the device none, without a mode. An override is
+// returned for every host; when it cannot be retrieved from some hosts, their overrides record the failure and an
+// ErrHostActionsFailed error is returned alongside them.
+func (m *Manager) BootOverrides(concurrency int) ([]HostBootOverride, error) {
+	overrides := make([]boot.Override, len(m.Hosts))
+
+	getBootOverride := func(i int) HostAction {
+		return func(ctx context.Context, client Client) (string, error) {
+			override, err := client.BootOverride(ctx)
+			overrides[i] = override
+			return override.String(), err
+		}
+	}
+
+	results, err := m.execute("get boot device", concurrency, getBootOverride, m.audit)
+
+	hostOverrides := make([]HostBootOverride, 0, len(m.Hosts))
+	for i, host := range m.Hosts {
+		hostOverride := HostBootOverride{HostName: host.HostName, BMCAddress: host.BMCAddress, NodeID: host.NodeID()}
+
+		if result := results[i]; result.Err != nil {
+			hostOverride.Error = collapse(result.Err)
+		} else {
+			override := overrides[i]
+			hostOverride.Device = override.Device.String()
+			switch {
+			case override.Device == boot.DeviceNone:
+			case override.Persistent:
+				hostOverride.Mode = BootModePersistent
+			default:
+				hostOverride.Mode = BootModeOnce
+			}
+		}
+
+		hostOverrides = append(hostOverrides, hostOverride)
+	}
+
+	return hostOverrides, err
+}
@@ -0,0 +1,67 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+)
+
+func TestBootOverrides(t *testing.T) {
+	host1, rMock1 := newMockHost(t, "node-1")
+	host2, rMock2 := newMockHost(t, "node-2")
+	host3, rMock3 := newMockHost(t, "node-3")
+
+	rMock1.On("BootOverride", host1.Context).Times(1).
+		Return(boot.Override{Device: boot.DevicePXE, Persistent: true}, nil)
+	rMock1.On("NodeID").Return("System.Embedded.1")
+	rMock2.On("BootOverride", host2.Context).Times(1).Return(boot.Override{Device: boot.DeviceNone}, nil)
+	rMock2.On("NodeID").Return("1")
+	rMock3.On("BootOverride", host3.Context).Times(1).
+		Return(boot.Override{}, redfish.ErrRedfishClient{Message: "BMC\nunavailable."})
+	rMock3.On("NodeID").Return("1")
+
+	m := &Manager{Hosts: []baremetalHost{host1, host2, host3}}
+
+	overrides, err := m.BootOverrides(2)
+	_, ok := err.(ErrHostActionsFailed)
+	assert.True(t, ok)
+
+	expected := []HostBootOverride{
+		{
+			HostName:   "node-1",
+			BMCAddress: redfishURL,
+			NodeID:     "System.Embedded.1",
+			Device:     "pxe",
+			Mode:       BootModePersistent,
+		},
+		{HostName: "node-2", BMCAddress: redfishURL, NodeID: "1", Device: "none"},
+		{
+			HostName:   "node-3",
+			BMCAddress: redfishURL,
+			NodeID:     "1",
+			Error:      "redfish client encountered an error: BMC unavailable",
+		},
+	}
+	assert.Equal(t, expected, overrides)
+
+	rMock1.AssertExpectations(t)
+	rMock2.AssertExpectations(t)
+	rMock3.AssertExpectations(t)
+}
@@ -0,0 +1,258 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+// CredentialProvider resolves the BMC credentials of baremetal hosts.
+type CredentialProvider interface {
+	// Credentials returns the BMC username and password of the host defined by a baremetal host document.
+	Credentials(hostDoc document.Document, docBundle document.Bundle) (username string, password string, err error)
+
+	// Source describes where the provider reads credentials from, so that users know where to fix them.
+	Source() string
+}
+
+// NewCredentialProvider returns the credential provider described by a credentials configuration. A nil configuration
+// describes the provider that reads credentials from the Secret documents referenced by baremetal host documents.
+func NewCredentialProvider(cfg *config.CredentialsConfiguration) (CredentialProvider, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	if cfg == nil {
+		return secretCredentials{}, nil
+	}
+
+	switch cfg.Provider {
+	case config.CredentialProviderEnv:
+		prefix := cfg.EnvPrefix
+		if prefix == "" {
+			prefix = config.DefaultCredentialsEnvPrefix
+		}
+
+		return envCredentials{prefix: prefix}, nil
+	case config.CredentialProviderFile:
+		return fileCredentials{path: cfg.Path}, nil
+	case config.CredentialProviderExec:
+		return execCredentials{command: cfg.Command, timeout: cfg.ExecTimeout()}, nil
+	default:
+		return secretCredentials{}, nil
+	}
+}
+
+// secretCredentials reads the credentials of a host from the Secret document named by the spec.bmc.credentialsName
+// field of its baremetal host document.
+type secretCredentials struct{}
+
+func (secretCredentials) Credentials(hostDoc document.Document, docBundle document.Bundle) (string, string, error) {
+	return document.GetBMHBMCCredentials(hostDoc, docBundle)
+}
+
+func (secretCredentials) Source() string {
+	return "the Secret referenced by spec.bmc.credentialsName"
+}
+
+// envCredentials reads the credentials of a host from the <PREFIX>_<HOST>_USERNAME and <PREFIX>_<HOST>_PASSWORD
+// environment variables, where HOST is the name of the host's document in upper case with characters other than
+// letters and digits replaced by underscores, e.g. AIRSHIP_BMC_NODE_01_USERNAME for node-01. The <PREFIX>_USERNAME and
+// <PREFIX>_PASSWORD variables hold the credentials of hosts without variables of their own.
+type envCredentials struct {
+	prefix string
+}
+
+func (c envCredentials) Credentials(hostDoc document.Document, _ document.Bundle) (string, string, error) {
+	hostPrefix := c.prefix + "_" + envName(hostDoc.GetName())
+	for _, prefix := range []string{hostPrefix, c.prefix} {
+		username, hasUsername := os.LookupEnv(prefix + "_USERNAME")
+		password, hasPassword := os.LookupEnv(prefix + "_PASSWORD")
+		if hasUsername && hasPassword {
+			return username, password, nil
+		}
+	}
+
+	return "", "", ErrCredentialsNotFound{
+		HostName: hostDoc.GetName(),
+		Provider: config.CredentialProviderEnv,
+		Reason: fmt.Sprintf("set %[1]s_USERNAME and %[1]s_PASSWORD, or %[2]s_USERNAME and %[2]s_PASSWORD",
+			hostPrefix, c.prefix),
+	}
+}
+
+func (c envCredentials) Source() string {
+	return fmt.Sprintf("the %s_*USERNAME and %s_*PASSWORD environment variables", c.prefix, c.prefix)
+}
+
+// envName converts a host name to the form used in environment variable names.
+func envName(hostName string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, hostName)
+}
+
+// fileCredentials reads the credentials of hosts from a local file or directory. A directory holds a directory per
+// host, named after the host's document, containing username and password files, as laid out when a Kubernetes Secret
+// is mounted as a volume. A file maps the names of host documents to their credentials in YAML or JSON, e.g.
+//
+//	node-01:
+//	  username: admin
+//	  password: secret
+type fileCredentials struct {
+	path string
+}
+
+func (c fileCredentials) Credentials(hostDoc document.Document, _ document.Bundle) (string, string, error) {
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return "", "", err
+	}
+
+	if info.IsDir() {
+		return c.fromDirectory(hostDoc.GetName())
+	}
+
+	return c.fromFile(hostDoc.GetName())
+}
+
+func (c fileCredentials) Source() string {
+	return fmt.Sprintf("credentials file '%s'", c.path)
+}
+
+func (c fileCredentials) fromDirectory(hostName string) (string, string, error) {
+	var values []string
+	for _, key := range []string{"username", "password"} {
+		data, err := ioutil.ReadFile(filepath.Join(c.path, hostName, key))
+		if os.IsNotExist(err) {
+			return "", "", ErrCredentialsNotFound{
+				HostName: hostName,
+				Provider: config.CredentialProviderFile,
+				Reason:   fmt.Sprintf("%s does not exist", filepath.Join(c.path, hostName, key)),
+			}
+		}
+		if err != nil {
+			return "", "", err
+		}
+
+		values = append(values, strings.TrimRight(string(data), "\r\n"))
+	}
+
+	return values[0], values[1], nil
+}
+
+func (c fileCredentials) fromFile(hostName string) (string, string, error) {
+	data, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return "", "", err
+	}
+
+	var hosts map[string]bmcCredentials
+	if err = yaml.Unmarshal(data, &hosts); err != nil {
+		return "", "", err
+	}
+
+	creds, ok := hosts[hostName]
+	if !ok || creds.Username == "" || creds.Password == "" {
+		return "", "", ErrCredentialsNotFound{
+			HostName: hostName,
+			Provider: config.CredentialProviderFile,
+			Reason:   fmt.Sprintf("%s has no username and password for the host", c.path),
+		}
+	}
+
+	return creds.Username, creds.Password, nil
+}
+
+// execCredentials reads the credentials of hosts from the output of an external command, e.g. a wrapper around a
+// password manager. The command is run once per host, with the name of the host's document appended to its arguments
+// and the AIRSHIP_BMC_HOST, AIRSHIP_BMC_NAMESPACE and AIRSHIP_BMC_ADDRESS environment variables set, and must print
+// the credentials as {"username": "...", "password": "..."} to its standard output.
+type execCredentials struct {
+	command []string
+	timeout time.Duration
+}
+
+func (c execCredentials) Credentials(hostDoc document.Document, _ document.Bundle) (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	// The BMC address is optional here; a missing address is reported when the host's client is created.
+	address, _ := document.GetBMHBMCAddress(hostDoc)
+
+	args := append(append([]string{}, c.command[1:]...), hostDoc.GetName())
+	cmd := exec.CommandContext(ctx, c.command[0], args...) //nolint:gosec
+	cmd.Env = append(os.Environ(),
+		"AIRSHIP_BMC_HOST="+hostDoc.GetName(),
+		"AIRSHIP_BMC_NAMESPACE="+hostDoc.GetNamespace(),
+		"AIRSHIP_BMC_ADDRESS="+address)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = ctx.Err()
+		}
+
+		return "", "", ErrCredentialCommandFailed{
+			HostName: hostDoc.GetName(),
+			Err:      err,
+			Stderr:   strings.TrimSpace(stderr.String()),
+		}
+	}
+
+	var creds bmcCredentials
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil || creds.Username == "" || creds.Password == "" {
+		return "", "", ErrCredentialsNotFound{
+			HostName: hostDoc.GetName(),
+			Provider: config.CredentialProviderExec,
+			Reason:   "the command did not print a JSON object with a username and a password",
+		}
+	}
+
+	return creds.Username, creds.Password, nil
+}
+
+func (c execCredentials) Source() string {
+	return fmt.Sprintf("credentials command '%s'", strings.Join(c.command, " "))
+}
+
+// bmcCredentials is the representation of BMC credentials read by the file and exec providers.
+type bmcCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
@@ -0,0 +1,208 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+)
+
+// testCredentials resolves the credentials of a host of the base test site with the provider described by cfg.
+func testCredentials(t *testing.T, cfg *config.CredentialsConfiguration,
+	hostName string) (string, string, error) {
+	t.Helper()
+
+	docBundle, err := document.NewBundleByPath(bootstrapDocsPath)
+	require.NoError(t, err)
+
+	credentials, err := NewCredentialProvider(cfg)
+	require.NoError(t, err)
+
+	return credentials.Credentials(selectHostDoc(t, docBundle, hostName), docBundle)
+}
+
+// setenv sets an environment variable, returning a function that unsets it.
+func setenv(t *testing.T, key, value string) func() {
+	t.Helper()
+
+	require.NoError(t, os.Setenv(key, value))
+	return func() { os.Unsetenv(key) }
+}
+
+func TestSecretCredentials(t *testing.T) {
+	username, password, err := testCredentials(t, nil, "master-0")
+	require.NoError(t, err)
+
+	assert.Equal(t, "admin", username)
+	assert.Equal(t, "password", password)
+}
+
+func TestEnvCredentials(t *testing.T) {
+	cfg := &config.CredentialsConfiguration{Provider: config.CredentialProviderEnv, EnvPrefix: "AIRSHIPCTL_TEST_BMC"}
+
+	_, _, err := testCredentials(t, cfg, "master-1")
+	assert.Equal(t, ErrCredentialsNotFound{
+		HostName: "master-1",
+		Provider: config.CredentialProviderEnv,
+		Reason: "set AIRSHIPCTL_TEST_BMC_MASTER_1_USERNAME and AIRSHIPCTL_TEST_BMC_MASTER_1_PASSWORD, or " +
+			"AIRSHIPCTL_TEST_BMC_USERNAME and AIRSHIPCTL_TEST_BMC_PASSWORD",
+	}, err)
+
+	defer setenv(t, "AIRSHIPCTL_TEST_BMC_USERNAME", "root")()
+	defer setenv(t, "AIRSHIPCTL_TEST_BMC_PASSWORD", "calvin")()
+	defer setenv(t, "AIRSHIPCTL_TEST_BMC_MASTER_1_USERNAME", "operator")()
+	defer setenv(t, "AIRSHIPCTL_TEST_BMC_MASTER_1_PASSWORD", "s3cret")()
+
+	username, password, err := testCredentials(t, cfg, "master-1")
+	require.NoError(t, err)
+	assert.Equal(t, "operator", username)
+	assert.Equal(t, "s3cret", password)
+
+	username, password, err = testCredentials(t, cfg, "master-2")
+	require.NoError(t, err)
+	assert.Equal(t, "root", username)
+	assert.Equal(t, "calvin", password)
+}
+
+func TestFileCredentialsDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airshipctl-credentials-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "master-1"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "master-1", "username"), []byte("operator\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "master-1", "password"), []byte("s3cret\n"), 0600))
+
+	cfg := &config.CredentialsConfiguration{Provider: config.CredentialProviderFile, Path: dir}
+
+	username, password, err := testCredentials(t, cfg, "master-1")
+	require.NoError(t, err)
+	assert.Equal(t, "operator", username)
+	assert.Equal(t, "s3cret", password)
+
+	_, _, err = testCredentials(t, cfg, "master-2")
+	assert.IsType(t, ErrCredentialsNotFound{}, err)
+}
+
+func TestFileCredentialsFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "airshipctl-credentials-")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString("master-1:\n  username: operator\n  password: s3cret\nmaster-2:\n  username: root\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	cfg := &config.CredentialsConfiguration{Provider: config.CredentialProviderFile, Path: file.Name()}
+
+	username, password, err := testCredentials(t, cfg, "master-1")
+	require.NoError(t, err)
+	assert.Equal(t, "operator", username)
+	assert.Equal(t, "s3cret", password)
+
+	for _, hostName := range []string{"master-2", "no-creds"} {
+		_, _, err = testCredentials(t, cfg, hostName)
+		assert.IsType(t, ErrCredentialsNotFound{}, err)
+	}
+}
+
+func TestExecCredentials(t *testing.T) {
+	cfg := &config.CredentialsConfiguration{
+		Provider: config.CredentialProviderExec,
+		Command: []string{"sh", "-c",
+			`printf '{"username": "%s", "password": "%s"}' "$1" "$AIRSHIP_BMC_ADDRESS"`, "sh"},
+	}
+
+	username, password, err := testCredentials(t, cfg, "master-1")
+	require.NoError(t, err)
+	assert.Equal(t, "master-1", username)
+	assert.Equal(t, "redfish+http://nolocalhost:8888/redfish/v1/Systems/node-master-1", password)
+}
+
+func TestExecCredentialsFailed(t *testing.T) {
+	cfg := &config.CredentialsConfiguration{
+		Provider: config.CredentialProviderExec,
+		Command:  []string{"sh", "-c", "echo 'vault is sealed' >&2; exit 2"},
+	}
+
+	_, _, err := testCredentials(t, cfg, "master-1")
+	require.IsType(t, ErrCredentialCommandFailed{}, err)
+	assert.Equal(t, "vault is sealed", err.(ErrCredentialCommandFailed).Stderr)
+
+	cfg.Command = []string{"sh", "-c", "echo 'not json'"}
+
+	_, _, err = testCredentials(t, cfg, "master-1")
+	assert.IsType(t, ErrCredentialsNotFound{}, err)
+}
+
+func TestNewCredentialProviderInvalid(t *testing.T) {
+	_, err := NewCredentialProvider(&config.CredentialsConfiguration{Provider: config.CredentialProviderFile})
+	assert.IsType(t, config.ErrInvalidCredentialsConfiguration{}, err)
+}
+
+func TestCredentialProviderSource(t *testing.T) {
+	tests := []struct {
+		cfg      *config.CredentialsConfiguration
+		expected string
+	}{
+		{expected: "the Secret referenced by spec.bmc.credentialsName"},
+		{
+			cfg:      &config.CredentialsConfiguration{Provider: config.CredentialProviderEnv},
+			expected: "the AIRSHIP_BMC_*USERNAME and AIRSHIP_BMC_*PASSWORD environment variables",
+		},
+		{
+			cfg:      &config.CredentialsConfiguration{Provider: config.CredentialProviderFile, Path: "/etc/bmc"},
+			expected: "credentials file '/etc/bmc'",
+		},
+		{
+			cfg: &config.CredentialsConfiguration{Provider: config.CredentialProviderExec,
+				Command: []string{"vault-bmc", "--site", "test-site"}},
+			expected: "credentials command 'vault-bmc --site test-site'",
+		},
+	}
+
+	for _, tt := range tests {
+		provider, err := NewCredentialProvider(tt.cfg)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, provider.Source())
+	}
+}
+
+func TestNewManagerEnvCredentials(t *testing.T) {
+	defer setenv(t, "AIRSHIP_BMC_NO_CREDS_USERNAME", "operator")()
+	defer setenv(t, "AIRSHIP_BMC_NO_CREDS_PASSWORD", "s3cret")()
+
+	cfg := &config.ManagementConfiguration{
+		Type:        redfish.ClientType,
+		Credentials: &config.CredentialsConfiguration{Provider: config.CredentialProviderEnv},
+	}
+	settings := initSettings(t, withManagementConfig(cfg), withTestDataPath("base"))
+
+	manager, err := NewManager(settings, config.BootstrapPhase, ByName("no-creds"))
+	require.NoError(t, err)
+	require.Len(t, manager.Hosts, 1)
+
+	assert.Equal(t, "operator", manager.Hosts[0].username)
+	assert.Equal(t, "s3cret", manager.Hosts[0].password)
+}
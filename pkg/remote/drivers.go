@@ -0,0 +1,94 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish/vendors"
+	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
+)
+
+const (
+	driverIPMI    = "ipmi"
+	driverRedfish = "redfish"
+
+	transportHTTP  = "http"
+	transportHTTPS = "https"
+)
+
+// bmcDrivers maps the drivers of metal3 BMC address schemes to the management types of the clients that drive them.
+// Drivers mapped to an empty management type name a protocol but no vendor, and are driven by the default management
+// type of the management configuration.
+var bmcDrivers = map[string]string{
+	driverIPMI:             ipmi.ClientType,
+	driverRedfish:          "",
+	"redfish-virtualmedia": "",
+	"idrac-redfish":        redfishdell.ClientType,
+	"idrac-virtualmedia":   redfishdell.ClientType,
+	"ilo5-redfish":         redfish.ClientType,
+	"ilo5-virtualmedia":    redfish.ClientType,
+}
+
+// parseBMCScheme splits the scheme of a BMC address into a driver and a transport as metal3 does, e.g.
+// idrac-virtualmedia+https://10.23.25.1/redfish/v1/Systems/System.Embedded.1. Addresses without a scheme are IPMI
+// addresses, plain http and https schemes are Redfish, and Redfish drivers without an explicit transport use HTTPS.
+func parseBMCScheme(address string) (driver string, transport string) {
+	i := strings.Index(address, "://")
+	if i < 0 {
+		return driverIPMI, ""
+	}
+
+	scheme := strings.ToLower(address[:i])
+	if scheme == transportHTTP || scheme == transportHTTPS {
+		return driverRedfish, scheme
+	}
+
+	parts := strings.SplitN(scheme, "+", 2)
+	switch {
+	case len(parts) == 2:
+		return parts[0], parts[1]
+	case parts[0] == driverIPMI:
+		return driverIPMI, ""
+	default:
+		return parts[0], transportHTTPS
+	}
+}
+
+// hostManagementType selects the management type of the client that drives the BMC of a host from the scheme of its
+// BMC address, so that hosts of different vendors can be managed with one management configuration. Schemes that name
+// a protocol but no vendor, e.g. redfish+https, select defaultType when it is a Redfish management type, and the
+// generic Redfish client otherwise.
+func hostManagementType(hostName, address, defaultType string) (string, error) {
+	driver, transport := parseBMCScheme(address)
+
+	mgmtType, known := bmcDrivers[driver]
+	switch {
+	case !known, driver == driverIPMI && transport != "",
+		driver != driverIPMI && transport != transportHTTP && transport != transportHTTPS:
+		return "", ErrUnsupportedBMCAddress{HostName: hostName, Address: address}
+	case mgmtType != "":
+		return mgmtType, nil
+	}
+
+	switch defaultType {
+	case redfish.ClientType, redfishdell.ClientType, vendors.ClientType:
+		return defaultType, nil
+	default:
+		return redfish.ClientType, nil
+	}
+}
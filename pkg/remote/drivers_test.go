@@ -0,0 +1,100 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish/vendors"
+	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
+)
+
+func TestHostManagementType(t *testing.T) {
+	tests := []struct {
+		address     string
+		defaultType string
+		expected    string
+	}{
+		{address: "ipmi://10.23.25.1", defaultType: redfish.ClientType, expected: ipmi.ClientType},
+		{address: "10.23.25.1:623", defaultType: redfish.ClientType, expected: ipmi.ClientType},
+		{address: "idrac-virtualmedia://10.23.25.1/redfish/v1/Systems/System.Embedded.1",
+			defaultType: redfish.ClientType, expected: redfishdell.ClientType},
+		{address: "idrac-redfish+http://10.23.25.1/redfish/v1/Systems/System.Embedded.1",
+			defaultType: ipmi.ClientType, expected: redfishdell.ClientType},
+		{address: "ilo5-virtualmedia://10.23.25.1/redfish/v1/Systems/1",
+			defaultType: redfishdell.ClientType, expected: redfish.ClientType},
+		{address: "redfish+https://10.23.25.1/redfish/v1/Systems/1",
+			defaultType: redfishdell.ClientType, expected: redfishdell.ClientType},
+		{address: "redfish-virtualmedia://10.23.25.1/redfish/v1/Systems/1",
+			defaultType: vendors.ClientType, expected: vendors.ClientType},
+		{address: "https://10.23.25.1/redfish/v1/Systems/1",
+			defaultType: ipmi.ClientType, expected: redfish.ClientType},
+	}
+
+	for _, tt := range tests {
+		mgmtType, err := hostManagementType("node-0", tt.address, tt.defaultType)
+		require.NoError(t, err, tt.address)
+		assert.Equal(t, tt.expected, mgmtType, tt.address)
+	}
+}
+
+func TestHostManagementTypeUnsupported(t *testing.T) {
+	for _, address := range []string{
+		"idrac://10.23.25.1",
+		"irmc://10.23.25.1",
+		"libvirt://10.23.25.1",
+		"ipmi+https://10.23.25.1",
+		"redfish+ftp://10.23.25.1/redfish/v1/Systems/1",
+	} {
+		_, err := hostManagementType("node-0", address, redfish.ClientType)
+		assert.Equal(t, ErrUnsupportedBMCAddress{HostName: "node-0", Address: address}, err)
+	}
+}
+
+func TestNewManagerHostManagementTypes(t *testing.T) {
+	defer setenv(t, "AIRSHIP_BMC_USERNAME", "admin")()
+	defer setenv(t, "AIRSHIP_BMC_PASSWORD", "password")()
+
+	cfg := &config.ManagementConfiguration{
+		Type:        redfishdell.ClientType,
+		Credentials: &config.CredentialsConfiguration{Provider: config.CredentialProviderEnv},
+	}
+	settings := initSettings(t, withManagementConfig(cfg), withTestDataPath("drivers"))
+
+	manager, err := NewManager(settings, config.BootstrapPhase, ByLabel("airshipit.org/test-node=true"))
+	require.NoError(t, err)
+	require.Len(t, manager.Hosts, 4)
+
+	assert.IsType(t, &redfishdell.Client{}, manager.Hosts[0].Client)
+	assert.IsType(t, &redfish.Client{}, manager.Hosts[1].Client)
+	assert.IsType(t, &redfishdell.Client{}, manager.Hosts[2].Client)
+	assert.IsType(t, &ipmi.Client{}, manager.Hosts[3].Client)
+
+	// The resolved management type and the source of the credentials are kept for dry run
+	assert.Equal(t, redfishdell.ClientType, manager.Hosts[0].ManagementType)
+	assert.Equal(t, redfish.ClientType, manager.Hosts[1].ManagementType)
+	assert.Equal(t, ipmi.ClientType, manager.Hosts[3].ManagementType)
+	assert.Equal(t, "the AIRSHIP_BMC_*USERNAME and AIRSHIP_BMC_*PASSWORD environment variables",
+		manager.Hosts[0].CredentialsSource)
+
+	_, err = NewManager(settings, config.BootstrapPhase, ByName("irmc-0"))
+	assert.IsType(t, ErrUnsupportedBMCAddress{}, err)
+}
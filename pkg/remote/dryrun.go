@@ -0,0 +1,76 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+)
+
+// dryRunClient is implemented by clients that can record the requests they would send to a BMC instead of sending
+// the requests that change the state of the host, e.g. Redfish clients.
+type dryRunClient interface {
+	EnableDryRun()
+	DryRun() bool
+	DryRunCalls() []redfish.Call
+}
+
+// DryRun makes the clients of the manager's hosts record the requests actions make instead of sending those that
+// change the state of the hosts to their BMCs; requests that only retrieve information are still sent. The requests
+// made by an action on each host are returned in its result. Actions performed in dry run are not recorded in the
+// audit log. An ErrDryRunUnsupported error is returned when the client of any host cannot record its requests, e.g.
+// an IPMI client.
+func (m *Manager) DryRun() error {
+	for _, host := range m.Hosts {
+		if _, ok := host.Client.(dryRunClient); !ok {
+			return ErrDryRunUnsupported{HostName: host.HostName, BMCAddress: host.BMCAddress}
+		}
+	}
+
+	for _, host := range m.Hosts {
+		host.Client.(dryRunClient).EnableDryRun()
+	}
+
+	m.audit = nil
+
+	return nil
+}
+
+// DryRun reports whether the client of the host is in dry run.
+func (b baremetalHost) DryRun() bool {
+	client, ok := b.Client.(dryRunClient)
+	return ok && client.DryRun()
+}
+
+// DryRunCalls returns the requests made to the BMC of the host in dry run since DryRunCalls was last called, in the
+// order they were made. No calls are returned when the host is not in dry run.
+func (b baremetalHost) DryRunCalls() []redfish.Call {
+	client, ok := b.Client.(dryRunClient)
+	if !ok {
+		return nil
+	}
+
+	return client.DryRunCalls()
+}
+
+// record records an action performed on the host outside of a manager, and its outcome, in the audit log of the
+// airshipctl settings. Actions performed in dry run are not recorded.
+func (b baremetalHost) record(settings *environment.AirshipCTLSettings, action string, err error) {
+	if b.DryRun() {
+		return
+	}
+
+	NewAuditLog(settings).Record(b.HostName, b.BMCAddress, action, err)
+}
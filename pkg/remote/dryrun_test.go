@@ -0,0 +1,54 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
+)
+
+func TestManagerDryRun(t *testing.T) {
+	defer setenv(t, "AIRSHIP_BMC_USERNAME", "admin")()
+	defer setenv(t, "AIRSHIP_BMC_PASSWORD", "password")()
+
+	cfg := &config.ManagementConfiguration{
+		Type:        redfishdell.ClientType,
+		Credentials: &config.CredentialsConfiguration{Provider: config.CredentialProviderEnv},
+	}
+	settings := initSettings(t, withManagementConfig(cfg), withTestDataPath("drivers"))
+
+	manager, err := NewManager(settings, config.BootstrapPhase, AnyOf(ByName("dell-0"), ByName("hpe-0")))
+	require.NoError(t, err)
+	manager.audit = &AuditLog{Path: "does-not-exist"}
+
+	require.NoError(t, manager.DryRun())
+	assert.Nil(t, manager.audit)
+	for _, host := range manager.Hosts {
+		assert.True(t, host.DryRun(), host.HostName)
+		assert.Empty(t, host.DryRunCalls(), host.HostName)
+	}
+
+	manager, err = NewManager(settings, config.BootstrapPhase, AnyOf(ByName("dell-0"), ByName("ipmi-0")))
+	require.NoError(t, err)
+
+	err = manager.DryRun()
+	assert.Equal(t, ErrDryRunUnsupported{HostName: "ipmi-0", BMCAddress: "ipmi://10.23.25.4:623"}, err)
+	assert.False(t, manager.Hosts[0].DryRun())
+}
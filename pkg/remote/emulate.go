@@ -0,0 +1,68 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish/emulator"
+)
+
+// EmulateHosts starts emulating the BMCs of the hosts of a phase that the supplied selector selects. Each BMC is
+// served at the BMC address of its host, or on bindAddress when set, and accepts the BMC credentials of its host. No
+// management client is created, so the BMCs need not be reachable beforehand.
+func EmulateHosts(settings *environment.AirshipCTLSettings, phase, bindAddress string,
+	selector HostSelector) (*emulator.Emulator, error) {
+	mgmtCfg, docBundle, err := loadPhase(settings, phase)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := NewCredentialProvider(mgmtCfg.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := selector(docBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, ErrNoHostsFound{}
+	}
+
+	hosts := make([]emulator.Host, 0, len(docs))
+	for _, doc := range docs {
+		address, err := document.GetBMHBMCAddress(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		username, password, err := credentials.Credentials(doc, docBundle)
+		if err != nil {
+			return nil, err
+		}
+
+		hosts = append(hosts, emulator.Host{
+			Name:     doc.GetName(),
+			Address:  address,
+			Username: username,
+			Password: password,
+		})
+	}
+
+	return emulator.NewEmulator(bindAddress, hosts...)
+}
@@ -0,0 +1,43 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+func TestEmulateHosts(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("base"))
+
+	e, err := EmulateHosts(settings, config.BootstrapPhase, "127.0.0.1", ByLabel(document.EphemeralHostSelector))
+	require.NoError(t, err)
+	defer e.Close()
+
+	assert.Equal(t, []string{"master-0"}, e.HostNames())
+	assert.Equal(t, "redfish+http://nolocalhost:8888/redfish/v1/Systems/ephemeral", e.Address("master-0"))
+}
+
+func TestEmulateHostsNotFound(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("base"))
+
+	_, err := EmulateHosts(settings, config.BootstrapPhase, "127.0.0.1", ByName("does-not-exist"))
+	assert.Equal(t, ErrNoHostsFound{}, err)
+}
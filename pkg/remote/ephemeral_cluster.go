@@ -0,0 +1,144 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
+)
+
+// DefaultEphemeralClusterTimeout bounds how long the ephemeral cluster is waited for when no timeout is specified.
+const DefaultEphemeralClusterTimeout = 30 * time.Minute
+
+// WaitForEphemeralCluster waits for the ephemeral cluster bootstrapped by DoRemoteDirect to come up. It polls the API
+// server of the cluster, using a client created by factory from the kubeconfig of the airshipctl settings, until the
+// API server answers and every node of the cluster reports Ready. When eject is true, the virtual media of the
+// ephemeral host is ejected once the cluster is ready, and the outcome of the ejection is recorded in the audit log.
+// The wait stops as soon as the context of the host is cancelled, e.g. when airshipctl is interrupted.
+func (b baremetalHost) WaitForEphemeralCluster(settings *environment.AirshipCTLSettings, factory client.Factory,
+	policy retry.Policy, eject bool) error {
+	kclient, err := factory(settings)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Waiting for the ephemeral cluster of host '%s' to become ready.", b.HostName)
+
+	err = policy.Poll(b.Context, "wait for the ephemeral cluster to become ready", sleepContext(b.Context),
+		func(ctx context.Context) (bool, error) {
+			return nodesReady(ctx, kclient), nil
+		})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Ephemeral cluster of host '%s' is ready.", b.HostName)
+
+	if !eject {
+		return nil
+	}
+
+	err = b.EjectVirtualMedia(b.Context)
+	b.record(settings, "eject media", err)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Ejected virtual media of host '%s'.", b.HostName)
+	return nil
+}
+
+// sleepContext returns a function that sleeps for the supplied duration, or until ctx is done.
+func sleepContext(ctx context.Context) func(time.Duration) {
+	return func(d time.Duration) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(d):
+		}
+	}
+}
+
+// nodesReady reports whether the API server of a cluster answers and every node of the cluster reports Ready. The
+// request for the nodes is bounded by the deadline of ctx, and the cluster is reported not ready as soon as ctx is
+// done, since the client of the cluster does not accept a context.
+func nodesReady(ctx context.Context, kclient client.Interface) bool {
+	opts := metav1.ListOptions{}
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := int64(math.Ceil(time.Until(deadline).Seconds()))
+		if timeout < 1 {
+			timeout = 1
+		}
+		opts.TimeoutSeconds = &timeout
+	}
+
+	type listResult struct {
+		nodes *corev1.NodeList
+		err   error
+	}
+
+	results := make(chan listResult, 1)
+	go func() {
+		nodes, err := kclient.ClientSet().CoreV1().Nodes().List(opts)
+		results <- listResult{nodes: nodes, err: err}
+	}()
+
+	var result listResult
+	select {
+	case <-ctx.Done():
+		log.Debugf("Stopped waiting for the ephemeral cluster API server: %v", ctx.Err())
+		return false
+	case result = <-results:
+	}
+
+	nodes, err := result.nodes, result.err
+	if err != nil {
+		// The API server does not answer until the ephemeral host has booted and started it
+		log.Debugf("Ephemeral cluster API server is not available yet: %v", err)
+		return false
+	}
+
+	if len(nodes.Items) == 0 {
+		log.Debug("Ephemeral cluster has no nodes yet.")
+		return false
+	}
+
+	for _, node := range nodes.Items {
+		if !nodeReady(node) {
+			log.Debugf("Ephemeral cluster node '%s' is not ready yet.", node.Name)
+			return false
+		}
+	}
+
+	return true
+}
+
+func nodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
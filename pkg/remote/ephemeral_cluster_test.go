@@ -0,0 +1,117 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/k8s/client"
+	"opendev.org/airship/airshipctl/pkg/k8s/client/fake"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
+)
+
+var ephemeralClusterPolicy = retry.Policy{Timeout: 50 * time.Millisecond, Interval: 10 * time.Millisecond}
+
+func newNode(name string, status corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func fakeClusterFactory(objs ...runtime.Object) client.Factory {
+	return func(_ *environment.AirshipCTLSettings) (client.Interface, error) {
+		return fake.NewClient(fake.WithTypedObjects(objs...)), nil
+	}
+}
+
+func TestWaitForEphemeralCluster(t *testing.T) {
+	host, rMock := newMockHost(t, "ephemeral")
+	rMock.On("EjectVirtualMedia", host.Context).Times(1).Return(nil)
+
+	factory := fakeClusterFactory(newNode("ephemeral", corev1.ConditionTrue))
+	err := host.WaitForEphemeralCluster(nil, factory, ephemeralClusterPolicy, true)
+	assert.NoError(t, err)
+
+	rMock.AssertExpectations(t)
+}
+
+func TestWaitForEphemeralClusterNotReady(t *testing.T) {
+	tests := []struct {
+		name string
+		objs []runtime.Object
+	}{
+		{name: "NoNodes"},
+		{name: "NodeNotReady", objs: []runtime.Object{
+			newNode("ephemeral", corev1.ConditionTrue),
+			newNode("worker", corev1.ConditionFalse),
+		}},
+		{name: "NoReadyCondition", objs: []runtime.Object{&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "ephemeral"}}}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			// Virtual media is not ejected until the cluster is ready
+			host, rMock := newMockHost(t, "ephemeral")
+
+			err := host.WaitForEphemeralCluster(nil, fakeClusterFactory(tt.objs...), ephemeralClusterPolicy, true)
+			assert.IsType(t, retry.ErrTimeout{}, err)
+
+			rMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWaitForEphemeralClusterInterrupted(t *testing.T) {
+	host, rMock := newMockHost(t, "ephemeral")
+
+	ctx, cancel := context.WithCancel(host.Context)
+	host.Context = ctx
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	// The wait stops once the host's context is cancelled rather than after the poll interval
+	policy := retry.Policy{Timeout: time.Minute, Interval: time.Minute}
+
+	start := time.Now()
+	err := host.WaitForEphemeralCluster(nil, fakeClusterFactory(), policy, true)
+	assert.Equal(t, context.Canceled, err)
+	assert.True(t, time.Since(start) < 10*time.Second)
+
+	rMock.AssertExpectations(t)
+}
+
+func TestWaitForEphemeralClusterClientError(t *testing.T) {
+	host, _ := newMockHost(t, "ephemeral")
+
+	expected := errors.New("invalid kubeconfig")
+	factory := func(_ *environment.AirshipCTLSettings) (client.Interface, error) {
+		return nil, expected
+	}
+
+	err := host.WaitForEphemeralCluster(nil, factory, ephemeralClusterPolicy, false)
+	assert.Equal(t, expected, err)
+}
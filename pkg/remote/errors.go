@@ -16,6 +16,7 @@ package remote
 
 import (
 	"fmt"
+	"strings"
 
 	aerror "opendev.org/airship/airshipctl/pkg/errors"
 )
@@ -57,9 +58,146 @@ func (e ErrMissingBootstrapInfoOption) Error() string {
 	return fmt.Sprintf("missing bootstrapInfo option: %s", e.What)
 }
 
+// ErrUnsupportedBMCAddress is an error that indicates the scheme of the BMC address of a host names a driver or
+// transport that airshipctl does not support.
+type ErrUnsupportedBMCAddress struct {
+	aerror.AirshipError
+	HostName string
+	Address  string
+}
+
+func (e ErrUnsupportedBMCAddress) Error() string {
+	return fmt.Sprintf("unsupported BMC address '%s' for host '%s': supported schemes are ipmi, redfish, "+
+		"redfish-virtualmedia, idrac-redfish, idrac-virtualmedia, ilo5-redfish and ilo5-virtualmedia, the Redfish "+
+		"ones optionally followed by +http or +https", e.Address, e.HostName)
+}
+
 // ErrNoHostsFound is an error that indicates that no hosts matched the selection criteria passed to a manager.
 type ErrNoHostsFound struct{}
 
 func (e ErrNoHostsFound) Error() string {
 	return "no hosts selected"
 }
+
+// ErrInvalidBMCAddressPattern is an error that indicates a BMC address pattern used to select hosts is not a valid
+// regular expression.
+type ErrInvalidBMCAddressPattern struct {
+	aerror.AirshipError
+	Pattern string
+	Err     error
+}
+
+func (e ErrInvalidBMCAddressPattern) Error() string {
+	return fmt.Sprintf("invalid BMC address pattern '%s': %v", e.Pattern, e.Err)
+}
+
+// ErrCredentialsNotFound is an error that indicates a credential provider could not find the BMC credentials of a
+// host.
+type ErrCredentialsNotFound struct {
+	aerror.AirshipError
+	HostName string
+	Provider string
+	Reason   string
+}
+
+func (e ErrCredentialsNotFound) Error() string {
+	return fmt.Sprintf("%s credential provider found no BMC credentials for host '%s': %s", e.Provider, e.HostName,
+		e.Reason)
+}
+
+// ErrCredentialCommandFailed is an error that indicates the command of the exec credential provider failed while
+// retrieving the BMC credentials of a host.
+type ErrCredentialCommandFailed struct {
+	aerror.AirshipError
+	HostName string
+	Err      error
+	Stderr   string
+}
+
+func (e ErrCredentialCommandFailed) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("credential command failed for host '%s': %v", e.HostName, e.Err)
+	}
+
+	return fmt.Sprintf("credential command failed for host '%s': %v: %s", e.HostName, e.Err, e.Stderr)
+}
+
+// ErrDryRunUnsupported is an error that indicates the client of a host cannot record the requests it would send to
+// the BMC of the host, so actions cannot be performed on the host in dry run.
+type ErrDryRunUnsupported struct {
+	aerror.AirshipError
+	HostName   string
+	BMCAddress string
+}
+
+func (e ErrDryRunUnsupported) Error() string {
+	return fmt.Sprintf("dry run is not supported for host '%s' with BMC address '%s': only Redfish requests can be "+
+		"recorded", e.HostName, e.BMCAddress)
+}
+
+// ErrNoBIOSSettings is an error that indicates no BIOS attributes were supplied for a host, either on the command line
+// or by a HostFirmwareSettings document.
+type ErrNoBIOSSettings struct{}
+
+func (e ErrNoBIOSSettings) Error() string {
+	return "no BIOS attributes supplied and no HostFirmwareSettings document found"
+}
+
+// ErrHostActionsFailed is an error that indicates an action performed by a manager failed on one or more of its hosts.
+type ErrHostActionsFailed struct {
+	Action   string
+	Hosts    int
+	Failures []HostResult
+}
+
+func (e ErrHostActionsFailed) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s failed on %d of %d host(s):", e.Action, len(e.Failures), e.Hosts)
+	for _, failure := range e.Failures {
+		fmt.Fprintf(&b, "\n  %s (%s): %v", failure.HostName, failure.BMCAddress, failure.Err)
+	}
+
+	return b.String()
+}
+
+// ErrBootMACAddressMismatch is an error that indicates the boot MAC address defined in the baremetal host documents of
+// one or more hosts does not match any of their NICs.
+type ErrBootMACAddressMismatch struct {
+	Mismatches []HostInventory
+}
+
+func (e ErrBootMACAddressMismatch) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "boot MAC address does not match any NIC on %d host(s):", len(e.Mismatches))
+	for _, mismatch := range e.Mismatches {
+		fmt.Fprintf(&b, "\n  %s (%s): %s", mismatch.HostName, mismatch.BMCAddress, mismatch.BootMACAddress)
+	}
+
+	return b.String()
+}
+
+// ErrHostVerificationFailed is an error that indicates one or more verification checks failed on one or more hosts.
+type ErrHostVerificationFailed struct {
+	Hosts    int
+	Failures []HostVerification
+}
+
+func (e ErrHostVerificationFailed) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "verification failed on %d of %d host(s):", len(e.Failures), e.Hosts)
+	for _, failure := range e.Failures {
+		for _, check := range failure.Checks {
+			if check.Status == CheckFailed {
+				fmt.Fprintf(&b, "\n  %s (%s): %s: %s", failure.HostName, failure.BMCAddress, check.Name, check.Reason)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// collapse joins the lines of an error message, dropping its final period, so that multi-line BMC error messages read
+// as a single line in verification reasons and in YAML and JSON documents.
+func collapse(err error) string {
+	return strings.TrimSuffix(strings.Join(strings.Fields(err.Error()), " "), ".")
+}
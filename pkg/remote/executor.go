@@ -0,0 +1,135 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+)
+
+// DefaultConcurrency is the number of hosts an action is performed on at once when no concurrency is specified. It is
+// kept low enough not to saturate the management network or the BMCs of a rack.
+const DefaultConcurrency = 4
+
+// HostAction is an out-of-band operation performed on a single baremetal host. Actions that retrieve information
+// from a host, e.g. its power status, may return a short description of it to be recorded in the host's result.
+type HostAction func(ctx context.Context, client Client) (string, error)
+
+// HostResult records the outcome of an action performed on a single baremetal host.
+type HostResult struct {
+	HostName   string
+	BMCAddress string
+	Action     string
+	Output     string
+	Err        error
+	Duration   time.Duration
+
+	// Calls holds the requests the action made in dry run, in the order they were made, along with the management
+	// type and the source of the credentials of the host they were made to.
+	Calls             []redfish.Call
+	ManagementType    string
+	CredentialsSource string
+}
+
+// Execute performs an action on every host selected by the manager, acting on up to concurrency hosts at once. A
+// failure on one host does not prevent the action from being performed on the remaining hosts. The results are
+// returned in the order the hosts were selected; when the action fails on any host, an ErrHostActionsFailed error
+// describing each failure is returned alongside them. The outcome of the action on each host is recorded in the
+// audit log of the manager.
+func (m *Manager) Execute(action string, concurrency int, fn HostAction) ([]HostResult, error) {
+	return m.execute(action, concurrency, func(int) HostAction { return fn }, m.audit)
+}
+
+// execute performs an action on every host selected by the manager as Execute does, recording its outcome on each
+// host in audit. The action performed on a host is provided by actionFor given the index of the host, so that actions
+// can keep what they retrieve from each host apart. Actions repeated many times over, e.g. polls of the power status
+// of hosts, pass a nil audit log so that they do not flood the audit log.
+func (m *Manager) execute(action string, concurrency int, actionFor func(i int) HostAction,
+	audit *AuditLog) ([]HostResult, error) {
+	results := make([]HostResult, len(m.Hosts))
+	fanOut(len(m.Hosts), concurrency, func(i int) {
+		host := m.Hosts[i]
+		results[i] = host.run(action, actionFor(i))
+		audit.Record(host.HostName, host.BMCAddress, action, results[i].Err)
+	})
+
+	var failures []HostResult
+	for _, result := range results {
+		if result.Err != nil {
+			failures = append(failures, result)
+		}
+	}
+
+	if len(failures) > 0 {
+		return results, ErrHostActionsFailed{Action: action, Hosts: len(results), Failures: failures}
+	}
+
+	return results, nil
+}
+
+// fanOut calls fn with the index of each of n items, making up to concurrency calls at once, and returns once every
+// call has returned.
+func fanOut(n int, concurrency int, fn func(i int)) {
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+
+	sem := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// run performs an action on a baremetal host and records its outcome.
+func (b baremetalHost) run(action string, fn HostAction) HostResult {
+	log.Debugf("Performing action '%s' on host '%s' with BMC address '%s'.", action, b.HostName, b.BMCAddress)
+
+	start := time.Now()
+	output, err := fn(b.Context, b.Client)
+	if err != nil {
+		log.Debugf("Action '%s' failed on host '%s': %v", action, b.HostName, err)
+	}
+
+	return HostResult{
+		HostName:   b.HostName,
+		BMCAddress: b.BMCAddress,
+		Action:     action,
+		Output:     output,
+		Err:        err,
+		Duration:   time.Since(start),
+		Calls:      b.DryRunCalls(),
+
+		ManagementType:    b.ManagementType,
+		CredentialsSource: b.CredentialsSource,
+	}
+}
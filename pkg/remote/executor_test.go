@@ -0,0 +1,126 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
+)
+
+// newMockHost creates a baremetal host backed by a mocked Redfish client.
+func newMockHost(t *testing.T, name string) (baremetalHost, *redfishutils.MockClient) {
+	t.Helper()
+
+	ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+	require.NoError(t, err)
+
+	host := baremetalHost{
+		Client:     rMock,
+		Context:    ctx,
+		BMCAddress: redfishURL,
+		HostName:   name,
+		username:   username,
+		password:   password,
+	}
+
+	return host, rMock
+}
+
+func powerOffAction(ctx context.Context, client Client) (string, error) {
+	return "", client.SystemPowerOff(ctx)
+}
+
+func TestExecute(t *testing.T) {
+	host1, rMock1 := newMockHost(t, "node-1")
+	host2, rMock2 := newMockHost(t, "node-2")
+	host3, rMock3 := newMockHost(t, "node-3")
+
+	rMock1.On("SystemPowerOff", host1.Context).Times(1).Return(nil)
+	rMock2.On("SystemPowerOff", host2.Context).Times(1).Return(nil)
+	rMock3.On("SystemPowerOff", host3.Context).Times(1).Return(nil)
+
+	m := &Manager{Hosts: []baremetalHost{host1, host2, host3}}
+
+	results, err := m.Execute("power off", 2, powerOffAction)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	for i, name := range []string{"node-1", "node-2", "node-3"} {
+		assert.Equal(t, name, results[i].HostName)
+		assert.Equal(t, redfishURL, results[i].BMCAddress)
+		assert.Equal(t, "power off", results[i].Action)
+		assert.NoError(t, results[i].Err)
+	}
+
+	rMock1.AssertExpectations(t)
+	rMock2.AssertExpectations(t)
+	rMock3.AssertExpectations(t)
+}
+
+func TestExecutePartialFailure(t *testing.T) {
+	host1, rMock1 := newMockHost(t, "node-1")
+	host2, rMock2 := newMockHost(t, "node-2")
+	host3, rMock3 := newMockHost(t, "node-3")
+
+	bmcErr := errors.New("BMC unavailable")
+	rMock1.On("SystemPowerOff", host1.Context).Times(1).Return(bmcErr)
+	rMock2.On("SystemPowerOff", host2.Context).Times(1).Return(nil)
+	rMock3.On("SystemPowerOff", host3.Context).Times(1).Return(nil)
+
+	m := &Manager{Hosts: []baremetalHost{host1, host2, host3}}
+
+	// The failure on the first host must not prevent the action from being performed on the others
+	results, err := m.Execute("power off", 1, powerOffAction)
+	require.Error(t, err)
+	require.Len(t, results, 3)
+
+	aggregateErr, ok := err.(ErrHostActionsFailed)
+	require.True(t, ok)
+	assert.Equal(t, 3, aggregateErr.Hosts)
+	require.Len(t, aggregateErr.Failures, 1)
+	assert.Equal(t, "node-1", aggregateErr.Failures[0].HostName)
+	assert.Equal(t, bmcErr, aggregateErr.Failures[0].Err)
+
+	assert.Equal(t, bmcErr, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	assert.NoError(t, results[2].Err)
+
+	rMock1.AssertExpectations(t)
+	rMock2.AssertExpectations(t)
+	rMock3.AssertExpectations(t)
+}
+
+func TestExecuteOutput(t *testing.T) {
+	host, rMock := newMockHost(t, "node-1")
+	rMock.On("SystemPowerStatus", host.Context).Times(1).Return(power.StatusOn, nil)
+
+	m := &Manager{Hosts: []baremetalHost{host}}
+
+	results, err := m.Execute("power status", 0, func(ctx context.Context, client Client) (string, error) {
+		status, err := client.SystemPowerStatus(ctx)
+		return status.String(), err
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	assert.Equal(t, power.StatusOn.String(), results[0].Output)
+}
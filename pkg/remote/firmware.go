@@ -0,0 +1,88 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
+)
+
+// DefaultFirmwareUpdateTimeout bounds how long a firmware update is waited for when no timeout is specified.
+const DefaultFirmwareUpdateTimeout = 30 * time.Minute
+
+// HostFirmware describes the firmware components of a baremetal host and their versions.
+type HostFirmware struct {
+	HostName   string               `json:"name"`
+	BMCAddress string               `json:"bmcAddress"`
+	Firmware   []inventory.Firmware `json:"firmware,omitempty"`
+	Error      string               `json:"error,omitempty"`
+}
+
+// FirmwareInventory retrieves the firmware components of every host selected by the manager, acting on up to
+// concurrency hosts at once. A firmware inventory is returned for every host; when it cannot be retrieved from some
+// hosts, their inventories record the failure and an ErrHostActionsFailed error is returned alongside them.
+func (m *Manager) FirmwareInventory(concurrency int) ([]HostFirmware, error) {
+	components := make([][]inventory.Firmware, len(m.Hosts))
+
+	getFirmware := func(i int) HostAction {
+		return func(ctx context.Context, client Client) (string, error) {
+			firmware, err := client.FirmwareInventory(ctx)
+			components[i] = firmware
+			return "", err
+		}
+	}
+
+	results, err := m.execute("firmware inventory", concurrency, getFirmware, m.audit)
+
+	hostFirmware := make([]HostFirmware, 0, len(m.Hosts))
+	for i, host := range m.Hosts {
+		firmware := HostFirmware{HostName: host.HostName, BMCAddress: host.BMCAddress}
+
+		if result := results[i]; result.Err != nil {
+			firmware.Error = collapse(result.Err)
+		} else {
+			firmware.Firmware = components[i]
+		}
+
+		hostFirmware = append(hostFirmware, firmware)
+	}
+
+	return hostFirmware, err
+}
+
+// UpdateFirmware updates the firmware of every host selected by the manager from the image located at imageURL,
+// acting on up to concurrency hosts at once, and waits up to timeout for each update to finish. Targets restricts the
+// update to the firmware components with the supplied IDs.
+func (m *Manager) UpdateFirmware(concurrency int, imageURL string, targets []string,
+	timeout time.Duration) ([]HostResult, error) {
+	if timeout <= 0 {
+		timeout = DefaultFirmwareUpdateTimeout
+	}
+
+	updateFirmware := func(ctx context.Context, client Client) (string, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		if err := client.UpdateFirmware(ctx, imageURL, targets); err != nil {
+			return "", err
+		}
+
+		return "update completed", nil
+	}
+
+	return m.Execute("update firmware", concurrency, updateFirmware)
+}
@@ -0,0 +1,75 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
+)
+
+func TestFirmwareInventory(t *testing.T) {
+	host1, rMock1 := newMockHost(t, "node-1")
+	host2, rMock2 := newMockHost(t, "node-2")
+
+	firmware := []inventory.Firmware{{ID: "BIOS", Name: "BIOS", Version: "2.8.1", Updateable: true}}
+
+	rMock1.On("FirmwareInventory", host1.Context).Times(1).Return(firmware, nil)
+	rMock2.On("FirmwareInventory", host2.Context).Times(1).Return(nil, errors.New("BMC\nunavailable"))
+
+	m := &Manager{Hosts: []baremetalHost{host1, host2}}
+
+	hostFirmware, err := m.FirmwareInventory(2)
+	_, ok := err.(ErrHostActionsFailed)
+	assert.True(t, ok)
+
+	expected := []HostFirmware{
+		{HostName: "node-1", BMCAddress: redfishURL, Firmware: firmware},
+		{HostName: "node-2", BMCAddress: redfishURL, Error: "BMC unavailable"},
+	}
+	assert.Equal(t, expected, hostFirmware)
+
+	rMock1.AssertExpectations(t)
+	rMock2.AssertExpectations(t)
+}
+
+func TestUpdateFirmware(t *testing.T) {
+	host, rMock := newMockHost(t, "node-1")
+
+	imageURL := "http://images.example.com/bios.exe"
+	targets := []string{"BIOS"}
+
+	// The update is bounded by the timeout, so it is performed with a context derived from the host's context
+	withDeadline := mock.MatchedBy(func(ctx context.Context) bool {
+		_, ok := ctx.Deadline()
+		return ok
+	})
+	rMock.On("UpdateFirmware", withDeadline, imageURL, targets).Times(1).Return(nil)
+
+	m := &Manager{Hosts: []baremetalHost{host}}
+
+	results, err := m.UpdateFirmware(1, imageURL, targets, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "update completed", results[0].Output)
+
+	rMock.AssertExpectations(t)
+}
@@ -0,0 +1,88 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
+)
+
+// HostInventory describes the hardware of a baremetal host, along with the details of its baremetal host document that
+// can be verified against it.
+type HostInventory struct {
+	HostName       string `json:"name"`
+	BMCAddress     string `json:"bmcAddress"`
+	BootMACAddress string `json:"bootMACAddress,omitempty"`
+	// BootMACAddressMismatch indicates that none of the host's NICs has the boot MAC address of its document.
+	BootMACAddressMismatch bool                 `json:"bootMACAddressMismatch,omitempty"`
+	Inventory              *inventory.Inventory `json:"inventory,omitempty"`
+	Error                  string               `json:"error,omitempty"`
+}
+
+// Inventory retrieves the hardware inventory of every host selected by the manager, acting on up to concurrency hosts
+// at once. An inventory is returned for every host; when it cannot be retrieved from some hosts, their inventories
+// record the failure and an ErrHostActionsFailed error is returned alongside them.
+func (m *Manager) Inventory(concurrency int) ([]HostInventory, error) {
+	inventories := make([]inventory.Inventory, len(m.Hosts))
+
+	getInventory := func(i int) HostAction {
+		return func(ctx context.Context, client Client) (string, error) {
+			inv, err := client.Inventory(ctx)
+			inventories[i] = inv
+			return "", err
+		}
+	}
+
+	results, err := m.execute("inventory", concurrency, getInventory, m.audit)
+
+	hostInventories := make([]HostInventory, 0, len(m.Hosts))
+	for i, host := range m.Hosts {
+		hostInventory := HostInventory{
+			HostName:       host.HostName,
+			BMCAddress:     host.BMCAddress,
+			BootMACAddress: host.BootMACAddress,
+		}
+
+		if result := results[i]; result.Err != nil {
+			hostInventory.Error = collapse(result.Err)
+		} else {
+			inv := inventories[i]
+			hostInventory.Inventory = &inv
+			hostInventory.BootMACAddressMismatch = host.BootMACAddress != "" && !inv.HasMACAddress(host.BootMACAddress)
+		}
+
+		hostInventories = append(hostInventories, hostInventory)
+	}
+
+	return hostInventories, err
+}
+
+// CheckBootMACAddresses returns an ErrBootMACAddressMismatch error describing the hosts whose boot MAC address does not
+// match any of their NICs, if any.
+func CheckBootMACAddresses(inventories []HostInventory) error {
+	var mismatches []HostInventory
+	for _, hostInventory := range inventories {
+		if hostInventory.BootMACAddressMismatch {
+			mismatches = append(mismatches, hostInventory)
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return ErrBootMACAddressMismatch{Mismatches: mismatches}
+	}
+
+	return nil
+}
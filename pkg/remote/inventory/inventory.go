@@ -0,0 +1,107 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package inventory describes the hardware of baremetal hosts independently of the management client used to
+// retrieve it.
+package inventory
+
+import (
+	"strings"
+)
+
+// Inventory describes the hardware installed in a baremetal host.
+type Inventory struct {
+	Manufacturer string      `json:"manufacturer,omitempty"`
+	Model        string      `json:"model,omitempty"`
+	SerialNumber string      `json:"serialNumber,omitempty"`
+	Processors   []Processor `json:"processors,omitempty"`
+	Memory       Memory      `json:"memory"`
+	NICs         []NIC       `json:"nics,omitempty"`
+	Storage      []Storage   `json:"storage,omitempty"`
+}
+
+// Processor describes a CPU installed in a baremetal host.
+type Processor struct {
+	ID          string `json:"id"`
+	Model       string `json:"model,omitempty"`
+	Cores       int    `json:"cores,omitempty"`
+	Threads     int    `json:"threads,omitempty"`
+	MaxSpeedMHz int    `json:"maxSpeedMHz,omitempty"`
+}
+
+// Memory describes the memory installed in a baremetal host.
+type Memory struct {
+	TotalGiB float64        `json:"totalGiB"`
+	Modules  []MemoryModule `json:"modules,omitempty"`
+}
+
+// MemoryModule describes a memory module, e.g. a DIMM, installed in a baremetal host.
+type MemoryModule struct {
+	ID          string `json:"id"`
+	CapacityMiB int    `json:"capacityMiB"`
+	Type        string `json:"type,omitempty"`
+	SpeedMHz    int    `json:"speedMHz,omitempty"`
+}
+
+// NIC describes a network interface of a baremetal host.
+type NIC struct {
+	ID         string `json:"id"`
+	Name       string `json:"name,omitempty"`
+	MACAddress string `json:"macAddress"`
+	LinkStatus string `json:"linkStatus,omitempty"`
+	SpeedMbps  int    `json:"speedMbps,omitempty"`
+}
+
+// Storage describes a storage subsystem of a baremetal host, i.e. a set of storage controllers and the drives
+// attached to them.
+type Storage struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name,omitempty"`
+	Controllers []StorageController `json:"controllers,omitempty"`
+	Drives      []Drive             `json:"drives,omitempty"`
+}
+
+// StorageController describes a storage controller, e.g. a RAID controller.
+type StorageController struct {
+	Name         string `json:"name,omitempty"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Model        string `json:"model,omitempty"`
+}
+
+// Drive describes a disk drive attached to a storage controller.
+type Drive struct {
+	ID            string `json:"id"`
+	Name          string `json:"name,omitempty"`
+	Model         string `json:"model,omitempty"`
+	SerialNumber  string `json:"serialNumber,omitempty"`
+	CapacityBytes int64  `json:"capacityBytes"`
+	MediaType     string `json:"mediaType,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+// HasMACAddress reports whether a network interface of the host has the supplied MAC address. MAC addresses are
+// compared case-insensitively and regardless of the separator used between octets.
+func (i Inventory) HasMACAddress(macAddress string) bool {
+	for _, nic := range i.NICs {
+		if normalizeMACAddress(nic.MACAddress) == normalizeMACAddress(macAddress) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeMACAddress(macAddress string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", "-", "", ".", "").Replace(macAddress))
+}
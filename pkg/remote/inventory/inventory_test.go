@@ -0,0 +1,39 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package inventory_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
+)
+
+func TestHasMACAddress(t *testing.T) {
+	inv := inventory.Inventory{
+		NICs: []inventory.NIC{
+			{ID: "NIC.Integrated.1-1-1", MACAddress: "00:3B:8B:0C:EC:8B"},
+			{ID: "NIC.Integrated.1-2-1", MACAddress: "00-3b-8b-0c-ec-8c"},
+			{ID: "NIC.Slot.1-1-1", MACAddress: "003b.8b0c.ec8e"},
+		},
+	}
+
+	assert.True(t, inv.HasMACAddress("00:3b:8b:0c:ec:8b"))
+	assert.True(t, inv.HasMACAddress("00:3b:8b:0c:ec:8c"))
+	assert.True(t, inv.HasMACAddress("00:3b:8b:0c:ec:8e"))
+	assert.False(t, inv.HasMACAddress("00:3b:8b:0c:ec:8d"))
+	assert.False(t, inventory.Inventory{}.HasMACAddress("00:3b:8b:0c:ec:8b"))
+}
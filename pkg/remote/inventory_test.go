@@ -0,0 +1,71 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
+)
+
+func TestInventory(t *testing.T) {
+	host1, rMock1 := newMockHost(t, "node-1")
+	host1.BootMACAddress = "00:3b:8b:0c:ec:8b"
+	host2, rMock2 := newMockHost(t, "node-2")
+	host2.BootMACAddress = "00:3b:8b:0c:ec:8c"
+	host3, rMock3 := newMockHost(t, "node-3")
+
+	inv1 := inventory.Inventory{NICs: []inventory.NIC{{ID: "NIC.1", MACAddress: "00:3B:8B:0C:EC:8B"}}}
+	inv2 := inventory.Inventory{NICs: []inventory.NIC{{ID: "NIC.1", MACAddress: "00:3B:8B:0C:EC:8D"}}}
+
+	rMock1.On("Inventory", host1.Context).Times(1).Return(inv1, nil)
+	rMock2.On("Inventory", host2.Context).Times(1).Return(inv2, nil)
+	rMock3.On("Inventory", host3.Context).Times(1).Return(nil, errors.New("BMC\nunavailable"))
+
+	m := &Manager{Hosts: []baremetalHost{host1, host2, host3}}
+
+	inventories, err := m.Inventory(2)
+	_, ok := err.(ErrHostActionsFailed)
+	assert.True(t, ok)
+
+	expected := []HostInventory{
+		{HostName: "node-1", BMCAddress: redfishURL, BootMACAddress: "00:3b:8b:0c:ec:8b", Inventory: &inv1},
+		{
+			HostName:               "node-2",
+			BMCAddress:             redfishURL,
+			BootMACAddress:         "00:3b:8b:0c:ec:8c",
+			BootMACAddressMismatch: true,
+			Inventory:              &inv2,
+		},
+		{HostName: "node-3", BMCAddress: redfishURL, Error: "BMC unavailable"},
+	}
+	assert.Equal(t, expected, inventories)
+
+	err = CheckBootMACAddresses(inventories)
+	require.Error(t, err)
+	assert.Equal(t, ErrBootMACAddressMismatch{Mismatches: expected[1:2]}, err)
+
+	rMock1.AssertExpectations(t)
+	rMock2.AssertExpectations(t)
+	rMock3.AssertExpectations(t)
+}
+
+func TestCheckBootMACAddressesNoMismatch(t *testing.T) {
+	assert.NoError(t, CheckBootMACAddresses([]HostInventory{{HostName: "node-1"}}))
+}
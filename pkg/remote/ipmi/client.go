@@ -0,0 +1,416 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipmi provides an out-of-band management client for BMCs that implement IPMI v2.0 over LAN (lanplus).
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/bios"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
+)
+
+const (
+	// ClientType is used by other packages as the identifier of the IPMI client.
+	ClientType string = "ipmi"
+
+	defaultPort = "623"
+	urlScheme   = "ipmi"
+)
+
+// Chassis control actions.
+const (
+	chassisPowerDown    byte = 0x00
+	chassisPowerUp      byte = 0x01
+	chassisSoftShutdown byte = 0x05
+)
+
+// bootDevice is a device a host can be instructed to boot from using the Set System Boot Options command.
+type bootDevice byte
+
+// Boot devices supported by the boot flags system boot option.
+const (
+	bootDeviceNone bootDevice = 0x00
+	bootDevicePXE  bootDevice = 0x04
+	bootDeviceDisk bootDevice = 0x08
+	bootDeviceCD   bootDevice = 0x14
+	bootDeviceBIOS bootDevice = 0x18
+)
+
+// bootDevices maps vendor-neutral boot devices to the boot devices of the boot flags system boot option.
+var bootDevices = map[boot.Device]bootDevice{
+	boot.DeviceNone: bootDeviceNone,
+	boot.DevicePXE:  bootDevicePXE,
+	boot.DeviceDisk: bootDeviceDisk,
+	boot.DeviceCD:   bootDeviceCD,
+	boot.DeviceBIOS: bootDeviceBIOS,
+}
+
+// Boot flags system boot option (parameter 5) fields.
+const (
+	bootOptionBootFlags  byte = 0x05
+	bootFlagsValid       byte = 0x80
+	bootFlagsPersistent  byte = 0x40
+	bootFlagsDeviceMask  byte = 0x3c
+	chassisStatusPowerOn byte = 0x01
+)
+
+// Client holds details about a BMC that is managed over IPMI.
+type Client struct {
+	nodeID      string
+	username    string
+	password    string
+	retryPolicy retry.Policy
+	timeout     time.Duration
+	attempts    int
+
+	// Sleep is meant to be mocked out for tests
+	Sleep func(d time.Duration)
+}
+
+// NodeID retrieves the address of the BMC, in host:port form.
+func (c *Client) NodeID() string {
+	return c.nodeID
+}
+
+// RetryPolicy returns the policy used to poll the host until power operations complete.
+func (c *Client) RetryPolicy() retry.Policy {
+	return c.retryPolicy
+}
+
+// EjectVirtualMedia is not supported by IPMI, which does not define virtual media operations.
+func (c *Client) EjectVirtualMedia(ctx context.Context) error {
+	return ErrOperationNotSupported{What: "ejecting virtual media"}
+}
+
+// SetVirtualMedia is not supported by IPMI, which does not define virtual media operations.
+func (c *Client) SetVirtualMedia(ctx context.Context, isoPath string) error {
+	return ErrOperationNotSupported{What: "inserting virtual media"}
+}
+
+// InsertVirtualMedia is not supported by IPMI, which does not define virtual media operations.
+func (c *Client) InsertVirtualMedia(ctx context.Context, isoPath string) (string, error) {
+	return "", ErrOperationNotSupported{What: "inserting virtual media"}
+}
+
+// SupportsVirtualMedia reports that hosts managed over IPMI have no virtual media devices.
+func (c *Client) SupportsVirtualMedia(ctx context.Context) (bool, error) {
+	return false, nil
+}
+
+// Inventory is not supported by the IPMI client, which does not retrieve hardware details from the BMC.
+func (c *Client) Inventory(ctx context.Context) (inventory.Inventory, error) {
+	return inventory.Inventory{}, ErrOperationNotSupported{What: "retrieving hardware inventory"}
+}
+
+// FirmwareInventory is not supported by the IPMI client, which does not retrieve firmware details from the BMC.
+func (c *Client) FirmwareInventory(ctx context.Context) ([]inventory.Firmware, error) {
+	return nil, ErrOperationNotSupported{What: "retrieving firmware inventory"}
+}
+
+// UpdateFirmware is not supported by IPMI, which does not define firmware update operations.
+func (c *Client) UpdateFirmware(ctx context.Context, imageURL string, targets []string) error {
+	return ErrOperationNotSupported{What: "updating firmware"}
+}
+
+// BIOSSettings is not supported by IPMI, which does not define BIOS attribute operations.
+func (c *Client) BIOSSettings(ctx context.Context) (bios.Settings, error) {
+	return bios.Settings{}, ErrOperationNotSupported{What: "retrieving BIOS settings"}
+}
+
+// SetBIOSAttributes is not supported by IPMI, which does not define BIOS attribute operations.
+func (c *Client) SetBIOSAttributes(ctx context.Context, attributes bios.Attributes) error {
+	return ErrOperationNotSupported{What: "changing BIOS settings"}
+}
+
+// RebootSystem power cycles a host by sending a power down command followed by a power up command.
+func (c *Client) RebootSystem(ctx context.Context) error {
+	return c.withSession(ctx, func(s *session) error {
+		log.Debugf("Rebooting node '%s': powering off.", c.nodeID)
+		if err := c.setPowerState(ctx, s, chassisPowerDown, power.StatusOff); err != nil {
+			log.Debugf("Failed to reboot node '%s': shutdown failure.", c.nodeID)
+			return err
+		}
+
+		log.Debugf("Rebooting node '%s': powering on.", c.nodeID)
+		if err := c.setPowerState(ctx, s, chassisPowerUp, power.StatusOn); err != nil {
+			log.Debugf("Failed to reboot node '%s': startup failure.", c.nodeID)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// RebootSystemGraceful power cycles a host by requesting a soft shutdown of its operating system through ACPI,
+// sending a power down command when it is not powered off within timeout, followed by a power up command.
+func (c *Client) RebootSystemGraceful(ctx context.Context, timeout time.Duration) error {
+	return c.withSession(ctx, func(s *session) error {
+		log.Debugf("Rebooting node '%s': shutting down.", c.nodeID)
+		if err := c.shutdown(ctx, s, timeout); err != nil {
+			log.Debugf("Failed to reboot node '%s': shutdown failure.", c.nodeID)
+			return err
+		}
+
+		log.Debugf("Rebooting node '%s': powering on.", c.nodeID)
+		if err := c.setPowerState(ctx, s, chassisPowerUp, power.StatusOn); err != nil {
+			log.Debugf("Failed to reboot node '%s': startup failure.", c.nodeID)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// SetBootSourceByType instructs a host to boot from its CD/DVD device on its next boot.
+func (c *Client) SetBootSourceByType(ctx context.Context) error {
+	return c.SetBootOverride(ctx, boot.Override{Device: boot.DeviceCD})
+}
+
+// SetBootOverride instructs a host to boot from the supplied device, either on its next boot only or persistently.
+// Overriding the boot device with boot.DeviceNone clears any existing override.
+func (c *Client) SetBootOverride(ctx context.Context, override boot.Override) error {
+	device, ok := bootDevices[override.Device]
+	if !ok {
+		return ErrOperationNotSupported{What: fmt.Sprintf("booting from device '%s'", override.Device)}
+	}
+
+	log.Debugf("Setting boot device of node '%s' to 0x%02x.", c.nodeID, byte(device))
+
+	var flags byte
+	if override.Device != boot.DeviceNone {
+		flags = bootFlagsValid
+		if override.Persistent {
+			flags |= bootFlagsPersistent
+		}
+	}
+
+	return c.withSession(ctx, func(s *session) error {
+		data := []byte{bootOptionBootFlags, flags, byte(device), 0x00, 0x00, 0x00}
+		if _, err := s.command(ctx, netFnChassis, cmdSetSystemBootOptions, data); err != nil {
+			return err
+		}
+
+		log.Debug("Successfully set boot device.")
+		return nil
+	})
+}
+
+// BootOverride retrieves the device a host is instructed to boot from and whether the instruction is persistent.
+func (c *Client) BootOverride(ctx context.Context) (boot.Override, error) {
+	override := boot.Override{Device: boot.DeviceUnknown}
+
+	err := c.withSession(ctx, func(s *session) error {
+		data, err := s.command(ctx, netFnChassis, cmdGetSystemBootOptions, []byte{bootOptionBootFlags, 0x00, 0x00})
+		if err != nil {
+			return err
+		}
+
+		// The response contains the parameter version and parameter number followed by the boot flags.
+		if len(data) < 4 {
+			return ErrMalformedPacket{Reason: "boot flags response is too short"}
+		}
+
+		if data[2]&bootFlagsValid == 0 {
+			override.Device = boot.DeviceNone
+			return nil
+		}
+
+		override.Persistent = data[2]&bootFlagsPersistent != 0
+		for vendorNeutralDevice, device := range bootDevices {
+			if bootDevice(data[3]&bootFlagsDeviceMask) == device {
+				override.Device = vendorNeutralDevice
+			}
+		}
+
+		return nil
+	})
+
+	return override, err
+}
+
+// SystemPowerOff shuts down a host.
+func (c *Client) SystemPowerOff(ctx context.Context) error {
+	return c.withSession(ctx, func(s *session) error {
+		return c.setPowerState(ctx, s, chassisPowerDown, power.StatusOff)
+	})
+}
+
+// SystemPowerOffGraceful shuts down a host by requesting a soft shutdown of its operating system through ACPI. When
+// the host is not powered off within timeout, a power down command is sent.
+func (c *Client) SystemPowerOffGraceful(ctx context.Context, timeout time.Duration) error {
+	return c.withSession(ctx, func(s *session) error {
+		return c.shutdown(ctx, s, timeout)
+	})
+}
+
+// SystemPowerOn powers on a host.
+func (c *Client) SystemPowerOn(ctx context.Context) error {
+	return c.withSession(ctx, func(s *session) error {
+		return c.setPowerState(ctx, s, chassisPowerUp, power.StatusOn)
+	})
+}
+
+// SystemPowerStatus retrieves the power status of a host.
+func (c *Client) SystemPowerStatus(ctx context.Context) (power.Status, error) {
+	status := power.StatusUnknown
+	err := c.withSession(ctx, func(s *session) error {
+		var err error
+		status, err = powerStatus(ctx, s)
+		return err
+	})
+
+	return status, err
+}
+
+// withSession establishes a session with the BMC, invokes fn and closes the session.
+func (c *Client) withSession(ctx context.Context, fn func(s *session) error) error {
+	s, err := openSession(ctx, c.nodeID, c.username, c.password, c.timeout, c.attempts)
+	if err != nil {
+		return err
+	}
+	defer s.close(ctx)
+
+	return fn(s)
+}
+
+// shutdown requests a soft shutdown of a host that is powered on, falling back to a power down command when the host
+// is not powered off within timeout.
+func (c *Client) shutdown(ctx context.Context, s *session, timeout time.Duration) error {
+	status, err := powerStatus(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	if status == power.StatusOff {
+		log.Debugf("Node '%s' is already powered off.", c.nodeID)
+		return nil
+	}
+
+	policy := c.retryPolicy
+	policy.Timeout = timeout
+
+	err = c.setPowerStateWithPolicy(ctx, s, chassisSoftShutdown, power.StatusOff, policy)
+	if _, timedOut := err.(retry.ErrTimeout); !timedOut {
+		return err
+	}
+
+	log.Printf("Node '%s' did not shut down gracefully within %s. Forcing it off.", c.nodeID, timeout)
+
+	return c.setPowerState(ctx, s, chassisPowerDown, power.StatusOff)
+}
+
+// setPowerState sends a chassis control command and waits for the host to reach the desired power state.
+func (c *Client) setPowerState(ctx context.Context, s *session, action byte, desiredState power.Status) error {
+	return c.setPowerStateWithPolicy(ctx, s, action, desiredState, c.retryPolicy)
+}
+
+// setPowerStateWithPolicy sends a chassis control command and waits for the host to reach the desired power state
+// until the timeout of policy expires.
+func (c *Client) setPowerStateWithPolicy(ctx context.Context, s *session, action byte, desiredState power.Status,
+	policy retry.Policy) error {
+	if _, err := s.command(ctx, netFnChassis, cmdChassisControl, []byte{action}); err != nil {
+		return err
+	}
+
+	log.Debugf("Waiting for node '%s' to reach power state '%s'.", c.nodeID, desiredState)
+
+	return policy.Poll(ctx, fmt.Sprintf("reach desired power state %s", desiredState), c.Sleep,
+		func(ctx context.Context) (bool, error) {
+			status, err := powerStatus(ctx, s)
+			if err != nil {
+				return false, err
+			}
+
+			if status == desiredState {
+				log.Debugf("Node '%s' reached power state '%s'.", c.nodeID, desiredState)
+				return true, nil
+			}
+
+			return false, nil
+		})
+}
+
+// powerStatus retrieves the power status of a host using the Get Chassis Status command.
+func powerStatus(ctx context.Context, s *session) (power.Status, error) {
+	data, err := s.command(ctx, netFnChassis, cmdGetChassisStatus, nil)
+	if err != nil {
+		return power.StatusUnknown, err
+	}
+
+	if len(data) < 1 {
+		return power.StatusUnknown, ErrMalformedPacket{Reason: "chassis status response is too short"}
+	}
+
+	if data[0]&chassisStatusPowerOn != 0 {
+		return power.StatusOn, nil
+	}
+
+	return power.StatusOff, nil
+}
+
+// NewClient returns a client with the capability to make IPMI v2.0 requests. The BMC address may be a bare host, a
+// host:port pair or an ipmi:// URL; the standard RMCP port is used when no port is supplied. The retry policy
+// determines how long and how often the host is polled until power operations complete.
+func NewClient(bmcAddress string,
+	username string,
+	password string,
+	retryPolicy retry.Policy) (context.Context, *Client, error) {
+	ctx := context.Background()
+
+	if bmcAddress == "" {
+		return ctx, nil, ErrIPMIMissingConfig{What: "BMC address"}
+	}
+
+	if !strings.Contains(bmcAddress, "://") {
+		bmcAddress = urlScheme + "://" + bmcAddress
+	}
+
+	parsedURL, err := url.Parse(bmcAddress)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	if parsedURL.Hostname() == "" {
+		return ctx, nil, ErrIPMIMissingConfig{What: "BMC host"}
+	}
+
+	port := parsedURL.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	c := &Client{
+		nodeID:      net.JoinHostPort(parsedURL.Hostname(), port),
+		username:    username,
+		password:    password,
+		retryPolicy: retryPolicy,
+		timeout:     defaultResponseTimeout,
+		attempts:    defaultRetransmitAttempts,
+
+		Sleep: func(d time.Duration) {
+			time.Sleep(d)
+		},
+	}
+
+	return ctx, c, nil
+}
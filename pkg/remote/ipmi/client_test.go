@@ -0,0 +1,315 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipmi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/bios"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
+)
+
+const (
+	username = "admin"
+	password = "password"
+)
+
+// newTestClient returns a client for a fake BMC that does not sleep between power state checks.
+func newTestClient(t *testing.T, bmc *fakeBMC, password string) (context.Context, *Client) {
+	t.Helper()
+
+	ctx, client, err := NewClient(bmc.Address(), username, password, retry.Policy{Timeout: 5 * time.Second})
+	require.NoError(t, err)
+
+	client.timeout = 200 * time.Millisecond
+	client.Sleep = func(_ time.Duration) {}
+
+	return ctx, client
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		address string
+		nodeID  string
+	}{
+		{address: "192.168.1.10", nodeID: "192.168.1.10:623"},
+		{address: "192.168.1.10:6230", nodeID: "192.168.1.10:6230"},
+		{address: "ipmi://bmc.example.com", nodeID: "bmc.example.com:623"},
+		{address: "ipmi://[fd00::10]:6230", nodeID: "[fd00::10]:6230"},
+	}
+
+	for _, tt := range tests {
+		_, client, err := NewClient(tt.address, username, password, retry.Policy{})
+		require.NoError(t, err)
+		assert.Equal(t, tt.nodeID, client.NodeID())
+	}
+}
+
+func TestNewClientMissingAddress(t *testing.T) {
+	_, _, err := NewClient("", username, password, retry.Policy{})
+	assert.Equal(t, ErrIPMIMissingConfig{What: "BMC address"}, err)
+}
+
+func TestEncodeDecodePacket(t *testing.T) {
+	keys := newSessionKeys([]byte("session integrity key"))
+	msg := encodeRequest(netFnChassis, cmdGetChassisStatus, 5, nil)
+
+	data, err := encodePacket(packet{payloadType: payloadIPMI, sessionID: 7, sequence: 3, payload: msg}, keys)
+	require.NoError(t, err)
+
+	p, err := decodePacket(data, keys)
+	require.NoError(t, err)
+	assert.Equal(t, payloadIPMI, p.payloadType)
+	assert.Equal(t, uint32(7), p.sessionID)
+	assert.Equal(t, uint32(3), p.sequence)
+	assert.Equal(t, msg, p.payload)
+
+	// Tampering with the packet must be detected by the integrity check
+	data[len(data)-1] ^= 0xff
+	_, err = decodePacket(data, keys)
+	assert.Error(t, err)
+}
+
+func TestSystemPowerStatus(t *testing.T) {
+	bmc := newFakeBMC(t, username, password)
+	defer bmc.Close()
+	ctx, client := newTestClient(t, bmc, password)
+
+	status, err := client.SystemPowerStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, power.StatusOff, status)
+
+	bmc.SetPowerOn(true)
+	status, err = client.SystemPowerStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, power.StatusOn, status)
+
+	// Every operation establishes its own session and closes it when complete
+	assert.Equal(t, []byte{
+		cmdSetSessionPrivilege, cmdGetChassisStatus, cmdCloseSession,
+		cmdSetSessionPrivilege, cmdGetChassisStatus, cmdCloseSession,
+	}, bmc.Commands())
+}
+
+func TestSystemPowerOnOff(t *testing.T) {
+	bmc := newFakeBMC(t, username, password)
+	defer bmc.Close()
+	ctx, client := newTestClient(t, bmc, password)
+
+	require.NoError(t, client.SystemPowerOn(ctx))
+	assert.True(t, bmc.PowerOn())
+
+	require.NoError(t, client.SystemPowerOff(ctx))
+	assert.False(t, bmc.PowerOn())
+}
+
+func TestRebootSystem(t *testing.T) {
+	bmc := newFakeBMC(t, username, password)
+	defer bmc.Close()
+	bmc.SetPowerOn(true)
+	ctx, client := newTestClient(t, bmc, password)
+
+	require.NoError(t, client.RebootSystem(ctx))
+	assert.True(t, bmc.PowerOn())
+	assert.Equal(t, []byte{
+		cmdSetSessionPrivilege,
+		cmdChassisControl, cmdGetChassisStatus,
+		cmdChassisControl, cmdGetChassisStatus,
+		cmdCloseSession,
+	}, bmc.Commands())
+}
+
+func TestSystemPowerOffGraceful(t *testing.T) {
+	bmc := newFakeBMC(t, username, password)
+	defer bmc.Close()
+	bmc.SetPowerOn(true)
+	ctx, client := newTestClient(t, bmc, password)
+
+	require.NoError(t, client.SystemPowerOffGraceful(ctx, time.Second))
+	assert.False(t, bmc.PowerOn())
+	assert.Equal(t, []byte{
+		cmdSetSessionPrivilege,
+		cmdGetChassisStatus,
+		cmdChassisControl, cmdGetChassisStatus,
+		cmdCloseSession,
+	}, bmc.Commands())
+}
+
+func TestSystemPowerOffGracefulAlreadyOff(t *testing.T) {
+	bmc := newFakeBMC(t, username, password)
+	defer bmc.Close()
+	ctx, client := newTestClient(t, bmc, password)
+
+	require.NoError(t, client.SystemPowerOffGraceful(ctx, time.Second))
+	assert.Equal(t, []byte{cmdSetSessionPrivilege, cmdGetChassisStatus, cmdCloseSession}, bmc.Commands())
+}
+
+func TestRebootSystemGracefulForced(t *testing.T) {
+	bmc := newFakeBMC(t, username, password)
+	defer bmc.Close()
+	bmc.SetPowerOn(true)
+	bmc.SetHung(true)
+	ctx, client := newTestClient(t, bmc, password)
+
+	require.NoError(t, client.RebootSystemGraceful(ctx, 10*time.Millisecond))
+	assert.True(t, bmc.PowerOn())
+
+	// The soft shutdown is ignored, so the host is powered down once the graceful shutdown timeout expires
+	commands := bmc.Commands()
+	var controls int
+	for _, cmd := range commands {
+		if cmd == cmdChassisControl {
+			controls++
+		}
+	}
+	assert.Equal(t, 3, controls)
+	assert.Equal(t, []byte{cmdChassisControl, cmdGetChassisStatus, cmdCloseSession}, commands[len(commands)-3:])
+}
+
+func TestSetBootOverride(t *testing.T) {
+	bmc := newFakeBMC(t, username, password)
+	defer bmc.Close()
+	ctx, client := newTestClient(t, bmc, password)
+
+	override, err := client.BootOverride(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, boot.Override{Device: boot.DeviceNone}, override)
+
+	require.NoError(t, client.SetBootOverride(ctx, boot.Override{Device: boot.DevicePXE, Persistent: true}))
+	override, err = client.BootOverride(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, boot.Override{Device: boot.DevicePXE, Persistent: true}, override)
+
+	require.NoError(t, client.SetBootSourceByType(ctx))
+	override, err = client.BootOverride(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, boot.Override{Device: boot.DeviceCD}, override)
+
+	require.NoError(t, client.SetBootOverride(ctx, boot.Override{Device: boot.DeviceNone}))
+	override, err = client.BootOverride(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, boot.Override{Device: boot.DeviceNone}, override)
+}
+
+func TestSetBootOverrideUnknownDevice(t *testing.T) {
+	_, client, err := NewClient("192.168.1.10", username, password, retry.Policy{})
+	require.NoError(t, err)
+
+	err = client.SetBootOverride(context.Background(), boot.Override{Device: boot.DeviceUnknown})
+	assert.Equal(t, ErrOperationNotSupported{What: "booting from device 'unknown'"}, err)
+}
+
+func TestInvalidCredentials(t *testing.T) {
+	bmc := newFakeBMC(t, username, password)
+	defer bmc.Close()
+	ctx, client := newTestClient(t, bmc, "wrong-password")
+
+	_, err := client.SystemPowerStatus(ctx)
+	_, ok := err.(ErrSessionFailed)
+	assert.True(t, ok)
+	assert.Empty(t, bmc.Commands())
+}
+
+func TestUnknownUser(t *testing.T) {
+	bmc := newFakeBMC(t, "other-user", password)
+	defer bmc.Close()
+	ctx, client := newTestClient(t, bmc, password)
+
+	_, err := client.SystemPowerStatus(ctx)
+	assert.Equal(t, ErrSessionFailed{Step: "RAKP message 2", Status: 0x0d}, err)
+}
+
+func TestSessionRejected(t *testing.T) {
+	bmc := newFakeBMC(t, username, password)
+	defer bmc.Close()
+	bmc.RejectSessions(0x01) // insufficient resources to create a session
+	ctx, client := newTestClient(t, bmc, password)
+
+	_, err := client.SystemPowerStatus(ctx)
+	assert.Equal(t, ErrSessionFailed{Step: "open session", Status: 0x01}, err)
+	assert.Empty(t, bmc.Commands())
+}
+
+func TestRetransmit(t *testing.T) {
+	bmc := newFakeBMC(t, username, password)
+	defer bmc.Close()
+	bmc.DropPackets(2)
+	ctx, client := newTestClient(t, bmc, password)
+
+	status, err := client.SystemPowerStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, power.StatusOff, status)
+}
+
+func TestNoResponse(t *testing.T) {
+	bmc := newFakeBMC(t, username, password)
+	defer bmc.Close()
+	bmc.DropPackets(defaultRetransmitAttempts)
+	ctx, client := newTestClient(t, bmc, password)
+
+	_, err := client.SystemPowerStatus(ctx)
+	assert.Equal(t, ErrNoResponse{Address: bmc.Address(), Retries: defaultRetransmitAttempts}, err)
+}
+
+func TestVirtualMediaNotSupported(t *testing.T) {
+	_, client, err := NewClient("192.168.1.10", username, password, retry.Policy{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	assert.Equal(t, ErrOperationNotSupported{What: "inserting virtual media"}, client.SetVirtualMedia(ctx, "/iso"))
+	assert.Equal(t, ErrOperationNotSupported{What: "ejecting virtual media"}, client.EjectVirtualMedia(ctx))
+
+	_, err = client.InsertVirtualMedia(ctx, "/iso")
+	assert.Equal(t, ErrOperationNotSupported{What: "inserting virtual media"}, err)
+
+	supported, err := client.SupportsVirtualMedia(ctx)
+	assert.NoError(t, err)
+	assert.False(t, supported)
+}
+
+func TestInventoryNotSupported(t *testing.T) {
+	_, client, err := NewClient("192.168.1.10", username, password, retry.Policy{})
+	require.NoError(t, err)
+
+	_, err = client.Inventory(context.Background())
+	assert.Equal(t, ErrOperationNotSupported{What: "retrieving hardware inventory"}, err)
+}
+
+func TestFirmwareNotSupported(t *testing.T) {
+	_, client, err := NewClient("192.168.1.10", username, password, retry.Policy{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = client.FirmwareInventory(ctx)
+	assert.Equal(t, ErrOperationNotSupported{What: "retrieving firmware inventory"}, err)
+	assert.Equal(t, ErrOperationNotSupported{What: "updating firmware"},
+		client.UpdateFirmware(ctx, "http://images.example.com/bios.exe", nil))
+}
+
+func TestBIOSNotSupported(t *testing.T) {
+	_, client, err := NewClient("192.168.1.10", username, password, retry.Policy{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = client.BIOSSettings(ctx)
+	assert.Equal(t, ErrOperationNotSupported{What: "retrieving BIOS settings"}, err)
+	assert.Equal(t, ErrOperationNotSupported{What: "changing BIOS settings"},
+		client.SetBIOSAttributes(ctx, bios.Attributes{"ProcVirtualization": "Enabled"}))
+}
@@ -0,0 +1,86 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ipmi
+
+import (
+	"fmt"
+
+	aerror "opendev.org/airship/airshipctl/pkg/errors"
+)
+
+// ErrIPMIMissingConfig describes an error encountered due to a missing configuration option.
+type ErrIPMIMissingConfig struct {
+	aerror.AirshipError
+	What string
+}
+
+func (e ErrIPMIMissingConfig) Error() string {
+	return "missing configuration: " + e.What
+}
+
+// ErrMalformedPacket describes a packet received from a BMC that could not be decoded.
+type ErrMalformedPacket struct {
+	aerror.AirshipError
+	Reason string
+}
+
+func (e ErrMalformedPacket) Error() string {
+	return fmt.Sprintf("received malformed IPMI packet: %s", e.Reason)
+}
+
+// ErrSessionFailed describes a BMC refusing to establish an RMCP+ session.
+type ErrSessionFailed struct {
+	aerror.AirshipError
+	Step   string
+	Status byte
+}
+
+func (e ErrSessionFailed) Error() string {
+	return fmt.Sprintf("unable to establish IPMI session: %s failed with status code 0x%02x", e.Step, e.Status)
+}
+
+// ErrCompletionCode describes an IPMI command that was rejected by a BMC.
+type ErrCompletionCode struct {
+	aerror.AirshipError
+	NetFn   byte
+	Command byte
+	Code    byte
+}
+
+func (e ErrCompletionCode) Error() string {
+	return fmt.Sprintf("IPMI command 0x%02x (netFn 0x%02x) failed with completion code 0x%02x",
+		e.Command, e.NetFn, e.Code)
+}
+
+// ErrNoResponse describes a BMC that did not respond to a request.
+type ErrNoResponse struct {
+	aerror.AirshipError
+	Address string
+	Retries int
+}
+
+func (e ErrNoResponse) Error() string {
+	return fmt.Sprintf("no response from BMC '%s' after %d attempt(s)", e.Address, e.Retries)
+}
+
+// ErrOperationNotSupported describes an operation that cannot be performed over IPMI.
+type ErrOperationNotSupported struct {
+	aerror.AirshipError
+	What string
+}
+
+func (e ErrOperationNotSupported) Error() string {
+	return fmt.Sprintf("%s is not supported by the IPMI client", e.What)
+}
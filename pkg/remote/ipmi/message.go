@@ -0,0 +1,284 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipmi
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // IPMI v2.0 cipher suite 3 mandates HMAC-SHA1
+	"encoding/binary"
+)
+
+// RMCP and RMCP+ framing constants defined by the IPMI v2.0 specification.
+const (
+	rmcpVersion      byte = 0x06
+	rmcpSeqNoAck     byte = 0xff
+	rmcpClassIPMI    byte = 0x07
+	authTypeRMCPPlus byte = 0x06
+
+	rmcpHeaderLength    = 4
+	sessionHeaderLength = 12
+	authCodeLength      = 12
+)
+
+// RMCP+ payload types. The two high bits of the payload type mark a payload as encrypted and authenticated.
+const (
+	payloadIPMI                byte = 0x00
+	payloadOpenSessionRequest  byte = 0x10
+	payloadOpenSessionResponse byte = 0x11
+	payloadRAKP1               byte = 0x12
+	payloadRAKP2               byte = 0x13
+	payloadRAKP3               byte = 0x14
+	payloadRAKP4               byte = 0x15
+
+	payloadEncrypted     byte = 0x80
+	payloadAuthenticated byte = 0x40
+	payloadTypeMask      byte = 0x3f
+)
+
+// Addresses used in IPMI LAN messages.
+const (
+	bmcSlaveAddress byte = 0x20
+	remoteSWID      byte = 0x81
+)
+
+// packet is an RMCP+ packet exchanged between a remote console and a BMC.
+type packet struct {
+	payloadType byte
+	sessionID   uint32
+	sequence    uint32
+	payload     []byte
+}
+
+// sessionKeys holds the keys derived from the session integrity key (SIK) once a session is activated. Cipher suite 3
+// (RAKP-HMAC-SHA1, HMAC-SHA1-96 and AES-CBC-128) is the only suite supported.
+type sessionKeys struct {
+	integrity       []byte
+	confidentiality []byte
+}
+
+// newSessionKeys derives the integrity (K1) and confidentiality (K2) keys from a session integrity key.
+func newSessionKeys(sik []byte) *sessionKeys {
+	return &sessionKeys{
+		integrity:       hmacSHA1(sik, bytes.Repeat([]byte{0x01}, sha1.Size)),
+		confidentiality: hmacSHA1(sik, bytes.Repeat([]byte{0x02}, sha1.Size))[:aes.BlockSize],
+	}
+}
+
+// authCode computes the HMAC-SHA1-96 integrity code of a session packet.
+func (k *sessionKeys) authCode(data []byte) []byte {
+	return hmacSHA1(k.integrity, data)[:authCodeLength]
+}
+
+// encrypt encrypts a payload using AES-CBC-128. The encrypted payload is prefixed by its initialization vector.
+func (k *sessionKeys) encrypt(payload []byte) ([]byte, error) {
+	block, err := aes.NewCipher(k.confidentiality)
+	if err != nil {
+		return nil, err
+	}
+
+	// The confidentiality trailer consists of pad bytes 1, 2, 3... followed by the number of pad bytes, and brings
+	// the length of the plain text to a multiple of the block size.
+	padLength := (aes.BlockSize - (len(payload)+1)%aes.BlockSize) % aes.BlockSize
+	plainText := make([]byte, 0, len(payload)+padLength+1)
+	plainText = append(plainText, payload...)
+	for i := 1; i <= padLength; i++ {
+		plainText = append(plainText, byte(i))
+	}
+	plainText = append(plainText, byte(padLength))
+
+	encrypted := make([]byte, aes.BlockSize+len(plainText))
+	iv := encrypted[:aes.BlockSize]
+	if _, err = rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted[aes.BlockSize:], plainText)
+
+	return encrypted, nil
+}
+
+// decrypt decrypts an AES-CBC-128 payload and strips its confidentiality trailer.
+func (k *sessionKeys) decrypt(payload []byte) ([]byte, error) {
+	if len(payload) < 2*aes.BlockSize || len(payload)%aes.BlockSize != 0 {
+		return nil, ErrMalformedPacket{Reason: "encrypted payload has an invalid length"}
+	}
+
+	block, err := aes.NewCipher(k.confidentiality)
+	if err != nil {
+		return nil, err
+	}
+
+	plainText := make([]byte, len(payload)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, payload[:aes.BlockSize]).CryptBlocks(plainText, payload[aes.BlockSize:])
+
+	padLength := int(plainText[len(plainText)-1])
+	if padLength >= len(plainText) {
+		return nil, ErrMalformedPacket{Reason: "invalid confidentiality pad length"}
+	}
+
+	return plainText[:len(plainText)-padLength-1], nil
+}
+
+// encodePacket serializes an RMCP+ packet. When keys are supplied, the payload is encrypted and the packet is
+// authenticated.
+func encodePacket(p packet, keys *sessionKeys) ([]byte, error) {
+	payloadType := p.payloadType
+	payload := p.payload
+	if keys != nil {
+		var err error
+		if payload, err = keys.encrypt(payload); err != nil {
+			return nil, err
+		}
+
+		payloadType |= payloadEncrypted | payloadAuthenticated
+	}
+
+	header := make([]byte, rmcpHeaderLength+sessionHeaderLength)
+	copy(header, []byte{rmcpVersion, 0x00, rmcpSeqNoAck, rmcpClassIPMI})
+	header[4] = authTypeRMCPPlus
+	header[5] = payloadType
+	binary.LittleEndian.PutUint32(header[6:10], p.sessionID)
+	binary.LittleEndian.PutUint32(header[10:14], p.sequence)
+	binary.LittleEndian.PutUint16(header[14:16], uint16(len(payload)))
+
+	data := append(header, payload...)
+	if keys == nil {
+		return data, nil
+	}
+
+	// The integrity pad aligns the authenticated data, which spans from the authentication type through the next
+	// header field, to a multiple of four bytes.
+	padLength := (4 - (len(data)-rmcpHeaderLength+2)%4) % 4
+	for i := 0; i < padLength; i++ {
+		data = append(data, 0xff)
+	}
+	data = append(data, byte(padLength), rmcpClassIPMI)
+
+	return append(data, keys.authCode(data[rmcpHeaderLength:])...), nil
+}
+
+// decodePacket parses an RMCP+ packet, verifying its integrity and decrypting its payload when it is authenticated or
+// encrypted.
+func decodePacket(data []byte, keys *sessionKeys) (packet, error) {
+	var p packet
+	if len(data) < rmcpHeaderLength+sessionHeaderLength {
+		return p, ErrMalformedPacket{Reason: "packet is too short"}
+	}
+
+	if data[0] != rmcpVersion || data[3] != rmcpClassIPMI || data[4] != authTypeRMCPPlus {
+		return p, ErrMalformedPacket{Reason: "not an RMCP+ packet"}
+	}
+
+	payloadType := data[5]
+	p.payloadType = payloadType & payloadTypeMask
+	p.sessionID = binary.LittleEndian.Uint32(data[6:10])
+	p.sequence = binary.LittleEndian.Uint32(data[10:14])
+
+	payloadLength := int(binary.LittleEndian.Uint16(data[14:16]))
+	payloadEnd := rmcpHeaderLength + sessionHeaderLength + payloadLength
+	if len(data) < payloadEnd {
+		return p, ErrMalformedPacket{Reason: "payload length exceeds packet length"}
+	}
+	payload := data[rmcpHeaderLength+sessionHeaderLength : payloadEnd]
+
+	if payloadType&(payloadAuthenticated|payloadEncrypted) != 0 && keys == nil {
+		return p, ErrMalformedPacket{Reason: "secured packet received outside of a session"}
+	}
+
+	if payloadType&payloadAuthenticated != 0 {
+		if len(data) < payloadEnd+2+authCodeLength {
+			return p, ErrMalformedPacket{Reason: "authenticated packet is missing its trailer"}
+		}
+
+		authenticated := data[rmcpHeaderLength : len(data)-authCodeLength]
+		if !hmac.Equal(keys.authCode(authenticated), data[len(data)-authCodeLength:]) {
+			return p, ErrMalformedPacket{Reason: "integrity check failed"}
+		}
+	}
+
+	if payloadType&payloadEncrypted != 0 {
+		var err error
+		if payload, err = keys.decrypt(payload); err != nil {
+			return p, err
+		}
+	}
+
+	p.payload = append([]byte{}, payload...)
+
+	return p, nil
+}
+
+// encodeRequest serializes an IPMI LAN request message.
+func encodeRequest(netFn byte, command byte, sequence byte, data []byte) []byte {
+	msg := []byte{bmcSlaveAddress, netFn << 2}
+	msg = append(msg, checksum(msg))
+
+	body := []byte{remoteSWID, sequence << 2, command}
+	body = append(body, data...)
+	body = append(body, checksum(body))
+
+	return append(msg, body...)
+}
+
+// response is a decoded IPMI LAN response message.
+type response struct {
+	netFn          byte
+	sequence       byte
+	command        byte
+	completionCode byte
+	data           []byte
+}
+
+// decodeResponse parses an IPMI LAN response message and validates its checksums.
+func decodeResponse(msg []byte) (response, error) {
+	var r response
+	if len(msg) < 8 {
+		return r, ErrMalformedPacket{Reason: "IPMI response is too short"}
+	}
+
+	if checksum(msg[:2]) != msg[2] || checksum(msg[3:len(msg)-1]) != msg[len(msg)-1] {
+		return r, ErrMalformedPacket{Reason: "IPMI response checksum mismatch"}
+	}
+
+	r.netFn = msg[1] >> 2
+	r.sequence = msg[4] >> 2
+	r.command = msg[5]
+	r.completionCode = msg[6]
+	r.data = msg[7 : len(msg)-1]
+
+	return r, nil
+}
+
+// checksum computes the two's complement checksum used by IPMI messages.
+func checksum(data []byte) byte {
+	var sum byte
+	for _, b := range data {
+		sum += b
+	}
+
+	return -sum
+}
+
+// hmacSHA1 computes the HMAC-SHA1 of the concatenation of the supplied byte slices.
+func hmacSHA1(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(sha1.New, key)
+	for _, d := range data {
+		mac.Write(d) //nolint:errcheck // hash writes never fail
+	}
+
+	return mac.Sum(nil)
+}
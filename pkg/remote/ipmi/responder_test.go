@@ -0,0 +1,342 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipmi
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBMC is a minimal IPMI v2.0 BMC listening on a local UDP port. It supports the RMCP+ session handshake for a
+// single user and tracks chassis power and boot flags in memory.
+type fakeBMC struct {
+	conn     net.PacketConn
+	username string
+	password string
+
+	mu          sync.Mutex
+	powerOn     bool
+	hung        bool
+	bootFlags   []byte
+	commands    []byte
+	dropPackets int
+	openStatus  byte
+	sessions    map[uint32]*fakeSession
+}
+
+// fakeSession holds the state of a session established with a fakeBMC.
+type fakeSession struct {
+	consoleID []byte
+	managedID []byte
+	rm        []byte
+	rc        []byte
+	role      byte
+	user      []byte
+	keys      *sessionKeys
+}
+
+var fakeGUID = []byte("airshipctl-guid!")
+
+// newFakeBMC starts a fake BMC that serves requests until it is closed.
+func newFakeBMC(t *testing.T, username, password string) *fakeBMC {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	bmc := &fakeBMC{
+		conn:      conn,
+		username:  username,
+		password:  password,
+		bootFlags: []byte{0x00, 0x00, 0x00, 0x00, 0x00},
+		sessions:  make(map[uint32]*fakeSession),
+	}
+
+	go bmc.serve()
+
+	return bmc
+}
+
+// Close stops the fake BMC.
+func (b *fakeBMC) Close() {
+	b.conn.Close()
+}
+
+// Address returns the host:port the fake BMC listens on.
+func (b *fakeBMC) Address() string {
+	return b.conn.LocalAddr().String()
+}
+
+// PowerOn reports whether the chassis of the fake BMC is powered on.
+func (b *fakeBMC) PowerOn() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.powerOn
+}
+
+// SetPowerOn sets the chassis power state of the fake BMC.
+func (b *fakeBMC) SetPowerOn(on bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.powerOn = on
+}
+
+// SetHung instructs the fake BMC to ignore soft shutdown requests, as a host whose operating system is hung does.
+func (b *fakeBMC) SetHung(hung bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.hung = hung
+}
+
+// DropPackets instructs the fake BMC to ignore the next n packets it receives.
+func (b *fakeBMC) DropPackets(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.dropPackets = n
+}
+
+// RejectSessions instructs the fake BMC to reject Open Session requests with an RMCP+ status code.
+func (b *fakeBMC) RejectSessions(status byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.openStatus = status
+}
+
+// Commands returns the IPMI commands received by the fake BMC within sessions, in order.
+func (b *fakeBMC) Commands() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]byte{}, b.commands...)
+}
+
+func (b *fakeBMC) serve() {
+	buf := make([]byte, maxPacketLength)
+	for {
+		n, addr, err := b.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		b.mu.Lock()
+		drop := b.dropPackets > 0
+		if drop {
+			b.dropPackets--
+		}
+		b.mu.Unlock()
+
+		if drop {
+			continue
+		}
+
+		if resp := b.handle(append([]byte{}, buf[:n]...)); resp != nil {
+			b.conn.WriteTo(resp, addr) //nolint:errcheck
+		}
+	}
+}
+
+func (b *fakeBMC) handle(data []byte) []byte {
+	if len(data) < rmcpHeaderLength+sessionHeaderLength {
+		return nil
+	}
+
+	sessionID := binary.LittleEndian.Uint32(data[6:10])
+	if sessionID != 0 {
+		b.mu.Lock()
+		s, ok := b.sessions[sessionID]
+		b.mu.Unlock()
+		if !ok {
+			return nil
+		}
+
+		return b.handleCommand(s, data)
+	}
+
+	p, err := decodePacket(data, nil)
+	if err != nil {
+		return nil
+	}
+
+	switch p.payloadType {
+	case payloadOpenSessionRequest:
+		return b.handleOpenSession(p.payload)
+	case payloadRAKP1:
+		return b.handleRAKP1(p.payload)
+	case payloadRAKP3:
+		return b.handleRAKP3(p.payload)
+	}
+
+	return nil
+}
+
+func (b *fakeBMC) handleOpenSession(req []byte) []byte {
+	b.mu.Lock()
+	status := b.openStatus
+	b.mu.Unlock()
+
+	if status != rmcpPlusStatusNoErrors {
+		return rejectPayload(payloadOpenSessionResponse, req[0], status, req[4:8])
+	}
+
+	s := &fakeSession{
+		consoleID: append([]byte{}, req[4:8]...),
+		managedID: []byte{0x11, 0x22, 0x33, 0x44},
+	}
+
+	b.mu.Lock()
+	b.sessions[binary.LittleEndian.Uint32(s.managedID)] = s
+	b.mu.Unlock()
+
+	resp := []byte{req[0], rmcpPlusStatusNoErrors, privilegeLevelAdmin, 0x00}
+	resp = append(resp, s.consoleID...)
+	resp = append(resp, s.managedID...)
+	resp = append(resp, req[8:]...)
+
+	return mustEncode(packet{payloadType: payloadOpenSessionResponse, payload: resp}, nil)
+}
+
+func (b *fakeBMC) handleRAKP1(req []byte) []byte {
+	b.mu.Lock()
+	s := b.sessions[binary.LittleEndian.Uint32(req[4:8])]
+	b.mu.Unlock()
+
+	s.rm = append([]byte{}, req[8:24]...)
+	s.role = req[24]
+	s.user = append([]byte{}, req[27:]...)
+	s.rc = []byte("0123456789abcdef")
+
+	if string(req[28:]) != b.username {
+		return rejectPayload(payloadRAKP2, req[0], 0x0d, s.consoleID) // unauthorized name
+	}
+
+	resp := []byte{req[0], rmcpPlusStatusNoErrors, 0x00, 0x00}
+	resp = append(resp, s.consoleID...)
+	resp = append(resp, s.rc...)
+	resp = append(resp, fakeGUID...)
+	resp = append(resp, hmacSHA1([]byte(b.password), s.consoleID, s.managedID, s.rm, s.rc, fakeGUID,
+		[]byte{s.role}, s.user)...)
+
+	return mustEncode(packet{payloadType: payloadRAKP2, payload: resp}, nil)
+}
+
+func (b *fakeBMC) handleRAKP3(req []byte) []byte {
+	b.mu.Lock()
+	s := b.sessions[binary.LittleEndian.Uint32(req[4:8])]
+	b.mu.Unlock()
+
+	expected := hmacSHA1([]byte(b.password), s.rc, s.consoleID, []byte{s.role}, s.user)
+	if !hmac.Equal(expected, req[8:]) {
+		return rejectPayload(payloadRAKP4, req[0], rmcpPlusStatusInvalidIntegrity, s.consoleID)
+	}
+
+	sik := hmacSHA1([]byte(b.password), s.rm, s.rc, []byte{s.role}, s.user)
+	s.keys = newSessionKeys(sik)
+
+	resp := []byte{req[0], rmcpPlusStatusNoErrors, 0x00, 0x00}
+	resp = append(resp, s.consoleID...)
+	resp = append(resp, hmacSHA1(sik, s.rm, s.managedID, fakeGUID)[:authCodeLength]...)
+
+	return mustEncode(packet{payloadType: payloadRAKP4, payload: resp}, nil)
+}
+
+func (b *fakeBMC) handleCommand(s *fakeSession, data []byte) []byte {
+	if s.keys == nil {
+		return nil
+	}
+
+	p, err := decodePacket(data, s.keys)
+	if err != nil || p.payloadType != payloadIPMI || len(p.payload) < 7 {
+		return nil
+	}
+
+	msg := p.payload
+	netFn := msg[1] >> 2
+	rqSeq := msg[4] >> 2
+	cmd := msg[5]
+	reqData := msg[6 : len(msg)-1]
+
+	b.mu.Lock()
+	b.commands = append(b.commands, cmd)
+	completionCode, respData := b.execute(netFn, cmd, reqData)
+	b.mu.Unlock()
+
+	header := []byte{remoteSWID, (netFn + 1) << 2}
+	header = append(header, checksum(header))
+	body := []byte{bmcSlaveAddress, rqSeq << 2, cmd, completionCode}
+	body = append(body, respData...)
+	body = append(body, checksum(body))
+
+	return mustEncode(packet{
+		payloadType: payloadIPMI,
+		sessionID:   binary.LittleEndian.Uint32(s.consoleID),
+		sequence:    p.sequence,
+		payload:     append(header, body...),
+	}, s.keys)
+}
+
+// execute performs a command against the in-memory state of the fake BMC. The caller must hold the lock.
+func (b *fakeBMC) execute(netFn, cmd byte, data []byte) (byte, []byte) {
+	switch {
+	case netFn == netFnApp && cmd == cmdSetSessionPrivilege:
+		return 0x00, []byte{data[0]}
+	case netFn == netFnApp && cmd == cmdCloseSession:
+		return 0x00, nil
+	case netFn == netFnChassis && cmd == cmdGetChassisStatus:
+		var state byte
+		if b.powerOn {
+			state = chassisStatusPowerOn
+		}
+		return 0x00, []byte{state, 0x00, 0x00}
+	case netFn == netFnChassis && cmd == cmdChassisControl:
+		// A hung operating system ignores soft shutdown requests
+		if data[0] != chassisSoftShutdown || !b.hung {
+			b.powerOn = data[0] == chassisPowerUp
+		}
+		return 0x00, nil
+	case netFn == netFnChassis && cmd == cmdSetSystemBootOptions && data[0] == bootOptionBootFlags:
+		b.bootFlags = append([]byte{}, data[1:]...)
+		return 0x00, nil
+	case netFn == netFnChassis && cmd == cmdGetSystemBootOptions && data[0] == bootOptionBootFlags:
+		return 0x00, append([]byte{0x01, bootOptionBootFlags}, b.bootFlags...)
+	}
+
+	// Invalid command
+	return 0xc1, nil
+}
+
+// rejectPayload returns the short form of a pre-session response that BMCs send when rejecting a request, which only
+// holds the message tag, the status code and the console session ID.
+func rejectPayload(payloadType, tag, status byte, consoleID []byte) []byte {
+	resp := append([]byte{tag, status, 0x00, 0x00}, consoleID...)
+
+	return mustEncode(packet{payloadType: payloadType, payload: resp}, nil)
+}
+
+func mustEncode(p packet, keys *sessionKeys) []byte {
+	data, err := encodePacket(p, keys)
+	if err != nil {
+		panic(err)
+	}
+
+	return data
+}
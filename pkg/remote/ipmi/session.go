@@ -0,0 +1,329 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipmi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// Network functions and commands used by the client.
+const (
+	netFnChassis byte = 0x00
+	netFnApp     byte = 0x06
+
+	cmdGetChassisStatus     byte = 0x01
+	cmdChassisControl       byte = 0x02
+	cmdSetSystemBootOptions byte = 0x08
+	cmdGetSystemBootOptions byte = 0x09
+	cmdSetSessionPrivilege  byte = 0x3b
+	cmdCloseSession         byte = 0x3c
+)
+
+// Session establishment parameters. Sessions are always established with cipher suite 3 at the administrator
+// privilege level.
+const (
+	privilegeLevelAdmin     byte = 0x04
+	privilegeLookupNameOnly byte = 0x10
+	algorithmRAKPHMACSHA1   byte = 0x01
+	algorithmHMACSHA196     byte = 0x01
+	algorithmAESCBC128      byte = 0x01
+
+	rmcpPlusStatusNoErrors         byte = 0x00
+	rmcpPlusStatusInvalidIntegrity byte = 0x0f
+
+	randomNumberLength = 16
+	maxPacketLength    = 1024
+
+	defaultResponseTimeout    = 2 * time.Second
+	defaultRetransmitAttempts = 3
+)
+
+// session is an authenticated RMCP+ (IPMI v2.0 lanplus) session with a BMC.
+type session struct {
+	conn     net.Conn
+	address  string
+	username string
+	password string
+
+	consoleID uint32
+	managedID uint32
+	sequence  uint32
+	rqSeq     byte
+	keys      *sessionKeys
+
+	timeout  time.Duration
+	attempts int
+}
+
+// openSession establishes an RMCP+ session with the BMC at address using cipher suite 3 and activates it with the
+// administrator privilege level.
+func openSession(ctx context.Context, address, username, password string, timeout time.Duration,
+	attempts int) (*session, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &session{
+		conn:     conn,
+		address:  address,
+		username: username,
+		password: password,
+		timeout:  timeout,
+		attempts: attempts,
+	}
+
+	if err = s.handshake(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if _, err = s.command(ctx, netFnApp, cmdSetSessionPrivilege, []byte{privilegeLevelAdmin}); err != nil {
+		s.close(ctx)
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// handshake performs the Open Session and RAKP exchanges and derives the session keys.
+func (s *session) handshake(ctx context.Context) error {
+	var consoleID [4]byte
+	if _, err := rand.Read(consoleID[:]); err != nil {
+		return err
+	}
+	consoleID[0] |= 0x01 // session ID zero is reserved for pre-session messages
+	s.consoleID = binary.LittleEndian.Uint32(consoleID[:])
+
+	// Open Session Request
+	openReq := []byte{0x00, 0x00, 0x00, 0x00}
+	openReq = append(openReq, consoleID[:]...)
+	openReq = append(openReq,
+		0x00, 0x00, 0x00, 0x08, algorithmRAKPHMACSHA1, 0x00, 0x00, 0x00,
+		0x01, 0x00, 0x00, 0x08, algorithmHMACSHA196, 0x00, 0x00, 0x00,
+		0x02, 0x00, 0x00, 0x08, algorithmAESCBC128, 0x00, 0x00, 0x00)
+
+	openResp, err := s.exchange(ctx, payloadOpenSessionRequest, openReq, payloadOpenSessionResponse)
+	if err != nil {
+		return err
+	}
+
+	if openResp[1] != rmcpPlusStatusNoErrors {
+		return ErrSessionFailed{Step: "open session", Status: openResp[1]}
+	}
+
+	if len(openResp) < 12 {
+		return ErrMalformedPacket{Reason: "Open Session response is too short"}
+	}
+	s.managedID = binary.LittleEndian.Uint32(openResp[8:12])
+
+	managedID := openResp[8:12]
+	role := privilegeLevelAdmin | privilegeLookupNameOnly
+	user := append([]byte{byte(len(s.username))}, s.username...)
+
+	// RAKP Message 1
+	rm := make([]byte, randomNumberLength)
+	if _, err = rand.Read(rm); err != nil {
+		return err
+	}
+
+	rakp1 := []byte{0x00, 0x00, 0x00, 0x00}
+	rakp1 = append(rakp1, managedID...)
+	rakp1 = append(rakp1, rm...)
+	rakp1 = append(rakp1, role, 0x00, 0x00)
+	rakp1 = append(rakp1, user...)
+
+	rakp2, err := s.exchange(ctx, payloadRAKP1, rakp1, payloadRAKP2)
+	if err != nil {
+		return err
+	}
+
+	if rakp2[1] != rmcpPlusStatusNoErrors {
+		return ErrSessionFailed{Step: "RAKP message 2", Status: rakp2[1]}
+	}
+
+	if len(rakp2) < 8+2*randomNumberLength+20 {
+		return ErrMalformedPacket{Reason: "RAKP message 2 is too short"}
+	}
+
+	rc := rakp2[8:24]
+	guid := rakp2[24:40]
+	kuid := []byte(s.password)
+
+	expected := hmacSHA1(kuid, consoleID[:], managedID, rm, rc, guid, []byte{role}, user)
+	if !hmac.Equal(expected, rakp2[40:60]) {
+		return ErrSessionFailed{Step: "RAKP message 2 authentication", Status: rmcpPlusStatusInvalidIntegrity}
+	}
+
+	sik := hmacSHA1(kuid, rm, rc, []byte{role}, user)
+
+	// RAKP Message 3
+	rakp3 := []byte{0x00, rmcpPlusStatusNoErrors, 0x00, 0x00}
+	rakp3 = append(rakp3, managedID...)
+	rakp3 = append(rakp3, hmacSHA1(kuid, rc, consoleID[:], []byte{role}, user)...)
+
+	rakp4, err := s.exchange(ctx, payloadRAKP3, rakp3, payloadRAKP4)
+	if err != nil {
+		return err
+	}
+
+	if rakp4[1] != rmcpPlusStatusNoErrors {
+		return ErrSessionFailed{Step: "RAKP message 4", Status: rakp4[1]}
+	}
+
+	if len(rakp4) < 8+authCodeLength {
+		return ErrMalformedPacket{Reason: "RAKP message 4 is too short"}
+	}
+
+	if !hmac.Equal(hmacSHA1(sik, rm, managedID, guid)[:authCodeLength], rakp4[8:8+authCodeLength]) {
+		return ErrSessionFailed{Step: "RAKP message 4 authentication", Status: rmcpPlusStatusInvalidIntegrity}
+	}
+
+	s.keys = newSessionKeys(sik)
+
+	log.Debugf("Established IPMI session 0x%08x with BMC '%s'.", s.managedID, s.address)
+
+	return nil
+}
+
+// exchange sends a pre-session payload and waits for a response payload of the expected type. Responses only need to
+// hold a message tag and a status code, since a BMC rejecting the request may omit the remaining fields.
+func (s *session) exchange(ctx context.Context, reqType byte, payload []byte, respType byte) ([]byte, error) {
+	var resp []byte
+	err := s.roundTrip(ctx,
+		func() ([]byte, error) {
+			return encodePacket(packet{payloadType: reqType, payload: payload}, nil)
+		},
+		func(data []byte) bool {
+			p, err := decodePacket(data, nil)
+			if err != nil || p.payloadType != respType || len(p.payload) < 2 {
+				return false
+			}
+
+			resp = p.payload
+			return true
+		})
+
+	return resp, err
+}
+
+// command sends an IPMI request within the session and returns the data of the response.
+func (s *session) command(ctx context.Context, netFn, cmd byte, data []byte) ([]byte, error) {
+	s.rqSeq = (s.rqSeq + 1) & 0x3f
+	rqSeq := s.rqSeq
+	msg := encodeRequest(netFn, cmd, rqSeq, data)
+
+	var resp response
+	err := s.roundTrip(ctx,
+		func() ([]byte, error) {
+			// Every retransmission is a new packet and must carry a new session sequence number.
+			s.sequence++
+			return encodePacket(packet{
+				payloadType: payloadIPMI,
+				sessionID:   s.managedID,
+				sequence:    s.sequence,
+				payload:     msg,
+			}, s.keys)
+		},
+		func(data []byte) bool {
+			p, err := decodePacket(data, s.keys)
+			if err != nil || p.payloadType != payloadIPMI || p.sessionID != s.consoleID {
+				return false
+			}
+
+			r, err := decodeResponse(p.payload)
+			if err != nil || r.netFn != netFn+1 || r.sequence != rqSeq || r.command != cmd {
+				return false
+			}
+
+			resp = r
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.completionCode != 0 {
+		return nil, ErrCompletionCode{NetFn: netFn, Command: cmd, Code: resp.completionCode}
+	}
+
+	return resp.data, nil
+}
+
+// roundTrip sends the packet produced by encode and reads packets until accept reports the expected response was
+// received, retransmitting when no response arrives in time.
+func (s *session) roundTrip(ctx context.Context, encode func() ([]byte, error), accept func([]byte) bool) error {
+	buf := make([]byte, maxPacketLength)
+	for attempt := 0; attempt < s.attempts; attempt++ {
+		req, err := encode()
+		if err != nil {
+			return err
+		}
+
+		if _, err = s.conn.Write(req); err != nil {
+			return err
+		}
+
+		deadline := time.Now().Add(s.timeout)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
+
+		if err = s.conn.SetReadDeadline(deadline); err != nil {
+			return err
+		}
+
+		for {
+			n, err := s.conn.Read(buf)
+			if err != nil {
+				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+					break
+				}
+
+				return err
+			}
+
+			if accept(buf[:n]) {
+				return nil
+			}
+		}
+
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	return ErrNoResponse{Address: s.address, Retries: s.attempts}
+}
+
+// close closes the session with the BMC and releases its connection.
+func (s *session) close(ctx context.Context) {
+	closeReq := make([]byte, 4)
+	binary.LittleEndian.PutUint32(closeReq, s.managedID)
+
+	if _, err := s.command(ctx, netFnApp, cmdCloseSession, closeReq); err != nil {
+		log.Debugf("Failed to close IPMI session 0x%08x with BMC '%s': %v", s.managedID, s.address, err)
+	}
+
+	if err := s.conn.Close(); err != nil {
+		log.Debugf("Failed to close connection to BMC '%s': %v", s.address, err)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package isoserver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoBMCHost is returned when the address of a BMC does not contain a host.
+var ErrNoBMCHost = errors.New("BMC address has no host")
+
+// ErrNotAFile is returned when the ISO image to serve is not a regular file.
+type ErrNotAFile struct {
+	Path string
+}
+
+func (e ErrNotAFile) Error() string {
+	return fmt.Sprintf("unable to serve ISO image '%s': not a file", e.Path)
+}
+
+// ErrUnknownLocalAddress is returned when the address the BMC of a host uses to reach the server cannot be
+// determined. The advertise address of the server must be configured in that case.
+type ErrUnknownLocalAddress struct {
+	BMCAddress string
+	Err        error
+}
+
+func (e ErrUnknownLocalAddress) Error() string {
+	return fmt.Sprintf("unable to determine the local address used to reach BMC '%s': %v. Configure the "+
+		"advertise address of the ISO server", e.BMCAddress, e.Err)
+}
@@ -0,0 +1,246 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package isoserver provides an HTTP server that serves an ISO image to the BMC of a host, so that the host can
+// boot from it over virtual media.
+package isoserver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// shutdownTimeout bounds how long requests in progress are waited for when the server is closed.
+var shutdownTimeout = 5 * time.Second
+
+// Server serves a single ISO image over HTTP. Range requests are supported, as BMCs typically read virtual media
+// in chunks rather than downloading the whole image.
+type Server struct {
+	// AdvertiseAddress is the address BMCs use to reach the server. When empty, it is derived by URL.
+	AdvertiseAddress string
+
+	isoPath     string
+	bindAddress string
+	port        int
+	listener    net.Listener
+	server      *http.Server
+	errs        chan error
+}
+
+// NewServer starts a server that serves the ISO image located at isoPath on bindAddress and port. The image is
+// served at the root of the server under its file name.
+func NewServer(isoPath, bindAddress string, port int) (*Server, error) {
+	if err := checkISO(isoPath); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(bindAddress, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		isoPath:     isoPath,
+		bindAddress: bindAddress,
+		port:        port,
+		listener:    listener,
+		errs:        make(chan error, 1),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.Path(), s.serveISO)
+	s.server = &http.Server{Handler: mux}
+
+	go func() {
+		s.errs <- s.server.Serve(listener)
+	}()
+
+	log.Debugf("Serving ISO image '%s' on '%s'.", isoPath, listener.Addr())
+
+	return s, nil
+}
+
+// NewDryRunServer returns a server for the ISO image located at isoPath, bindAddress and port that does not listen, so
+// that the URL a server started by NewServer would advertise is known in dry run without serving the image. Closing
+// the server does nothing.
+func NewDryRunServer(isoPath, bindAddress string, port int) (*Server, error) {
+	if err := checkISO(isoPath); err != nil {
+		return nil, err
+	}
+
+	return &Server{isoPath: isoPath, bindAddress: bindAddress, port: port}, nil
+}
+
+// checkISO verifies that the ISO image located at isoPath is a file that can be served.
+func checkISO(isoPath string) error {
+	info, err := os.Stat(isoPath)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return ErrNotAFile{Path: isoPath}
+	}
+
+	return nil
+}
+
+// Path returns the path of the ISO image on the server.
+func (s *Server) Path() string {
+	return "/" + filepath.Base(s.isoPath)
+}
+
+// Port returns the port the server listens on, or would listen on in dry run.
+func (s *Server) Port() int {
+	if s.listener == nil {
+		return s.port
+	}
+
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// URL returns the URL of the ISO image for a BMC located at bmcAddress. The host of the URL is the advertise address
+// of the server when set, then the address the server is bound to, and finally the local address used to reach the
+// BMC when the server listens on all addresses.
+func (s *Server) URL(bmcAddress string) (string, error) {
+	host := s.AdvertiseAddress
+	if host == "" {
+		host = s.boundHost()
+	}
+
+	if host == "" {
+		var err error
+		if host, err = localAddress(bmcAddress); err != nil {
+			return "", err
+		}
+	}
+
+	isoURL := url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(host, strconv.Itoa(s.Port())),
+		Path:   s.Path(),
+	}
+
+	return isoURL.String(), nil
+}
+
+// Close stops the server. Requests in progress are given shutdownTimeout to complete, after which their connections
+// are closed, so that a BMC holding a long read of the image open does not prevent the server from stopping.
+func (s *Server) Close() error {
+	if s.server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		log.Debugf("Closing the connections still reading ISO image '%s': %v", s.isoPath, err)
+		if err = s.server.Close(); err != nil {
+			return err
+		}
+	}
+
+	if err := <-s.errs; err != http.ErrServerClosed {
+		return err
+	}
+
+	log.Debugf("Stopped serving ISO image '%s'.", s.isoPath)
+	return nil
+}
+
+func (s *Server) serveISO(w http.ResponseWriter, r *http.Request) {
+	log.Debugf("Serving %s %s (range '%s') to '%s'.", r.Method, r.URL.Path, r.Header.Get("Range"), r.RemoteAddr)
+
+	if r.URL.Path != s.Path() {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	iso, err := os.Open(s.isoPath)
+	if err != nil {
+		log.Printf("Unable to open ISO image '%s': %v", s.isoPath, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer iso.Close()
+
+	info, err := iso.Stat()
+	if err != nil {
+		log.Printf("Unable to open ISO image '%s': %v", s.isoPath, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// ServeContent handles Range, If-Range and HEAD requests
+	http.ServeContent(w, r, info.Name(), info.ModTime(), iso)
+}
+
+// boundHost returns the host the server is bound to, or an empty string when it listens on all addresses.
+func (s *Server) boundHost() string {
+	if s.listener == nil {
+		if ip := net.ParseIP(s.bindAddress); ip == nil || ip.IsUnspecified() {
+			return ""
+		}
+
+		return s.bindAddress
+	}
+
+	addr := s.listener.Addr().(*net.TCPAddr)
+	if addr.IP.IsUnspecified() {
+		return ""
+	}
+
+	return addr.IP.String()
+}
+
+// localAddress returns the local address used to reach the BMC located at bmcAddress.
+func localAddress(bmcAddress string) (string, error) {
+	bmcURL, err := url.Parse(bmcAddress)
+	if err != nil {
+		return "", err
+	}
+
+	if bmcURL.Hostname() == "" {
+		return "", ErrUnknownLocalAddress{BMCAddress: bmcAddress, Err: ErrNoBMCHost}
+	}
+
+	port := bmcURL.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	// Connecting a UDP socket only selects the route to the BMC; no packets are sent.
+	conn, err := net.Dial("udp", net.JoinHostPort(bmcURL.Hostname(), port))
+	if err != nil {
+		return "", ErrUnknownLocalAddress{BMCAddress: bmcAddress, Err: err}
+	}
+	defer conn.Close()
+
+	return conn.LocalAddr().(*net.UDPAddr).IP.String(), nil
+}
@@ -0,0 +1,236 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package isoserver
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const isoContent = "0123456789abcdef"
+
+func newTestServer(t *testing.T, bindAddress string) (*Server, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "airshipctl-isoserver-")
+	require.NoError(t, err)
+
+	isoPath := filepath.Join(dir, "ephemeral.iso")
+	require.NoError(t, ioutil.WriteFile(isoPath, []byte(isoContent), 0600))
+
+	server, err := NewServer(isoPath, bindAddress, 0)
+	if err != nil {
+		os.RemoveAll(dir)
+		require.NoError(t, err)
+	}
+
+	return server, func() {
+		assert.NoError(t, server.Close())
+		os.RemoveAll(dir)
+	}
+}
+
+func get(t *testing.T, isoURL, byteRange string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, isoURL, nil)
+	require.NoError(t, err)
+
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp, string(body)
+}
+
+func TestServeISO(t *testing.T) {
+	server, cleanup := newTestServer(t, "127.0.0.1")
+	defer cleanup()
+
+	isoURL, err := server.URL("redfish+https://10.23.25.1:8000/redfish/v1/Systems/1")
+	require.NoError(t, err)
+	assert.Equal(t, "http://127.0.0.1:"+strconv.Itoa(server.Port())+"/ephemeral.iso", isoURL)
+
+	resp, body := get(t, isoURL, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "bytes", resp.Header.Get("Accept-Ranges"))
+	assert.Equal(t, isoContent, body)
+
+	resp, body = get(t, isoURL, "bytes=4-7")
+	assert.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	assert.Equal(t, "bytes 4-7/16", resp.Header.Get("Content-Range"))
+	assert.Equal(t, "4567", body)
+
+	resp, _ = get(t, isoURL, "bytes=32-")
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.StatusCode)
+
+	resp, _ = get(t, "http://127.0.0.1:"+strconv.Itoa(server.Port())+"/other.iso", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestCloseWithRequestInProgress(t *testing.T) {
+	defer func(timeout time.Duration) { shutdownTimeout = timeout }(shutdownTimeout)
+	shutdownTimeout = 50 * time.Millisecond
+
+	dir, err := ioutil.TempDir("", "airshipctl-isoserver-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	isoPath := filepath.Join(dir, "ephemeral.iso")
+	require.NoError(t, ioutil.WriteFile(isoPath, []byte(isoContent), 0600))
+
+	server, err := NewServer(isoPath, "127.0.0.1", 0)
+	require.NoError(t, err)
+
+	// A BMC reading the image keeps its request in progress
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(server.Port())))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("GET /ephemeral.iso HTTP/1.1\r\n"))
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+
+	closed := make(chan error, 1)
+	go func() {
+		closed <- server.Close()
+	}()
+
+	select {
+	case err = <-closed:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+}
+
+func TestServeISOMethodNotAllowed(t *testing.T) {
+	server, cleanup := newTestServer(t, "127.0.0.1")
+	defer cleanup()
+
+	isoURL, err := server.URL("redfish+https://127.0.0.1:8000/redfish/v1/Systems/1")
+	require.NoError(t, err)
+
+	resp, err := http.Post(isoURL, "application/octet-stream", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestNewServerNotAFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airshipctl-isoserver-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = NewServer(dir, "127.0.0.1", 0)
+	assert.Equal(t, ErrNotAFile{Path: dir}, err)
+
+	_, err = NewServer(filepath.Join(dir, "missing.iso"), "127.0.0.1", 0)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestURL(t *testing.T) {
+	// The server listens on all addresses, so that the local address used to reach the BMC is advertised
+	server, cleanup := newTestServer(t, "")
+	defer cleanup()
+
+	port := strconv.Itoa(server.Port())
+
+	tests := []struct {
+		name             string
+		bmcAddress       string
+		advertiseAddress string
+		expectedURL      string
+		expectedErr      error
+	}{
+		{
+			name:             "AdvertiseAddress",
+			bmcAddress:       "redfish+https://10.23.25.1/redfish/v1/Systems/1",
+			advertiseAddress: "10.23.24.1",
+			expectedURL:      "http://10.23.24.1:" + port + "/ephemeral.iso",
+		},
+		{
+			name:             "AdvertiseIPv6Address",
+			advertiseAddress: "fd00::1",
+			expectedURL:      "http://[fd00::1]:" + port + "/ephemeral.iso",
+		},
+		{
+			name:        "LocalAddress",
+			bmcAddress:  "redfish+https://127.0.0.1:8000/redfish/v1/Systems/1",
+			expectedURL: "http://127.0.0.1:" + port + "/ephemeral.iso",
+		},
+		{
+			name:        "NoBMCHost",
+			bmcAddress:  "/redfish/v1/Systems/1",
+			expectedErr: ErrUnknownLocalAddress{BMCAddress: "/redfish/v1/Systems/1", Err: ErrNoBMCHost},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server.AdvertiseAddress = tt.advertiseAddress
+
+			isoURL, err := server.URL(tt.bmcAddress)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Equal(t, tt.expectedURL, isoURL)
+		})
+	}
+}
+
+func TestDryRunServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airshipctl-isoserver-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	isoPath := filepath.Join(dir, "ephemeral.iso")
+	require.NoError(t, ioutil.WriteFile(isoPath, []byte(isoContent), 0600))
+
+	server, err := NewDryRunServer(isoPath, "10.23.24.1", 8099)
+	require.NoError(t, err)
+	assert.Equal(t, 8099, server.Port())
+
+	isoURL, err := server.URL("redfish+https://10.23.25.1/redfish/v1/Systems/1")
+	require.NoError(t, err)
+	assert.Equal(t, "http://10.23.24.1:8099/ephemeral.iso", isoURL)
+
+	// The advertise address takes precedence over the bind address
+	server.AdvertiseAddress = "ephemeral.example.com"
+	isoURL, err = server.URL("redfish+https://10.23.25.1/redfish/v1/Systems/1")
+	require.NoError(t, err)
+	assert.Equal(t, "http://ephemeral.example.com:8099/ephemeral.iso", isoURL)
+
+	assert.NoError(t, server.Close())
+
+	_, err = NewDryRunServer(dir, "", 8099)
+	assert.Equal(t, ErrNotAFile{Path: dir}, err)
+}
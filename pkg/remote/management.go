@@ -17,26 +17,44 @@ package remote
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/bios"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
 	"opendev.org/airship/airshipctl/pkg/remote/power"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish/vendors"
 	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
 )
 
 // Client is a set of functions that clients created for out-of-band power management and control should implement. The
 // functions within client are used by power management commands and remote direct functionality.
 type Client interface {
+	BIOSSettings(context.Context) (bios.Settings, error)
+	BootOverride(context.Context) (boot.Override, error)
 	EjectVirtualMedia(context.Context) error
+	FirmwareInventory(context.Context) ([]inventory.Firmware, error)
+	InsertVirtualMedia(context.Context, string) (string, error)
+	Inventory(context.Context) (inventory.Inventory, error)
 	NodeID() string
 	RebootSystem(context.Context) error
+	RebootSystemGraceful(context.Context, time.Duration) error
+	SetBIOSAttributes(context.Context, bios.Attributes) error
+	SetBootOverride(context.Context, boot.Override) error
 	SetBootSourceByType(context.Context) error
+	SupportsVirtualMedia(context.Context) (bool, error)
 	SystemPowerOff(context.Context) error
+	SystemPowerOffGraceful(context.Context, time.Duration) error
 	SystemPowerOn(context.Context) error
 	SystemPowerStatus(context.Context) (power.Status, error)
+	UpdateFirmware(context.Context, string, []string) error
 
 	// TODO(drewwalters96): This function is tightly coupled to Redfish. It should be combined with the
 	// SetBootSource operation and removed from the client interface.
@@ -49,111 +67,136 @@ type Client interface {
 type Manager struct {
 	Config config.ManagementConfiguration
 	Hosts  []baremetalHost
+
+	// docBundle holds the documents of the phase the hosts were selected from.
+	docBundle document.Bundle
+	// audit records the actions performed on the hosts; actions are not recorded when it is nil.
+	audit *AuditLog
 }
 
 // baremetalHost is an airshipctl representation of a baremetal host, defined by a baremetal host document, that embeds
 // actions an out-of-band client can perform. Once instantiated, actions can be performed on a baremetal host.
 type baremetalHost struct {
 	Client
-	Context    context.Context
-	BMCAddress string
-	HostName   string
-	username   string
-	password   string
+	Context        context.Context
+	BMCAddress     string
+	HostName       string
+	Namespace      string
+	username       string
+	password       string
+	BootMACAddress string
+
+	// ManagementType is the type of the client used to manage the host, e.g. "redfish-dell" when the vendor of the
+	// host's BMC was detected, and CredentialsSource describes where its BMC credentials were read from.
+	ManagementType    string
+	CredentialsSource string
 }
 
-// HostSelector populates baremetal hosts within a manager when supplied with selection criteria.
-type HostSelector func(*Manager, config.ManagementConfiguration, document.Bundle) error
-
-// ByLabel adds all hosts to a manager whose documents match a supplied label selector.
-func ByLabel(label string) HostSelector {
-	return func(a *Manager, mgmtCfg config.ManagementConfiguration, docBundle document.Bundle) error {
-		selector := document.NewSelector().ByKind(document.BareMetalHostKind).ByLabel(label)
-		docs, err := docBundle.Select(selector)
-		if err != nil {
-			return err
-		}
-
-		if len(docs) == 0 {
-			return document.ErrDocNotFound{Selector: selector}
-		}
-
-		var matchingHosts []baremetalHost
-		for _, doc := range docs {
-			host, err := newBaremetalHost(mgmtCfg, doc, docBundle)
-			if err != nil {
-				return err
-			}
-
-			matchingHosts = append(matchingHosts, host)
-		}
-
-		a.Hosts = reconcileHosts(a.Hosts, matchingHosts...)
+// NewManager provides a manager that exposes the capability to perform remote direct functionality and other
+// out-of-band management on multiple hosts.
+func NewManager(settings *environment.AirshipCTLSettings, phase string, hosts ...HostSelector) (*Manager, error) {
+	managementCfg, docBundle, err := loadPhase(settings, phase)
+	if err != nil {
+		return nil, err
+	}
 
-		return nil
+	manager := &Manager{
+		Config:    *managementCfg,
+		Hosts:     []baremetalHost{},
+		docBundle: docBundle,
+		audit:     NewAuditLog(settings),
 	}
-}
 
-// ByName adds the host to a manager whose document meets the specified name.
-func ByName(name string) HostSelector {
-	return func(a *Manager, mgmtCfg config.ManagementConfiguration, docBundle document.Bundle) error {
-		selector := document.NewSelector().ByKind(document.BareMetalHostKind).ByName(name)
-		doc, err := docBundle.SelectOne(selector)
-		if err != nil {
-			return err
-		}
+	// Only the hosts selected by every selector, which are built from CLI arguments and airshipctl settings, are
+	// managed; combine selectors with AnyOf to manage the hosts selected by any of them.
+	docs, err := AllOf(hosts...)(docBundle)
+	if err != nil {
+		return nil, err
+	}
 
-		host, err := newBaremetalHost(mgmtCfg, doc, docBundle)
+	for _, doc := range docs {
+		host, err := newBaremetalHost(*managementCfg, doc, docBundle)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		a.Hosts = reconcileHosts(a.Hosts, host)
+		manager.Hosts = append(manager.Hosts, host)
+	}
 
-		return nil
+	if len(manager.Hosts) == 0 {
+		return manager, ErrNoHostsFound{}
 	}
+
+	return manager, nil
 }
 
-// NewManager provides a manager that exposes the capability to perform remote direct functionality and other
-// out-of-band management on multiple hosts.
-func NewManager(settings *environment.AirshipCTLSettings, phase string, hosts ...HostSelector) (*Manager, error) {
+// loadPhase retrieves the management configuration of the current context and the document bundle of a phase.
+func loadPhase(settings *environment.AirshipCTLSettings,
+	phase string) (*config.ManagementConfiguration, document.Bundle, error) {
 	managementCfg, err := settings.Config.CurrentContextManagementConfig()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err = managementCfg.Validate(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	entrypoint, err := settings.Config.CurrentContextEntryPoint(phase)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	docBundle, err := document.NewBundleByPath(entrypoint)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	manager := &Manager{
-		Config: *managementCfg,
-		Hosts:  []baremetalHost{},
+	return managementCfg, docBundle, nil
+}
+
+// WithContext binds the actions performed on the manager's hosts to ctx, so that they are cancelled once ctx is done,
+// e.g. when airshipctl is interrupted. The values carried by the contexts of the hosts, e.g. their basic
+// authentication credentials, are preserved.
+func (m *Manager) WithContext(ctx context.Context) {
+	for i := range m.Hosts {
+		m.Hosts[i].Context = boundContext{Context: ctx, values: m.Hosts[i].Context}
 	}
+}
 
-	// Each function in hosts modifies the list of hosts for the new manager based on selection criteria provided
-	// by CLI arguments and airshipctl settings.
-	for _, addHost := range hosts {
-		if err := addHost(manager, *managementCfg, docBundle); err != nil {
-			return nil, err
-		}
+// boundContext is a context that is done when its embedded context is done, and that carries the values of both its
+// embedded context and the context of a host.
+type boundContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c boundContext) Value(key interface{}) interface{} {
+	if value := c.values.Value(key); value != nil {
+		return value
 	}
 
-	if len(manager.Hosts) == 0 {
-		return manager, ErrNoHostsFound{}
+	return c.Context.Value(key)
+}
+
+// Close releases the resources held by the clients of the manager's hosts, e.g. Redfish sessions. It should be called
+// once no further actions will be performed on the hosts.
+func (m *Manager) Close() {
+	for _, host := range m.Hosts {
+		closeHost(host)
 	}
+}
 
-	return manager, nil
+// closeHost releases the resources held by the client of a host, e.g. a Redfish session.
+func closeHost(host baremetalHost) {
+	closer, ok := host.Client.(io.Closer)
+	if !ok {
+		return
+	}
+
+	if err := closer.Close(); err != nil {
+		log.Printf("Failed to close management client of host '%s': %v", host.HostName, err)
+	}
 }
 
 // newBaremetalHost creates a representation of a baremetal host that is configured to perform management actions by
@@ -163,75 +206,138 @@ func newBaremetalHost(mgmtCfg config.ManagementConfiguration,
 	docBundle document.Bundle) (baremetalHost, error) {
 	var host baremetalHost
 
+	credentials, err := NewCredentialProvider(mgmtCfg.Credentials)
+	if err != nil {
+		return host, err
+	}
+
+	username, password, err := credentials.Credentials(hostDoc, docBundle)
+	if err != nil {
+		return host, err
+	}
+
+	host, err = newBaremetalHostWithCredentials(mgmtCfg, hostDoc, username, password)
+	host.CredentialsSource = credentials.Source()
+
+	return host, err
+}
+
+// newBaremetalHostWithCredentials creates a representation of a baremetal host as newBaremetalHost does, using BMC
+// credentials that were already resolved.
+func newBaremetalHostWithCredentials(mgmtCfg config.ManagementConfiguration, hostDoc document.Document, username,
+	password string) (baremetalHost, error) {
+	var host baremetalHost
+
 	address, err := document.GetBMHBMCAddress(hostDoc)
 	if err != nil {
 		return host, err
 	}
 
-	username, password, err := document.GetBMHBMCCredentials(hostDoc, docBundle)
+	// The boot MAC address is optional; it is only used to verify the inventory of a host.
+	bootMACAddress, err := document.GetBMHBootMACAddress(hostDoc)
+	if err != nil {
+		log.Debugf("Baremetal host '%s' does not define a boot MAC address.", hostDoc.GetName())
+	}
+
+	tlsConfig, err := mgmtCfg.TLSConfig()
+	if err != nil {
+		return host, err
+	}
+
+	// Select the client that corresponds to the scheme of the host's BMC address, falling back to the management type
+	// specified in the airshipctl config. When the management type is auto, the client is selected by detecting the
+	// vendor of the host's BMC.
+	mgmtType, err := hostManagementType(hostDoc.GetName(), address, mgmtCfg.Type)
 	if err != nil {
 		return host, err
 	}
 
-	// Select the client that corresponds to the management type specified in the airshipctl config.
-	switch mgmtCfg.Type {
+	if mgmtType == vendors.ClientType {
+		mgmtType, err = vendors.DetectClientType(address, tlsConfig, mgmtCfg.UseProxy, username, password)
+		if err != nil {
+			return host, err
+		}
+	}
+
+	switch mgmtType {
 	case redfish.ClientType:
 		log.Debug("Remote type: Redfish")
 		ctx, client, err := redfish.NewClient(
 			address,
-			mgmtCfg.Insecure,
+			tlsConfig,
 			mgmtCfg.UseProxy,
+			mgmtCfg.SessionAuth,
 			username,
 			password,
-			mgmtCfg.SystemActionRetries,
-			mgmtCfg.SystemRebootDelay)
+			mgmtCfg.RetryPolicy())
 
 		if err != nil {
 			return host, err
 		}
 
-		host = baremetalHost{client, ctx, address, hostDoc.GetName(), username, password}
+		host = baremetalHost{
+			Client:         client,
+			Context:        ctx,
+			BMCAddress:     address,
+			HostName:       hostDoc.GetName(),
+			Namespace:      hostDoc.GetNamespace(),
+			username:       username,
+			password:       password,
+			BootMACAddress: bootMACAddress,
+			ManagementType: mgmtType,
+		}
 	case redfishdell.ClientType:
 		log.Debug("Remote type: Redfish for Integrated Dell Remote Access Controller (iDrac) systems")
 		ctx, client, err := redfishdell.NewClient(
 			address,
-			mgmtCfg.Insecure,
+			tlsConfig,
 			mgmtCfg.UseProxy,
+			mgmtCfg.SessionAuth,
 			username,
 			password,
-			mgmtCfg.SystemActionRetries,
-			mgmtCfg.SystemRebootDelay)
+			mgmtCfg.RetryPolicy())
 
 		if err != nil {
 			return host, err
 		}
 
-		host = baremetalHost{client, ctx, address, hostDoc.GetName(), username, password}
-	default:
-		return host, ErrUnknownManagementType{Type: mgmtCfg.Type}
-	}
-
-	return host, nil
-}
-
-// reconcileHosts produces the intersection of two baremetal host arrays.
-func reconcileHosts(existingHosts []baremetalHost, newHosts ...baremetalHost) []baremetalHost {
-	if len(existingHosts) == 0 {
-		return newHosts
-	}
+		host = baremetalHost{
+			Client:         client,
+			Context:        ctx,
+			BMCAddress:     address,
+			HostName:       hostDoc.GetName(),
+			Namespace:      hostDoc.GetNamespace(),
+			username:       username,
+			password:       password,
+			BootMACAddress: bootMACAddress,
+			ManagementType: mgmtType,
+		}
+	case ipmi.ClientType:
+		log.Debug("Remote type: IPMI v2.0 (lanplus)")
+		ctx, client, err := ipmi.NewClient(
+			address,
+			username,
+			password,
+			mgmtCfg.RetryPolicy())
 
-	// Create a map of host document names for efficient filtering
-	hostMap := make(map[string]bool)
-	for _, host := range existingHosts {
-		hostMap[host.HostName] = true
-	}
+		if err != nil {
+			return host, err
+		}
 
-	var reconciledHosts []baremetalHost
-	for _, host := range newHosts {
-		if _, exists := hostMap[host.HostName]; exists {
-			reconciledHosts = append(reconciledHosts, host)
+		host = baremetalHost{
+			Client:         client,
+			Context:        ctx,
+			BMCAddress:     address,
+			HostName:       hostDoc.GetName(),
+			Namespace:      hostDoc.GetNamespace(),
+			username:       username,
+			password:       password,
+			BootMACAddress: bootMACAddress,
+			ManagementType: mgmtType,
 		}
+	default:
+		return host, ErrUnknownManagementType{Type: mgmtType}
 	}
 
-	return reconciledHosts
+	return host, nil
 }
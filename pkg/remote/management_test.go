@@ -15,6 +15,7 @@
 package remote
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -24,6 +25,7 @@ import (
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/document"
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote/ipmi"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
 	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
 	"opendev.org/airship/airshipctl/testutil"
@@ -115,6 +117,25 @@ func TestNewManagerMultipleSelectorsNoMatch(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestNewManagerUnionOfSelectors(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("base"))
+
+	manager, err := NewManager(settings, config.BootstrapPhase,
+		AnyOf(ByName("master-2"), ByLabel(document.EphemeralHostSelector)))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(manager.Hosts))
+
+	assert.Equal(t, "node-master-2", manager.Hosts[0].NodeID())
+	assert.Equal(t, "ephemeral", manager.Hosts[1].NodeID())
+}
+
+func TestNewManagerInvalidBMCAddressPattern(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("base"))
+
+	_, err := NewManager(settings, config.BootstrapPhase, ByBMCAddress("[nolocalhost"))
+	assert.IsType(t, ErrInvalidBMCAddressPattern{}, err)
+}
+
 func TestNewManagerByNameNoHostFound(t *testing.T) {
 	settings := initSettings(t, withTestDataPath("base"))
 
@@ -152,6 +173,14 @@ func TestNewManagerRedfishDell(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNewManagerIPMI(t *testing.T) {
+	cfg := &config.ManagementConfiguration{Type: ipmi.ClientType}
+	settings := initSettings(t, withManagementConfig(cfg), withTestDataPath("base"))
+
+	_, err := NewManager(settings, config.BootstrapPhase, ByLabel(document.EphemeralHostSelector))
+	assert.NoError(t, err)
+}
+
 func TestNewManagerUnknownRemoteType(t *testing.T) {
 	badCfg := &config.ManagementConfiguration{Type: "bad-remote-type"}
 	settings := initSettings(t, withManagementConfig(badCfg), withTestDataPath("base"))
@@ -180,3 +209,22 @@ func TestNewManagerBadPhase(t *testing.T) {
 	_, err := NewManager(settings, "bad-phase", ByLabel(document.EphemeralHostSelector))
 	assert.Error(t, err)
 }
+
+func TestManagerWithContext(t *testing.T) {
+	type key string
+	hostCtx := context.WithValue(context.Background(), key("credentials"), "admin")
+
+	m := &Manager{Hosts: []baremetalHost{{Context: hostCtx, HostName: "node-1"}}}
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key("phase"), "bootstrap"))
+	m.WithContext(ctx)
+
+	bound := m.Hosts[0].Context
+	assert.NoError(t, bound.Err())
+	assert.Equal(t, "admin", bound.Value(key("credentials")))
+	assert.Equal(t, "bootstrap", bound.Value(key("phase")))
+
+	cancel()
+	<-bound.Done()
+	assert.Equal(t, context.Canceled, bound.Err())
+}
@@ -0,0 +1,55 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+)
+
+// HostPowerStatus describes the power status of a baremetal host.
+type HostPowerStatus struct {
+	HostName   string `json:"name"`
+	BMCAddress string `json:"bmcAddress"`
+	NodeID     string `json:"nodeID"`
+	PowerState string `json:"powerState,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// PowerStatus retrieves the power status of every host selected by the manager, acting on up to concurrency hosts at
+// once. A power status is returned for every host; when it cannot be retrieved from some hosts, their power statuses
+// record the failure and an ErrHostActionsFailed error is returned alongside them.
+func (m *Manager) PowerStatus(concurrency int) ([]HostPowerStatus, error) {
+	powerStatus := func(ctx context.Context, client Client) (string, error) {
+		status, err := client.SystemPowerStatus(ctx)
+		return status.String(), err
+	}
+
+	results, err := m.Execute("power status", concurrency, powerStatus)
+
+	statuses := make([]HostPowerStatus, 0, len(m.Hosts))
+	for i, host := range m.Hosts {
+		status := HostPowerStatus{HostName: host.HostName, BMCAddress: host.BMCAddress, NodeID: host.NodeID()}
+
+		if result := results[i]; result.Err != nil {
+			status.Error = collapse(result.Err)
+		} else {
+			status.PowerState = result.Output
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, err
+}
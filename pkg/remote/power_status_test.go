@@ -0,0 +1,55 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+)
+
+func TestPowerStatus(t *testing.T) {
+	host1, rMock1 := newMockHost(t, "node-1")
+	host2, rMock2 := newMockHost(t, "node-2")
+
+	rMock1.On("SystemPowerStatus", host1.Context).Times(1).Return(power.StatusOn, nil)
+	rMock1.On("NodeID").Return("System.Embedded.1")
+	rMock2.On("SystemPowerStatus", host2.Context).Times(1).
+		Return(power.StatusUnknown, redfish.ErrRedfishClient{Message: "BMC\nunavailable"})
+	rMock2.On("NodeID").Return("1")
+
+	m := &Manager{Hosts: []baremetalHost{host1, host2}}
+
+	statuses, err := m.PowerStatus(2)
+	_, ok := err.(ErrHostActionsFailed)
+	assert.True(t, ok)
+
+	expected := []HostPowerStatus{
+		{HostName: "node-1", BMCAddress: redfishURL, NodeID: "System.Embedded.1", PowerState: "ON"},
+		{
+			HostName:   "node-2",
+			BMCAddress: redfishURL,
+			NodeID:     "1",
+			Error:      "redfish client encountered an error: BMC unavailable",
+		},
+	}
+	assert.Equal(t, expected, statuses)
+
+	rMock1.AssertExpectations(t)
+	rMock2.AssertExpectations(t)
+}
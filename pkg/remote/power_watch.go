@@ -0,0 +1,93 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// DefaultPowerWatchInterval is the interval between polls of the power status of hosts when no interval is specified.
+const DefaultPowerWatchInterval = 10 * time.Second
+
+// PowerTransition records a change in the power status of a baremetal host observed while watching it. The first
+// status observed for a host is reported as a transition from an empty status.
+type PowerTransition struct {
+	HostName  string    `json:"host"`
+	OldStatus string    `json:"oldStatus,omitempty"`
+	NewStatus string    `json:"newStatus"`
+	Time      time.Time `json:"timestamp"`
+}
+
+// WatchPowerStatus polls the power status of every host selected by the manager, acting on up to concurrency hosts
+// at once, until ctx is done. Each poll starts interval after the previous one completes, and each change in the power
+// status of a host is passed to report. A host whose power status cannot be retrieved keeps its last known status; the
+// failure is logged once rather than on every poll. Polls are not recorded in the audit log.
+func (m *Manager) WatchPowerStatus(ctx context.Context, concurrency int, interval time.Duration,
+	report func(PowerTransition)) {
+	if interval <= 0 {
+		interval = DefaultPowerWatchInterval
+	}
+
+	powerStatus := func(ctx context.Context, client Client) (string, error) {
+		status, err := client.SystemPowerStatus(ctx)
+		return status.String(), err
+	}
+
+	statuses := make([]string, len(m.Hosts))
+	failures := make([]string, len(m.Hosts))
+
+	for {
+		results, _ := m.execute("power status", concurrency, func(int) HostAction { return powerStatus }, nil)
+		if ctx.Err() != nil {
+			// Polls interrupted by the end of the watch fail, and are not reported
+			return
+		}
+
+		now := time.Now()
+
+		for i, result := range results {
+			if result.Err != nil {
+				if failures[i] != result.Err.Error() {
+					failures[i] = result.Err.Error()
+					log.Printf("Unable to retrieve power status of host '%s': %v", result.HostName, result.Err)
+				}
+
+				continue
+			}
+
+			failures[i] = ""
+			if statuses[i] == result.Output {
+				continue
+			}
+
+			report(PowerTransition{
+				HostName:  result.HostName,
+				OldStatus: statuses[i],
+				NewStatus: result.Output,
+				Time:      now,
+			})
+			statuses[i] = result.Output
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
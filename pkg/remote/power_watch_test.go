@@ -0,0 +1,82 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+)
+
+func TestWatchPowerStatus(t *testing.T) {
+	host1, rMock1 := newMockHost(t, "node-1")
+	host2, rMock2 := newMockHost(t, "node-2")
+
+	rMock1.On("SystemPowerStatus", host1.Context).Once().Return(power.StatusOff, nil)
+	rMock1.On("SystemPowerStatus", host1.Context).Return(power.StatusOn, nil)
+
+	// A failed poll neither reports a transition nor loses the last known status
+	rMock2.On("SystemPowerStatus", host2.Context).Once().Return(power.StatusOn, nil)
+	rMock2.On("SystemPowerStatus", host2.Context).Once().
+		Return(power.StatusUnknown, redfish.ErrRedfishClient{Message: "BMC unavailable"})
+	rMock2.On("SystemPowerStatus", host2.Context).Return(power.StatusOn, nil)
+
+	m := &Manager{Hosts: []baremetalHost{host1, host2}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transitions []PowerTransition
+	m.WatchPowerStatus(ctx, 2, time.Millisecond, func(transition PowerTransition) {
+		assert.False(t, transition.Time.IsZero())
+		transition.Time = time.Time{}
+
+		transitions = append(transitions, transition)
+		if len(transitions) == 3 {
+			cancel()
+		}
+	})
+
+	expected := []PowerTransition{
+		{HostName: "node-1", NewStatus: "OFF"},
+		{HostName: "node-2", NewStatus: "ON"},
+		{HostName: "node-1", OldStatus: "OFF", NewStatus: "ON"},
+	}
+	assert.Equal(t, expected, transitions)
+}
+
+func TestWatchPowerStatusInterrupted(t *testing.T) {
+	host, rMock := newMockHost(t, "node-1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The watch ends while the power status is being retrieved, which makes the poll fail
+	rMock.On("SystemPowerStatus", host.Context).Once().Run(func(mock.Arguments) { cancel() }).
+		Return(power.StatusUnknown, context.Canceled)
+
+	m := &Manager{Hosts: []baremetalHost{host}}
+	m.WatchPowerStatus(ctx, 1, time.Millisecond, func(transition PowerTransition) {
+		t.Errorf("unexpected power status change %v", transition)
+	})
+
+	rMock.AssertExpectations(t)
+}
@@ -0,0 +1,140 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"reflect"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/bios"
+)
+
+// applyTimeOnReset instructs a BMC to apply staged settings on the next reboot of a host.
+const applyTimeOnReset = "OnReset"
+
+// biosResource holds the properties of a Bios resource and of its settings object.
+type biosResource struct {
+	Attributes bios.Attributes `json:"Attributes"`
+	Settings   struct {
+		SettingsObject      odataID  `json:"SettingsObject"`
+		SupportedApplyTimes []string `json:"SupportedApplyTimes"`
+	} `json:"@Redfish.Settings"`
+}
+
+// biosSettingsRequest holds the attributes staged in a Bios settings object.
+type biosSettingsRequest struct {
+	Attributes bios.Attributes    `json:"Attributes"`
+	ApplyTime  *settingsApplyTime `json:"@Redfish.SettingsApplyTime,omitempty"`
+}
+
+// settingsApplyTime instructs a BMC when to apply the settings staged in a settings object.
+type settingsApplyTime struct {
+	ApplyTime string `json:"ApplyTime"`
+}
+
+// BIOSSettings retrieves the current BIOS attributes of a host from its Bios resource, along with the attributes
+// staged in the Bios settings object that have not been applied yet.
+func (c *Client) BIOSSettings(ctx context.Context) (bios.Settings, error) {
+	biosURI, err := c.biosURI(ctx)
+	if err != nil {
+		return bios.Settings{}, err
+	}
+
+	var resource biosResource
+	if err = c.getResource(ctx, biosURI, &resource); err != nil {
+		return bios.Settings{}, err
+	}
+
+	settings := bios.Settings{Current: resource.Attributes}
+
+	settingsURI := resource.Settings.SettingsObject.OdataID
+	if settingsURI == "" {
+		log.Debugf("Node '%s' does not advertise a Bios settings object.", c.nodeID)
+		return settings, nil
+	}
+
+	// Some BMCs return every attribute from the settings object, others only the staged ones. Only the attributes
+	// that differ from the current attributes are pending.
+	var staged biosResource
+	if err = c.getResource(ctx, settingsURI, &staged); err != nil {
+		return bios.Settings{}, err
+	}
+
+	for name, value := range staged.Attributes {
+		if currentValue, ok := settings.Current[name]; ok && reflect.DeepEqual(currentValue, value) {
+			continue
+		}
+
+		if settings.Pending == nil {
+			settings.Pending = bios.Attributes{}
+		}
+		settings.Pending[name] = value
+	}
+
+	return settings, nil
+}
+
+// SetBIOSAttributes stages BIOS attributes in the Bios settings object of a host. Staged attributes are applied by the
+// BMC on the next reboot of the host.
+func (c *Client) SetBIOSAttributes(ctx context.Context, attributes bios.Attributes) error {
+	biosURI, err := c.biosURI(ctx)
+	if err != nil {
+		return err
+	}
+
+	var resource biosResource
+	if err = c.getResource(ctx, biosURI, &resource); err != nil {
+		return err
+	}
+
+	settingsURI := resource.Settings.SettingsObject.OdataID
+	if settingsURI == "" {
+		settingsURI = biosURI + "/Settings"
+	}
+
+	// BMCs that support several apply times, e.g. iDRAC, only apply staged attributes on the next reboot of the host
+	// when asked to.
+	req := biosSettingsRequest{Attributes: attributes}
+	for _, applyTime := range resource.Settings.SupportedApplyTimes {
+		if applyTime == applyTimeOnReset {
+			req.ApplyTime = &settingsApplyTime{ApplyTime: applyTimeOnReset}
+		}
+	}
+
+	log.Debugf("Staging BIOS attributes %v of node '%s' in '%s'.", attributes.Names(), c.nodeID, settingsURI)
+
+	if err = c.patchResource(ctx, settingsURI, req); err != nil {
+		return err
+	}
+
+	log.Debug("Successfully staged BIOS attributes.")
+	return nil
+}
+
+// biosURI retrieves the location of the Bios resource of a host from its ComputerSystem resource.
+func (c *Client) biosURI(ctx context.Context) (string, error) {
+	var system struct {
+		Bios odataID `json:"Bios"`
+	}
+
+	if err := c.getResource(ctx, endpointSystems+c.nodeID, &system); err != nil {
+		return "", err
+	}
+
+	if system.Bios.OdataID == "" {
+		return endpointSystems + c.nodeID + "/Bios", nil
+	}
+
+	return system.Bios.OdataID, nil
+}
@@ -0,0 +1,149 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/bios"
+)
+
+// biosResources are the resources served by a fake BMC for a host with a pending change to one BIOS attribute.
+var biosResources = map[string]string{
+	"/redfish/v1/Systems/1": `{"Bios": {"@odata.id": "/redfish/v1/Systems/1/Bios"}}`,
+	"/redfish/v1/Systems/1/Bios": `{"Attributes": {"ProcVirtualization": "Disabled", "SriovGlobalEnable": false,
+		"ProcCores": 8}, "@Redfish.Settings": {"SettingsObject": {"@odata.id": "/redfish/v1/Systems/1/Bios/Settings"}}}`,
+	"/redfish/v1/Systems/1/Bios/Settings": `{"Attributes": {"ProcVirtualization": "Enabled",
+		"SriovGlobalEnable": false, "ProcCores": 8}}`,
+}
+
+// newBIOSClient creates a client of a fake BMC serving resources. The body of every PATCH request received by the BMC
+// is decoded into patches, keyed by the path of the request.
+func newBIOSClient(t *testing.T, resources map[string]string,
+	patches map[string]biosSettingsRequest) (*Client, func()) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				panic(err)
+			}
+
+			var patch biosSettingsRequest
+			if err = json.Unmarshal(body, &patch); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			patches[r.URL.Path] = patch
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		body, ok := resources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, err := w.Write([]byte(body))
+		if err != nil {
+			panic(err)
+		}
+	}))
+
+	_, client, err := NewClient(server.URL+"/redfish/v1/Systems/1", nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	return client, server.Close
+}
+
+func TestBIOSSettings(t *testing.T) {
+	client, closeServer := newBIOSClient(t, biosResources, nil)
+	defer closeServer()
+
+	settings, err := client.BIOSSettings(context.Background())
+	require.NoError(t, err)
+
+	expected := bios.Settings{
+		Current: bios.Attributes{"ProcVirtualization": "Disabled", "SriovGlobalEnable": false, "ProcCores": float64(8)},
+		Pending: bios.Attributes{"ProcVirtualization": "Enabled"},
+	}
+	assert.Equal(t, expected, settings)
+}
+
+func TestBIOSSettingsWithoutSettingsObject(t *testing.T) {
+	client, closeServer := newBIOSClient(t, map[string]string{
+		"/redfish/v1/Systems/1":      `{}`,
+		"/redfish/v1/Systems/1/Bios": `{"Attributes": {"ProcVirtualization": "Disabled"}}`,
+	}, nil)
+	defer closeServer()
+
+	settings, err := client.BIOSSettings(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, bios.Settings{Current: bios.Attributes{"ProcVirtualization": "Disabled"}}, settings)
+}
+
+func TestBIOSSettingsNotSupported(t *testing.T) {
+	client, closeServer := newBIOSClient(t, map[string]string{"/redfish/v1/Systems/1": `{}`}, nil)
+	defer closeServer()
+
+	_, err := client.BIOSSettings(context.Background())
+	_, ok := err.(ErrRedfishClient)
+	assert.True(t, ok)
+	assert.Contains(t, err.Error(), "Unable to retrieve '/redfish/v1/Systems/1/Bios'")
+}
+
+func TestSetBIOSAttributes(t *testing.T) {
+	patches := make(map[string]biosSettingsRequest)
+	client, closeServer := newBIOSClient(t, biosResources, patches)
+	defer closeServer()
+
+	attributes := bios.Attributes{"SriovGlobalEnable": true}
+	require.NoError(t, client.SetBIOSAttributes(context.Background(), attributes))
+
+	assert.Equal(t, map[string]biosSettingsRequest{
+		"/redfish/v1/Systems/1/Bios/Settings": {Attributes: attributes},
+	}, patches)
+}
+
+func TestSetBIOSAttributesOnReset(t *testing.T) {
+	resources := map[string]string{
+		"/redfish/v1/Systems/1": `{}`,
+		"/redfish/v1/Systems/1/Bios": `{"Attributes": {"ProcVirtualization": "Disabled"}, "@Redfish.Settings": {
+			"SupportedApplyTimes": ["Immediate", "OnReset"]}}`,
+	}
+
+	patches := make(map[string]biosSettingsRequest)
+	client, closeServer := newBIOSClient(t, resources, patches)
+	defer closeServer()
+
+	attributes := bios.Attributes{"ProcVirtualization": "Enabled"}
+	require.NoError(t, client.SetBIOSAttributes(context.Background(), attributes))
+
+	assert.Equal(t, map[string]biosSettingsRequest{
+		"/redfish/v1/Systems/1/Bios/Settings": {
+			Attributes: attributes,
+			ApplyTime:  &settingsApplyTime{ApplyTime: applyTimeOnReset},
+		},
+	}, patches)
+}
@@ -0,0 +1,117 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+
+	redfishClient "opendev.org/airship/go-redfish/client"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+)
+
+// bootSources maps vendor-neutral boot devices to Redfish boot sources.
+var bootSources = map[boot.Device]redfishClient.BootSource{
+	boot.DeviceNone: redfishClient.BOOTSOURCE_NONE,
+	boot.DevicePXE:  redfishClient.BOOTSOURCE_PXE,
+	boot.DeviceDisk: redfishClient.BOOTSOURCE_HDD,
+	boot.DeviceCD:   redfishClient.BOOTSOURCE_CD,
+	boot.DeviceBIOS: redfishClient.BOOTSOURCE_BIOS_SETUP,
+}
+
+// SetBootOverride instructs a host to boot from the supplied device, either on its next boot only or persistently.
+// Overriding the boot device with boot.DeviceNone clears any existing override.
+func (c *Client) SetBootOverride(ctx context.Context, override boot.Override) error {
+	bootSource, ok := bootSources[override.Device]
+	if !ok {
+		return ErrRedfishClient{Message: fmt.Sprintf("Boot device '%s' is not supported.", override.Device)}
+	}
+
+	system, httpResp, err := c.RedfishAPI.GetSystem(ctx, c.nodeID)
+	if err = ScreenRedfishError(httpResp, err); err != nil {
+		return err
+	}
+
+	// BMCs that do not advertise the boot sources they support are assumed to support every boot source.
+	allowableValues := system.Boot.BootSourceOverrideTargetRedfishAllowableValues
+	if override.Device != boot.DeviceNone && len(allowableValues) > 0 && !hasBootSource(allowableValues, bootSource) {
+		return ErrRedfishClient{Message: fmt.Sprintf("Node '%s' does not support boot source '%s'. Supported boot "+
+			"sources: %v.", c.nodeID, bootSource, allowableValues)}
+	}
+
+	systemReq := redfishClient.ComputerSystem{}
+	systemReq.Boot.BootSourceOverrideTarget = bootSource
+	switch {
+	case override.Device == boot.DeviceNone:
+		systemReq.Boot.BootSourceOverrideEnabled = redfishClient.BOOTSOURCEOVERRIDEENABLED_DISABLED
+	case override.Persistent:
+		systemReq.Boot.BootSourceOverrideEnabled = redfishClient.BOOTSOURCEOVERRIDEENABLED_CONTINUOUS
+	default:
+		systemReq.Boot.BootSourceOverrideEnabled = redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE
+	}
+
+	log.Debugf("Setting boot source override of node '%s' to '%s' (%s).", c.nodeID, bootSource,
+		systemReq.Boot.BootSourceOverrideEnabled)
+
+	_, httpResp, err = c.RedfishAPI.SetSystem(ctx, c.nodeID, systemReq)
+	if err = ScreenRedfishError(httpResp, err); err != nil {
+		return err
+	}
+
+	log.Debug("Successfully set boot device.")
+	return nil
+}
+
+// BootOverride retrieves the device a host is instructed to boot from and whether the instruction is persistent.
+// Boot sources without a vendor-neutral equivalent, e.g. a USB device, are reported as boot.DeviceUnknown.
+func (c *Client) BootOverride(ctx context.Context) (boot.Override, error) {
+	system, httpResp, err := c.RedfishAPI.GetSystem(ctx, c.nodeID)
+	if err = ScreenRedfishError(httpResp, err); err != nil {
+		return boot.Override{Device: boot.DeviceUnknown}, err
+	}
+
+	enabled := system.Boot.BootSourceOverrideEnabled
+	if enabled == "" || enabled == redfishClient.BOOTSOURCEOVERRIDEENABLED_DISABLED {
+		return boot.Override{Device: boot.DeviceNone}, nil
+	}
+
+	override := boot.Override{
+		Device:     boot.DeviceUnknown,
+		Persistent: enabled == redfishClient.BOOTSOURCEOVERRIDEENABLED_CONTINUOUS,
+	}
+
+	for device, bootSource := range bootSources {
+		if system.Boot.BootSourceOverrideTarget == bootSource {
+			override.Device = device
+		}
+	}
+
+	if override.Device == boot.DeviceUnknown {
+		log.Debugf("Node '%s' boots from boot source '%s', which has no vendor-neutral equivalent.", c.nodeID,
+			system.Boot.BootSourceOverrideTarget)
+	}
+
+	return override, nil
+}
+
+func hasBootSource(sources []redfishClient.BootSource, bootSource redfishClient.BootSource) bool {
+	for _, source := range sources {
+		if source == bootSource {
+			return true
+		}
+	}
+
+	return false
+}
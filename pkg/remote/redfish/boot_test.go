@@ -0,0 +1,183 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	redfishMocks "opendev.org/airship/go-redfish/api/mocks"
+	redfishClient "opendev.org/airship/go-redfish/client"
+
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	testutil "opendev.org/airship/airshipctl/testutil/redfishutils/helpers"
+)
+
+// matchBoot matches a ComputerSystem request that overrides the boot source of a system.
+func matchBoot(target redfishClient.BootSource, enabled redfishClient.BootSourceOverrideEnabled) interface{} {
+	return mock.MatchedBy(func(system redfishClient.ComputerSystem) bool {
+		return system.Boot.BootSourceOverrideTarget == target && system.Boot.BootSourceOverrideEnabled == enabled
+	})
+}
+
+func TestSetBootOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		override boot.Override
+		target   redfishClient.BootSource
+		enabled  redfishClient.BootSourceOverrideEnabled
+	}{
+		{
+			name:     "PXEOnce",
+			override: boot.Override{Device: boot.DevicePXE},
+			target:   redfishClient.BOOTSOURCE_PXE,
+			enabled:  redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE,
+		},
+		{
+			name:     "DiskPersistent",
+			override: boot.Override{Device: boot.DeviceDisk, Persistent: true},
+			target:   redfishClient.BOOTSOURCE_HDD,
+			enabled:  redfishClient.BOOTSOURCEOVERRIDEENABLED_CONTINUOUS,
+		},
+		{
+			name:     "None",
+			override: boot.Override{Device: boot.DeviceNone},
+			target:   redfishClient.BOOTSOURCE_NONE,
+			enabled:  redfishClient.BOOTSOURCEOVERRIDEENABLED_DISABLED,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			m := &redfishMocks.RedfishAPI{}
+			defer m.AssertExpectations(t)
+
+			_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			httpResp := &http.Response{StatusCode: 200}
+			m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), httpResp, nil)
+			m.On("SetSystem", ctx, client.nodeID, matchBoot(tt.target, tt.enabled)).Times(1).
+				Return(redfishClient.ComputerSystem{}, httpResp, nil)
+
+			client.RedfishAPI = m
+
+			assert.NoError(t, client.SetBootOverride(ctx, tt.override))
+		})
+	}
+}
+
+func TestSetBootOverrideUnsupportedBootSource(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), &http.Response{StatusCode: 200}, nil)
+
+	client.RedfishAPI = m
+
+	err = client.SetBootOverride(ctx, boot.Override{Device: boot.DeviceBIOS})
+	_, ok := err.(ErrRedfishClient)
+	assert.True(t, ok)
+	assert.Contains(t, err.Error(), "does not support boot source 'BiosSetup'")
+}
+
+func TestSetBootOverrideWithoutAllowableValues(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	system := testutil.GetTestSystem()
+	system.Boot.BootSourceOverrideTargetRedfishAllowableValues = nil
+
+	ctx := context.Background()
+	httpResp := &http.Response{StatusCode: 200}
+	m.On("GetSystem", ctx, client.nodeID).Return(system, httpResp, nil)
+	m.On("SetSystem", ctx, client.nodeID,
+		matchBoot(redfishClient.BOOTSOURCE_BIOS_SETUP, redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE)).Times(1).
+		Return(redfishClient.ComputerSystem{}, httpResp, nil)
+
+	client.RedfishAPI = m
+
+	assert.NoError(t, client.SetBootOverride(ctx, boot.Override{Device: boot.DeviceBIOS}))
+}
+
+func TestBootOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   redfishClient.BootSource
+		enabled  redfishClient.BootSourceOverrideEnabled
+		expected boot.Override
+	}{
+		{
+			name:     "Persistent",
+			target:   redfishClient.BOOTSOURCE_CD,
+			enabled:  redfishClient.BOOTSOURCEOVERRIDEENABLED_CONTINUOUS,
+			expected: boot.Override{Device: boot.DeviceCD, Persistent: true},
+		},
+		{
+			name:     "Once",
+			target:   redfishClient.BOOTSOURCE_PXE,
+			enabled:  redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE,
+			expected: boot.Override{Device: boot.DevicePXE},
+		},
+		{
+			name:     "Disabled",
+			target:   redfishClient.BOOTSOURCE_HDD,
+			enabled:  redfishClient.BOOTSOURCEOVERRIDEENABLED_DISABLED,
+			expected: boot.Override{Device: boot.DeviceNone},
+		},
+		{
+			name:     "Unknown",
+			target:   redfishClient.BOOTSOURCE_USB,
+			enabled:  redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE,
+			expected: boot.Override{Device: boot.DeviceUnknown},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			m := &redfishMocks.RedfishAPI{}
+			defer m.AssertExpectations(t)
+
+			_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+			require.NoError(t, err)
+
+			system := testutil.GetTestSystem()
+			system.Boot.BootSourceOverrideTarget = tt.target
+			system.Boot.BootSourceOverrideEnabled = tt.enabled
+
+			ctx := context.Background()
+			m.On("GetSystem", ctx, client.nodeID).Return(system, &http.Response{StatusCode: 200}, nil)
+
+			client.RedfishAPI = m
+
+			override, err := client.BootOverride(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, override)
+		})
+	}
+}
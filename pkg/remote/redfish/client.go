@@ -25,6 +25,7 @@ import (
 
 	"opendev.org/airship/airshipctl/pkg/log"
 	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
 )
 
 const (
@@ -34,11 +35,12 @@ const (
 
 // Client holds details about a Redfish out-of-band system required for out-of-band management.
 type Client struct {
-	nodeID              string
-	RedfishAPI          redfishAPI.RedfishAPI
-	RedfishCFG          *redfishClient.Configuration
-	systemActionRetries int
-	systemRebootDelay   int
+	nodeID      string
+	RedfishAPI  redfishAPI.RedfishAPI
+	RedfishCFG  *redfishClient.Configuration
+	retryPolicy retry.Policy
+	session     *sessionTransport
+	dryRun      *dryRunTransport
 
 	// Sleep is meant to be mocked out for tests
 	Sleep func(d time.Duration)
@@ -49,32 +51,38 @@ func (c *Client) NodeID() string {
 	return c.nodeID
 }
 
-// SystemActionRetries returns number of attempts to reach host during reboot process and ejecting virtual media
-func (c *Client) SystemActionRetries() int {
-	return c.systemActionRetries
+// RetryPolicy returns the policy used to poll the host until power, media and boot operations complete.
+func (c *Client) RetryPolicy() retry.Policy {
+	return c.retryPolicy
 }
 
-// SystemRebootDelay returns number of seconds to wait after reboot if host isn't available
-func (c *Client) SystemRebootDelay() int {
-	return c.systemRebootDelay
+// Close deletes the Redfish session used by the client, if one was created. Clients that authenticate with basic
+// authentication credentials hold no session and have nothing to close.
+func (c *Client) Close() error {
+	if c.session == nil {
+		return nil
+	}
+
+	return c.session.logout()
 }
 
 // EjectVirtualMedia ejects a virtual media device attached to a host.
 func (c *Client) EjectVirtualMedia(ctx context.Context) error {
 	waitForEjectMedia := func(managerID string, mediaID string) error {
-		for retry := 0; retry < c.systemActionRetries; retry++ {
-			vMediaMgr, httpResp, err := c.RedfishAPI.GetManagerVirtualMedia(ctx, managerID, mediaID)
-			if err = ScreenRedfishError(httpResp, err); err != nil {
-				return err
-			}
-
-			if *vMediaMgr.Inserted == false {
-				log.Debugf("Successfully ejected virtual media.")
-				return nil
-			}
-		}
-
-		return ErrOperationRetriesExceeded{What: fmt.Sprintf("eject media %s", mediaID), Retries: c.systemActionRetries}
+		return c.poll(ctx, c.retryPolicy, fmt.Sprintf("eject media %s", mediaID),
+			func(ctx context.Context) (bool, error) {
+				vMediaMgr, httpResp, err := c.RedfishAPI.GetManagerVirtualMedia(ctx, managerID, mediaID)
+				if err = ScreenRedfishError(httpResp, err); err != nil {
+					return false, err
+				}
+
+				if *vMediaMgr.Inserted == false {
+					log.Debugf("Successfully ejected virtual media.")
+					return true, nil
+				}
+
+				return false, nil
+			})
 	}
 
 	managerID, err := getManagerID(ctx, c.RedfishAPI, c.nodeID)
@@ -104,6 +112,7 @@ func (c *Client) EjectVirtualMedia(ctx context.Context) error {
 			if err = ScreenRedfishError(httpResp, err); err != nil {
 				return err
 			}
+			c.recordEject(managerID, mediaID)
 
 			if err = waitForEjectMedia(managerID, mediaID); err != nil {
 				return err
@@ -181,38 +190,64 @@ func (c *Client) SetBootSourceByType(ctx context.Context) error {
 	return ErrRedfishClient{Message: fmt.Sprintf("failed to set system[%s] boot source", c.nodeID)}
 }
 
+// SupportsVirtualMedia reports whether the manager of a host has a virtual media device of type CD or DVD, which is
+// required to boot the host from an ISO image.
+func (c *Client) SupportsVirtualMedia(ctx context.Context) (bool, error) {
+	media, err := findVirtualMedia(ctx, c.RedfishAPI, c.nodeID)
+	if err != nil {
+		return false, err
+	}
+
+	return media.ID != "", nil
+}
+
 // SetVirtualMedia injects a virtual media device to an established virtual media ID. This assumes that isoPath is
 // accessible to the redfish server and virtualMedia device is either of type CD or DVD.
 func (c *Client) SetVirtualMedia(ctx context.Context, isoPath string) error {
-	log.Debugf("Inserting virtual media '%s'.", isoPath)
 	// Eject all previously-inserted media
 	if err := c.EjectVirtualMedia(ctx); err != nil {
 		return err
 	}
 
+	_, err := c.InsertVirtualMedia(ctx, isoPath)
+	return err
+}
+
+// InsertVirtualMedia inserts an ISO image into a virtual media device of type CD or DVD and returns the ID of the
+// device. Media that is already inserted is not ejected; the insertion fails when the device already holds media.
+// This assumes that isoPath is accessible to the redfish server.
+func (c *Client) InsertVirtualMedia(ctx context.Context, isoPath string) (string, error) {
+	log.Debugf("Inserting virtual media '%s'.", isoPath)
+
 	// Retrieve the ID of a compatible media type
-	vMediaID, _, err := GetVirtualMediaID(ctx, c.RedfishAPI, c.nodeID)
+	media, err := findVirtualMedia(ctx, c.RedfishAPI, c.nodeID)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	managerID, err := getManagerID(ctx, c.RedfishAPI, c.nodeID)
-	if err != nil {
-		return err
+	if media.ID == "" {
+		return "", ErrRedfishClient{Message: fmt.Sprintf("Manager '%s' does not have virtual media type CD or DVD.",
+			media.ManagerID)}
+	}
+
+	// In dry run, media the client ejected is still reported as inserted, since the eject request was not sent
+	if media.Inserted && !c.ejectedInDryRun(media.ManagerID, media.ID) {
+		return "", ErrRedfishClient{Message: fmt.Sprintf("Virtual media '%s' of manager '%s' already has media "+
+			"'%s' inserted. Eject the media and try again.", media.ID, media.ManagerID, media.Image)}
 	}
 
 	// Insert media
 	vMediaReq := redfishClient.InsertMediaRequestBody{}
 	vMediaReq.Image = isoPath
 	vMediaReq.Inserted = true
-	_, httpResp, err := c.RedfishAPI.InsertVirtualMedia(ctx, managerID, vMediaID, vMediaReq)
+	_, httpResp, err := c.RedfishAPI.InsertVirtualMedia(ctx, media.ManagerID, media.ID, vMediaReq)
 
 	if err = ScreenRedfishError(httpResp, err); err != nil {
-		return err
+		return "", err
 	}
 
-	log.Debug("Successfully set virtual media.")
-	return nil
+	log.Debugf("Successfully inserted virtual media into '%s'.", media.ID)
+	return media.ID, nil
 }
 
 // SystemPowerOff shuts down a host.
@@ -262,16 +297,20 @@ func (c *Client) SystemPowerStatus(ctx context.Context) (power.Status, error) {
 	}
 }
 
-// NewClient returns a client with the capability to make Redfish requests.
+// NewClient returns a client with the capability to make Redfish requests. When sessionAuth is set, requests are
+// authenticated with a session token obtained from the SessionService of the BMC rather than with basic authentication
+// credentials; the session is created on the first request and must be deleted by calling Close. The TLS configuration
+// determines how the certificate of the BMC is verified; the system's trusted CAs are used when it is nil. The retry
+// policy determines how long and how often the host is polled until power, media and boot operations complete.
 func NewClient(redfishURL string,
-	insecure bool,
+	tlsConfig *tls.Config,
 	useProxy bool,
+	sessionAuth bool,
 	username string,
 	password string,
-	systemActionRetries int,
-	systemRebootDelay int) (context.Context, *Client, error) {
+	retryPolicy retry.Policy) (context.Context, *Client, error) {
 	var ctx context.Context
-	if username != "" && password != "" {
+	if username != "" && password != "" && !sessionAuth {
 		ctx = context.WithValue(
 			context.Background(),
 			redfishClient.ContextBasicAuth,
@@ -303,10 +342,8 @@ func NewClient(redfishURL string,
 	defaultTransportCopy := http.DefaultTransport.(*http.Transport) //nolint:errcheck
 	transport := defaultTransportCopy.Clone()
 
-	if insecure {
-		transport.TLSClientConfig = &tls.Config{
-			InsecureSkipVerify: true, //nolint:gosec
-		}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig.Clone()
 	}
 
 	if !useProxy {
@@ -317,6 +354,17 @@ func NewClient(redfishURL string,
 		Transport: transport,
 	}
 
+	var session *sessionTransport
+	if sessionAuth {
+		session = &sessionTransport{
+			base:     transport,
+			basePath: basePath,
+			username: username,
+			password: password,
+		}
+		cfg.HTTPClient.Transport = session
+	}
+
 	// Retrieve system ID from end of Redfish URL
 	systemID := GetResourceIDFromURL(redfishURL)
 	if len(systemID) == 0 {
@@ -324,11 +372,11 @@ func NewClient(redfishURL string,
 	}
 
 	c := &Client{
-		nodeID:              systemID,
-		RedfishAPI:          redfishClient.NewAPIClient(cfg).DefaultApi,
-		RedfishCFG:          cfg,
-		systemActionRetries: systemActionRetries,
-		systemRebootDelay:   systemRebootDelay,
+		nodeID:      systemID,
+		RedfishAPI:  redfishClient.NewAPIClient(cfg).DefaultApi,
+		RedfishCFG:  cfg,
+		retryPolicy: retryPolicy,
+		session:     session,
 
 		Sleep: func(d time.Duration) {
 			time.Sleep(d)
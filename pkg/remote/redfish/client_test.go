@@ -28,35 +28,40 @@ import (
 	redfishClient "opendev.org/airship/go-redfish/client"
 
 	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
 	testutil "opendev.org/airship/airshipctl/testutil/redfishutils/helpers"
 )
 
 const (
-	nodeID              = "System.Embedded.1"
-	isoPath             = "https://localhost:8080/debian.iso"
-	redfishURL          = "redfish+https://localhost:2224/Systems/System.Embedded.1"
-	systemActionRetries = 1
-	systemRebootDelay   = 0
+	nodeID     = "System.Embedded.1"
+	isoPath    = "https://localhost:8080/debian.iso"
+	redfishURL = "redfish+https://localhost:2224/Systems/System.Embedded.1"
+)
+
+var (
+	// retryPolicy polls without a timeout, so that mocked requests receive the context passed to the client
+	retryPolicy = retry.Policy{}
+	// timeoutPolicy gives up polling shortly
+	timeoutPolicy = retry.Policy{Timeout: 10 * time.Millisecond, Interval: time.Millisecond}
 )
 
 func TestNewClient(t *testing.T) {
-	_, _, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, _, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 }
 
 func TestNewClientDefaultValues(t *testing.T) {
-	sysActRetr := 111
-	sysRebDel := 999
-	_, c, err := NewClient(redfishURL, false, false, "", "", sysActRetr, sysRebDel)
-	assert.Equal(t, c.systemActionRetries, sysActRetr)
-	assert.Equal(t, c.systemRebootDelay, sysRebDel)
+	policy := retry.Policy{Timeout: 111 * time.Second, Interval: 9 * time.Second, Multiplier: 2, Jitter: 0.1}
+	_, c, err := NewClient(redfishURL, nil, false, false, "", "", policy)
+	assert.Equal(t, c.retryPolicy, policy)
+	assert.Equal(t, c.RetryPolicy(), policy)
 	assert.NoError(t, err)
 }
 
 func TestNewClientMissingSystemID(t *testing.T) {
 	badURL := "redfish+https://localhost:2224"
 
-	_, _, err := NewClient(badURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, _, err := NewClient(badURL, nil, false, false, "", "", retryPolicy)
 	_, ok := err.(ErrRedfishMissingConfig)
 	assert.True(t, ok)
 }
@@ -64,12 +69,31 @@ func TestNewClientMissingSystemID(t *testing.T) {
 func TestNewClientNoRedfishMarking(t *testing.T) {
 	url := "https://localhost:2224/Systems/System.Embedded.1"
 
-	_, _, err := NewClient(url, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, _, err := NewClient(url, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 }
 
+func TestNewClientBasePath(t *testing.T) {
+	tests := []struct {
+		url      string
+		basePath string
+	}{
+		{url: "redfish+http://10.23.25.1:8000/redfish/v1/Systems/1", basePath: "http://10.23.25.1:8000"},
+		{url: "redfish://10.23.25.1/redfish/v1/Systems/1", basePath: "https://10.23.25.1"},
+		{url: "idrac-virtualmedia://10.23.25.1/redfish/v1/Systems/1", basePath: "https://10.23.25.1"},
+		{url: "ilo5-redfish+http://10.23.25.1/redfish/v1/Systems/1", basePath: "http://10.23.25.1"},
+		{url: "https://10.23.25.1/redfish/v1/Systems/1", basePath: "https://10.23.25.1"},
+	}
+
+	for _, tt := range tests {
+		_, c, err := NewClient(tt.url, nil, false, false, "", "", retryPolicy)
+		require.NoError(t, err)
+		assert.Equal(t, tt.basePath, c.RedfishCFG.BasePath, tt.url)
+	}
+}
+
 func TestNewClientAuth(t *testing.T) {
-	ctx, _, err := NewClient(redfishURL, false, false, "username", "password", systemActionRetries, systemRebootDelay)
+	ctx, _, err := NewClient(redfishURL, nil, false, false, "username", "password", retryPolicy)
 	assert.NoError(t, err)
 
 	cAuth := ctx.Value(redfishClient.ContextBasicAuth)
@@ -79,14 +103,14 @@ func TestNewClientAuth(t *testing.T) {
 
 func TestNewClientEmptyRedfishURL(t *testing.T) {
 	// Redfish URL cannot be empty when creating a client.
-	_, _, err := NewClient("", false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, _, err := NewClient("", nil, false, false, "", "", retryPolicy)
 	assert.Error(t, err)
 }
 func TestEjectVirtualMedia(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries+1, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -137,11 +161,11 @@ func TestEjectVirtualMedia(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestEjectVirtualMediaRetriesExceeded(t *testing.T) {
+func TestEjectVirtualMediaTimeout(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", timeoutPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -164,8 +188,8 @@ func TestEjectVirtualMediaRetriesExceeded(t *testing.T) {
 	m.On("EjectVirtualMedia", ctx, testutil.ManagerID, "Cd", mock.Anything).Times(1).
 		Return(redfishClient.RedfishError{}, httpResp, nil)
 
-	// Media still inserted on retry. Since retries are 1, this causes failure.
-	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Cd").Times(1).
+	// Media remains inserted until the timeout expires. Polls carry the deadline of the retry policy.
+	m.On("GetManagerVirtualMedia", mock.Anything, testutil.ManagerID, "Cd").
 		Return(testMedia, httpResp, nil)
 
 	// Replace normal API client with mocked API client
@@ -174,14 +198,13 @@ func TestEjectVirtualMediaRetriesExceeded(t *testing.T) {
 	client.Sleep = func(_ time.Duration) {}
 
 	err = client.EjectVirtualMedia(ctx)
-	_, ok := err.(ErrOperationRetriesExceeded)
-	assert.True(t, ok)
+	assert.Equal(t, retry.ErrTimeout{What: "eject media Cd", Timeout: timeoutPolicy.Timeout}, err)
 }
 func TestRebootSystem(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -215,7 +238,7 @@ func TestRebootSystemShutdownError(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -241,7 +264,7 @@ func TestRebootSystemStartupError(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -278,7 +301,7 @@ func TestRebootSystemTimeout(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", timeoutPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -291,7 +314,7 @@ func TestRebootSystemTimeout(t *testing.T) {
 		Times(1).
 		Return(redfishClient.RedfishError{}, &http.Response{StatusCode: 200}, nil)
 
-	m.On("GetSystem", ctx, client.nodeID).
+	m.On("GetSystem", mock.Anything, client.nodeID).
 		Return(redfishClient.ComputerSystem{}, &http.Response{StatusCode: 200}, nil)
 
 	// Replace normal API client with mocked API client
@@ -300,14 +323,14 @@ func TestRebootSystemTimeout(t *testing.T) {
 	client.Sleep = func(_ time.Duration) {}
 
 	err = client.RebootSystem(ctx)
-	assert.Error(t, err)
+	assert.IsType(t, retry.ErrTimeout{}, err)
 }
 
 func TestSetBootSourceByTypeGetSystemError(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -329,7 +352,7 @@ func TestSetBootSourceByTypeSetSystemError(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -356,7 +379,7 @@ func TestSetBootSourceByTypeBootSourceUnavailable(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -388,7 +411,7 @@ func TestSetVirtualMediaEjectExistingMedia(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -434,7 +457,7 @@ func TestSetVirtualMediaEjectExistingMediaFailure(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", timeoutPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -456,7 +479,7 @@ func TestSetVirtualMediaEjectExistingMediaFailure(t *testing.T) {
 		Return(testMedia, httpResp, nil)
 	m.On("EjectVirtualMedia", ctx, testutil.ManagerID, "Cd", mock.Anything).Times(1).
 		Return(redfishClient.RedfishError{}, httpResp, nil)
-	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Cd").Times(1).
+	m.On("GetManagerVirtualMedia", mock.Anything, testutil.ManagerID, "Cd").
 		Return(testMedia, httpResp, nil)
 
 	// Replace normal API client with mocked API client
@@ -471,7 +494,7 @@ func TestSetVirtualMediaGetSystemError(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -493,7 +516,7 @@ func TestSetVirtualMediaInsertVirtualMediaError(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -523,11 +546,164 @@ func TestSetVirtualMediaInsertVirtualMediaError(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestInsertVirtualMedia(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	httpResp := &http.Response{StatusCode: 200}
+	m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), httpResp, nil)
+	m.On("ListManagerVirtualMedia", ctx, testutil.ManagerID).Times(1).
+		Return(testutil.GetMediaCollection([]string{"Floppy", "Cd"}), httpResp, nil)
+	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Floppy").Times(1).
+		Return(testutil.GetVirtualMedia([]string{"Floppy"}), httpResp, nil)
+	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Cd").Times(1).
+		Return(testutil.GetVirtualMedia([]string{"CD"}), httpResp, nil)
+	m.On("InsertVirtualMedia", ctx, testutil.ManagerID, "Cd",
+		redfishClient.InsertMediaRequestBody{Image: isoPath, Inserted: true}).Times(1).
+		Return(redfishClient.RedfishError{}, httpResp, nil)
+
+	client.RedfishAPI = m
+
+	mediaID, err := client.InsertVirtualMedia(ctx, isoPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "Cd", mediaID)
+}
+
+func TestInsertVirtualMediaAlreadyInserted(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	inserted := true
+	testMedia := testutil.GetVirtualMedia([]string{"CD"})
+	testMedia.Inserted = &inserted
+	testMedia.Image = "https://localhost:8080/ubuntu.iso"
+
+	ctx := context.Background()
+	httpResp := &http.Response{StatusCode: 200}
+	m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), httpResp, nil)
+	m.On("ListManagerVirtualMedia", ctx, testutil.ManagerID).Times(1).
+		Return(testutil.GetMediaCollection([]string{"Cd"}), httpResp, nil)
+	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Cd").Times(1).Return(testMedia, httpResp, nil)
+
+	client.RedfishAPI = m
+
+	mediaID, err := client.InsertVirtualMedia(ctx, isoPath)
+	_, ok := err.(ErrRedfishClient)
+	assert.True(t, ok)
+	assert.Contains(t, err.Error(), "already has media 'https://localhost:8080/ubuntu.iso' inserted")
+	assert.Empty(t, mediaID)
+}
+
+func TestSetVirtualMediaEjectExistingMediaDryRun(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	client.nodeID = nodeID
+	client.EnableDryRun()
+
+	ctx := context.Background()
+
+	// The media stays inserted, since the eject request is not sent in dry run
+	inserted := true
+	testMedia := testutil.GetVirtualMedia([]string{"CD"})
+	testMedia.Inserted = &inserted
+
+	httpResp := &http.Response{StatusCode: 200}
+	m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), httpResp, nil)
+	m.On("ListManagerVirtualMedia", ctx, testutil.ManagerID).Times(2).
+		Return(testutil.GetMediaCollection([]string{"Cd"}), httpResp, nil)
+	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Cd").Times(2).Return(testMedia, httpResp, nil)
+	m.On("EjectVirtualMedia", ctx, testutil.ManagerID, "Cd", mock.Anything).Times(1).
+		Return(redfishClient.RedfishError{}, httpResp, nil)
+	m.On("InsertVirtualMedia", ctx, testutil.ManagerID, "Cd", mock.Anything).Times(1).
+		Return(redfishClient.RedfishError{}, httpResp, nil)
+
+	client.RedfishAPI = m
+
+	assert.NoError(t, client.SetVirtualMedia(ctx, isoPath))
+}
+
+func TestSupportsVirtualMedia(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	httpResp := &http.Response{StatusCode: 200}
+	m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), httpResp, nil)
+	m.On("ListManagerVirtualMedia", ctx, testutil.ManagerID).Times(1).
+		Return(testutil.GetMediaCollection([]string{"Floppy", "Cd"}), httpResp, nil)
+	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Floppy").Times(1).
+		Return(testutil.GetVirtualMedia([]string{"Floppy"}), httpResp, nil)
+	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Cd").Times(1).
+		Return(testutil.GetVirtualMedia([]string{"CD"}), httpResp, nil)
+
+	client.RedfishAPI = m
+
+	supported, err := client.SupportsVirtualMedia(ctx)
+	assert.NoError(t, err)
+	assert.True(t, supported)
+}
+
+func TestSupportsVirtualMediaNoCompatibleMedia(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	httpResp := &http.Response{StatusCode: 200}
+	m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), httpResp, nil)
+	m.On("ListManagerVirtualMedia", ctx, testutil.ManagerID).Times(1).
+		Return(testutil.GetMediaCollection([]string{"Floppy"}), httpResp, nil)
+	m.On("GetManagerVirtualMedia", ctx, testutil.ManagerID, "Floppy").Times(1).
+		Return(testutil.GetVirtualMedia([]string{"Floppy", "USBStick"}), httpResp, nil)
+
+	client.RedfishAPI = m
+
+	supported, err := client.SupportsVirtualMedia(ctx)
+	assert.NoError(t, err)
+	assert.False(t, supported)
+}
+
+func TestSupportsVirtualMediaListError(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	m.On("GetSystem", ctx, client.nodeID).Return(testutil.GetTestSystem(), &http.Response{StatusCode: 200}, nil)
+	m.On("ListManagerVirtualMedia", ctx, testutil.ManagerID).Times(1).
+		Return(redfishClient.Collection{}, &http.Response{StatusCode: 500}, redfishClient.GenericOpenAPIError{})
+
+	client.RedfishAPI = m
+
+	supported, err := client.SupportsVirtualMedia(ctx)
+	_, ok := err.(ErrRedfishClient)
+	assert.True(t, ok)
+	assert.False(t, supported)
+}
+
 func TestSystemPowerOff(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	require.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -560,7 +736,7 @@ func TestSystemPowerOffResetSystemError(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	require.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -585,7 +761,7 @@ func TestSystemPowerOn(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	require.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -618,7 +794,7 @@ func TestSystemPowerOnResetSystemError(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	require.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -643,7 +819,7 @@ func TestSystemPowerStatusUnknown(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	require.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -666,7 +842,7 @@ func TestSystemPowerStatusOn(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	require.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -688,7 +864,7 @@ func TestSystemPowerStatusOff(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	require.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -710,7 +886,7 @@ func TestSystemPowerStatusPoweringOn(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	require.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -732,7 +908,7 @@ func TestSystemPowerStatusPoweringOff(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	require.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -754,7 +930,7 @@ func TestSystemPowerStatusGetSystemError(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	require.NoError(t, err)
 
 	client.nodeID = nodeID
@@ -774,7 +950,7 @@ func TestWaitForPowerStateGetSystemFailed(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	ctx := context.Background()
@@ -798,7 +974,7 @@ func TestWaitForPowerStateNoRetries(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	ctx := context.Background()
@@ -824,7 +1000,7 @@ func TestWaitForPowerStateWithRetries(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	ctx := context.Background()
@@ -851,42 +1027,70 @@ func TestWaitForPowerStateWithRetries(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestWaitForPowerStateRetriesExceeded(t *testing.T) {
+func TestWaitForPowerStateTimeout(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", timeoutPolicy)
 	assert.NoError(t, err)
 
 	ctx := context.Background()
 	resetReq := redfishClient.ResetRequestBody{}
 	resetReq.ResetType = redfishClient.RESETTYPE_FORCE_OFF
 
-	m.On("GetSystem", ctx, client.nodeID).Return(
+	m.On("GetSystem", mock.Anything, client.nodeID).Return(
 		redfishClient.ComputerSystem{
 			PowerState: redfishClient.POWERSTATE_ON,
 		}, &http.Response{StatusCode: 200}, nil)
 
+	// Replace normal API client with mocked API client
+	client.RedfishAPI = m
+
+	// Mock out the Sleep function so we don't have to wait on it
+	client.Sleep = func(_ time.Duration) {}
+
+	err = client.waitForPowerState(ctx, redfishClient.POWERSTATE_OFF)
+	expected := retry.ErrTimeout{What: "reach desired power state Off", Timeout: timeoutPolicy.Timeout}
+	assert.Equal(t, expected, err)
+}
+
+func TestWaitForPowerStateBackoff(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	policy := retry.Policy{Interval: time.Second, MaxInterval: 3 * time.Second, Multiplier: 2}
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", policy)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+
 	m.On("GetSystem", ctx, client.nodeID).Return(
 		redfishClient.ComputerSystem{
 			PowerState: redfishClient.POWERSTATE_ON,
-		}, &http.Response{StatusCode: 200}, nil)
+		}, &http.Response{StatusCode: 200}, nil).Times(3)
+
+	m.On("GetSystem", ctx, client.nodeID).Return(
+		redfishClient.ComputerSystem{
+			PowerState: redfishClient.POWERSTATE_OFF,
+		}, &http.Response{StatusCode: 200}, nil).Times(1)
 
 	// Replace normal API client with mocked API client
 	client.RedfishAPI = m
 
-	// Mock out the Sleep function so we don't have to wait on it
-	client.Sleep = func(_ time.Duration) {}
+	// Record the time waited between polls
+	var waits []time.Duration
+	client.Sleep = func(d time.Duration) { waits = append(waits, d) }
 
 	err = client.waitForPowerState(ctx, redfishClient.POWERSTATE_OFF)
-	assert.Error(t, err)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, waits)
 }
 
 func TestWaitForPowerStateDifferentPowerState(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	_, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	ctx := context.Background()
@@ -0,0 +1,255 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
+)
+
+// maskedValue replaces the values of secrets in the bodies of recorded calls.
+const maskedValue = "********"
+
+// secretKeys holds substrings of the lower-cased names of JSON properties whose values are secrets, e.g. the
+// Password property of an InsertMedia action or a BIOS password attribute.
+var secretKeys = []string{"password", "passphrase", "secret", "token"}
+
+// Call is a request made by a client in dry run. Calls with a Pending status change the state of the host and were
+// not sent to its BMC; other calls only retrieve information and were sent.
+type Call struct {
+	Method  string `json:"method"`
+	URL     string `json:"url"`
+	Body    string `json:"body,omitempty"`
+	Pending bool   `json:"pending"`
+}
+
+// dryRunTransport is an HTTP transport that records every request made through it. Requests that only retrieve
+// information are sent to the BMC so that the client can locate the resources it acts on; any other request is
+// answered with an empty JSON object instead of being sent.
+type dryRunTransport struct {
+	base http.RoundTripper
+
+	mu    sync.Mutex
+	calls []Call
+
+	// ejected holds the virtual media devices, identified by manager and media ID, the client ejected media from.
+	ejected map[string]bool
+}
+
+// RoundTrip records a request, sending it to the BMC only when it does not change the state of the host.
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	call := Call{Method: req.Method, URL: maskURL(req.URL)}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		call.Pending = true
+	}
+
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		call.Body = maskBody(body)
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	t.mu.Lock()
+	t.calls = append(t.calls, call)
+	t.mu.Unlock()
+
+	if !call.Pending {
+		return t.base.RoundTrip(req)
+	}
+
+	log.Debugf("Dry run: not sending %s request to '%s'.", call.Method, call.URL)
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(strings.NewReader("{}")),
+		ContentLength: 2,
+		Request:       req,
+	}, nil
+}
+
+// takeCalls returns the requests recorded since it was last called, in the order they were made.
+func (t *dryRunTransport) takeCalls() []Call {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	calls := t.calls
+	t.calls = nil
+
+	return calls
+}
+
+// EnableDryRun makes the client record every request it makes, without sending requests that change the state of the
+// host to its BMC. Operations are not waited for in dry run, since the requests that would start them are not sent.
+func (c *Client) EnableDryRun() {
+	if c.dryRun != nil {
+		return
+	}
+
+	// With session authentication the dry run transport is placed below the session transport, so that the request
+	// creating the session is recorded rather than sent.
+	if c.session != nil {
+		c.dryRun = &dryRunTransport{base: c.session.base}
+		c.session.base = c.dryRun
+		c.session.dryRun = true
+
+		return
+	}
+
+	c.dryRun = &dryRunTransport{base: c.RedfishCFG.HTTPClient.Transport}
+	if c.dryRun.base == nil {
+		c.dryRun.base = http.DefaultTransport
+	}
+
+	c.RedfishCFG.HTTPClient.Transport = c.dryRun
+}
+
+// DryRun reports whether the client is in dry run.
+func (c *Client) DryRun() bool {
+	return c.dryRun != nil
+}
+
+// DryRunCalls returns the requests made by the client in dry run since DryRunCalls was last called, in the order they
+// were made. No calls are returned when dry run is not enabled.
+func (c *Client) DryRunCalls() []Call {
+	if c.dryRun == nil {
+		return nil
+	}
+
+	return c.dryRun.takeCalls()
+}
+
+// recordEject notes that the client ejected the media of a virtual media device in dry run. The eject request is not
+// sent, so the BMC still reports the media as inserted.
+func (c *Client) recordEject(managerID, mediaID string) {
+	if c.dryRun == nil {
+		return
+	}
+
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+
+	if c.dryRun.ejected == nil {
+		c.dryRun.ejected = make(map[string]bool)
+	}
+	c.dryRun.ejected[managerID+"/"+mediaID] = true
+}
+
+// ejectedInDryRun reports whether the client ejected the media of a virtual media device in dry run, in which case
+// the device would hold no media had the eject request been sent.
+func (c *Client) ejectedInDryRun(managerID, mediaID string) bool {
+	if c.dryRun == nil {
+		return false
+	}
+
+	c.dryRun.mu.Lock()
+	defer c.dryRun.mu.Unlock()
+
+	return c.dryRun.ejected[managerID+"/"+mediaID]
+}
+
+// poll waits for an operation to complete as described by policy, unless the client is in dry run.
+func (c *Client) poll(ctx context.Context, policy retry.Policy, what string,
+	condition func(ctx context.Context) (bool, error)) error {
+	if c.dryRun != nil {
+		log.Debugf("Dry run: not waiting to %s.", what)
+		return nil
+	}
+
+	return policy.Poll(ctx, what, c.Sleep, condition)
+}
+
+// maskURL returns a URL with the password of its user information, if any, masked.
+func maskURL(u *url.URL) string {
+	if u.User == nil {
+		return u.String()
+	}
+
+	masked := *u
+	if _, ok := u.User.Password(); ok {
+		masked.User = url.UserPassword(u.User.Username(), maskedValue)
+	}
+
+	return masked.String()
+}
+
+// maskBody returns the body of a request with the values of the secrets in it masked. Bodies that are not JSON are
+// masked entirely, since secrets cannot be located in them.
+func maskBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return maskedValue
+	}
+
+	masked, err := json.Marshal(maskSecrets(v))
+	if err != nil {
+		return maskedValue
+	}
+
+	return string(masked)
+}
+
+// maskSecrets replaces the values of the properties of a decoded JSON value whose names denote secrets.
+func maskSecrets(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if isSecretKey(key) {
+				value[key] = maskedValue
+			} else {
+				value[key] = maskSecrets(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = maskSecrets(item)
+		}
+	}
+
+	return v
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+
+	return false
+}
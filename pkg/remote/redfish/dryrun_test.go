@@ -0,0 +1,95 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	redfishClient "opendev.org/airship/go-redfish/client"
+)
+
+func TestDryRunUpdateFirmware(t *testing.T) {
+	client, update, closeServer := newFirmwareClient(t, firmwareResources)
+	defer closeServer()
+
+	assert.False(t, client.DryRun())
+	client.EnableDryRun()
+	assert.True(t, client.DryRun())
+
+	err := client.UpdateFirmware(context.Background(), "http://images.example.com/bios.exe", []string{"BIOS"})
+	require.NoError(t, err)
+
+	// The update is not sent to the BMC
+	assert.Empty(t, update.updates)
+
+	basePath := client.RedfishCFG.BasePath
+	expected := []Call{
+		{Method: http.MethodGet, URL: basePath + "/redfish/v1/UpdateService"},
+		{
+			Method: http.MethodPost,
+			URL:    basePath + "/redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate",
+			Body: `{"ImageURI":"http://images.example.com/bios.exe",` +
+				`"Targets":["/redfish/v1/UpdateService/FirmwareInventory/BIOS"]}`,
+			Pending: true,
+		},
+	}
+	assert.Equal(t, expected, client.DryRunCalls())
+
+	// Calls are only returned once
+	assert.Empty(t, client.DryRunCalls())
+}
+
+func TestDryRunMasksSecrets(t *testing.T) {
+	client, _, closeServer := newFirmwareClient(t, firmwareResources)
+	defer closeServer()
+
+	client.EnableDryRun()
+
+	body := []byte(`{"Image":"http://images.example.com/ephemeral.iso","UserName":"admin","Password":"secret",` +
+		`"Attributes":{"SysPassword":"bios","BootMode":"Uefi"}}`)
+	_, _, err := client.rawRequestWithBody(context.Background(), http.MethodPatch, "/redfish/v1/Systems/1", body)
+	require.NoError(t, err)
+
+	calls := client.DryRunCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, `{"Attributes":{"BootMode":"Uefi","SysPassword":"********"},`+
+		`"Image":"http://images.example.com/ephemeral.iso","Password":"********","UserName":"admin"}`, calls[0].Body)
+	assert.NotContains(t, calls[0].Body, "secret")
+}
+
+func TestDryRunSkipsPolling(t *testing.T) {
+	resources := map[string]string{"/redfish/v1/Systems/1": `{"PowerState": "On"}`}
+	client, _, closeServer := newFirmwareClient(t, resources)
+	defer closeServer()
+
+	client.EnableDryRun()
+
+	// The host never reaches the desired power state, since the request that would change it is not sent
+	err := client.waitForSystemPowerState(context.Background(), redfishClient.POWERSTATE_OFF, retryPolicy)
+	require.NoError(t, err)
+	assert.Empty(t, client.DryRunCalls())
+}
+
+func TestDryRunCallsNotEnabled(t *testing.T) {
+	client, _, closeServer := newFirmwareClient(t, firmwareResources)
+	defer closeServer()
+
+	_, err := client.FirmwareInventory(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, client.DryRunCalls())
+}
@@ -0,0 +1,204 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package emulator serves Redfish endpoints that emulate the BMCs of baremetal hosts with in-memory state, so that
+// out-of-band management can be exercised without lab hardware.
+package emulator
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+const systemsPath = "/redfish/v1/Systems/"
+
+// Host describes a baremetal host whose BMC is emulated.
+type Host struct {
+	// Name is the name of the baremetal host document of the host.
+	Name string
+	// Address is the BMC address of the host, e.g. redfish+http://127.0.0.1:8000/redfish/v1/Systems/node-1.
+	Address string
+	// Username and Password are the credentials the emulated BMC accepts.
+	Username string
+	Password string
+}
+
+// Emulator emulates the BMCs of a set of hosts. Hosts whose BMC addresses share a host and port are served by the
+// same endpoint, each as a distinct system.
+type Emulator struct {
+	endpoints []*endpoint
+	hostNames []string
+	addresses map[string]string
+}
+
+// NewEmulator starts emulating the BMCs of hosts. Each endpoint listens on the host and port of the BMC addresses it
+// serves, or on bindAddress with the port of those BMC addresses when bindAddress is set. HTTPS endpoints present a
+// self-signed certificate, so clients must be configured to skip certificate verification.
+func NewEmulator(bindAddress string, hosts ...Host) (*Emulator, error) {
+	e := &Emulator{addresses: make(map[string]string)}
+
+	endpoints := make(map[string]*endpoint)
+	for _, host := range hosts {
+		bmcURL, secure, systemID, err := parseAddress(host)
+		if err != nil {
+			return nil, err
+		}
+
+		ep, ok := endpoints[bmcURL.Host]
+		switch {
+		case !ok:
+			listenHost := bmcURL.Hostname()
+			if bindAddress != "" {
+				listenHost = bindAddress
+			}
+
+			ep = newEndpoint(net.JoinHostPort(listenHost, bmcURL.Port()), bmcURL.Hostname(), secure)
+			endpoints[bmcURL.Host] = ep
+			e.endpoints = append(e.endpoints, ep)
+		case ep.secure != secure:
+			return nil, ErrMixedSchemes{HostName: host.Name, Address: host.Address}
+		case ep.systems[systemID] != nil:
+			return nil, ErrDuplicateSystem{HostName: host.Name, Address: host.Address}
+		}
+
+		ep.systems[systemID] = newSystem(systemID, host)
+	}
+
+	for _, ep := range e.endpoints {
+		if err := ep.start(); err != nil {
+			e.Close() //nolint:errcheck
+			return nil, err
+		}
+	}
+
+	// The emulated addresses carry the ports the endpoints listen on, which differ from the BMC addresses of the
+	// hosts when those request a random port
+	for _, host := range hosts {
+		bmcURL, _, systemID, err := parseAddress(host)
+		if err != nil {
+			return nil, err
+		}
+
+		ep := endpoints[bmcURL.Host]
+		bmcURL.Host = net.JoinHostPort(bmcURL.Hostname(), strconv.Itoa(ep.port()))
+		e.hostNames = append(e.hostNames, host.Name)
+		e.addresses[host.Name] = bmcURL.String()
+
+		log.Debugf("Emulating the BMC of host '%s' at '%s' as system '%s', served on '%s'.", host.Name,
+			e.addresses[host.Name], systemID, ep.listener.Addr())
+	}
+
+	return e, nil
+}
+
+// Address returns the BMC address at which the BMC of a host is emulated, or an empty string when the host is not
+// emulated.
+func (e *Emulator) Address(hostName string) string {
+	return e.addresses[hostName]
+}
+
+// HostNames returns the names of the hosts whose BMCs are emulated, in the order they were supplied.
+func (e *Emulator) HostNames() []string {
+	return append([]string{}, e.hostNames...)
+}
+
+// Close stops all endpoints of the emulator.
+func (e *Emulator) Close() error {
+	var closeErr error
+	for _, ep := range e.endpoints {
+		if err := ep.stop(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+
+	return closeErr
+}
+
+// parseAddress validates the BMC address of a host and returns it as a URL with an explicit port, along with whether
+// it uses HTTPS and the ID of the system it points to.
+func parseAddress(host Host) (*url.URL, bool, string, error) {
+	bmcURL, err := url.Parse(host.Address)
+	if err != nil || bmcURL.Hostname() == "" {
+		return nil, false, "", ErrUnsupportedAddress{HostName: host.Name, Address: host.Address}
+	}
+
+	var secure bool
+	var port string
+	switch bmcURL.Scheme {
+	case "redfish", "redfish+https", "https":
+		secure = true
+		port = "443"
+	case "redfish+http", "http":
+		port = "80"
+	default:
+		return nil, false, "", ErrUnsupportedAddress{HostName: host.Name, Address: host.Address}
+	}
+
+	systemID := strings.TrimSuffix(strings.TrimPrefix(bmcURL.Path, systemsPath), "/")
+	if !strings.HasPrefix(bmcURL.Path, systemsPath) || systemID == "" || strings.Contains(systemID, "/") {
+		return nil, false, "", ErrUnsupportedAddress{HostName: host.Name, Address: host.Address}
+	}
+
+	if bmcURL.Port() != "" {
+		port = bmcURL.Port()
+	}
+	bmcURL.Host = net.JoinHostPort(bmcURL.Hostname(), port)
+
+	return bmcURL, secure, systemID, nil
+}
+
+// selfSignedCertificate generates a certificate for hostname that is valid for a year.
+func selfSignedCertificate(hostname string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"airshipctl BMC emulator"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	if ip := net.ParseIP(hostname); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	} else {
+		template.DNSNames = []string{hostname}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
@@ -0,0 +1,331 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish/emulator"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
+)
+
+const (
+	username = "admin"
+	password = "password"
+)
+
+var testPolicy = retry.Policy{Timeout: 5 * time.Second, Interval: time.Millisecond}
+
+// newTestEmulator emulates the BMCs of node-1 and node-2 on a single random port, and that of node-3, whose
+// credentials differ, on another.
+func newTestEmulator(t *testing.T) *emulator.Emulator {
+	t.Helper()
+
+	e, err := emulator.NewEmulator("",
+		emulator.Host{Name: "node-1", Address: "redfish+http://127.0.0.1:0/redfish/v1/Systems/node-1",
+			Username: username, Password: password},
+		emulator.Host{Name: "node-2", Address: "redfish+http://127.0.0.1:0/redfish/v1/Systems/node-2",
+			Username: username, Password: password},
+		emulator.Host{Name: "node-3", Address: "http://localhost:0/redfish/v1/Systems/node-3",
+			Username: "root", Password: "calvin"},
+	)
+	require.NoError(t, err)
+
+	return e
+}
+
+// request sends a request to the emulated BMC located at bmcAddress and decodes the JSON response into v, if set.
+func request(t *testing.T, method, bmcAddress, uri string, auth bool, body interface{},
+	v interface{}) *http.Response {
+	t.Helper()
+
+	target, err := url.Parse(bmcAddress)
+	require.NoError(t, err)
+
+	target.Scheme = "http"
+	target.Path = uri
+
+	var reqBody []byte
+	if body != nil {
+		reqBody, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	req, err := http.NewRequest(method, target.String(), bytes.NewReader(reqBody))
+	require.NoError(t, err)
+
+	if auth {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	if v != nil {
+		require.NoError(t, json.Unmarshal(respBody, v))
+	}
+
+	return resp
+}
+
+func TestNewEmulatorAddresses(t *testing.T) {
+	e := newTestEmulator(t)
+	defer e.Close()
+
+	node1, err := url.Parse(e.Address("node-1"))
+	require.NoError(t, err)
+	node2, err := url.Parse(e.Address("node-2"))
+	require.NoError(t, err)
+	node3, err := url.Parse(e.Address("node-3"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "redfish+http", node1.Scheme)
+	assert.Equal(t, "/redfish/v1/Systems/node-1", node1.Path)
+	assert.NotEqual(t, "0", node1.Port())
+	assert.Equal(t, node1.Host, node2.Host)
+	assert.NotEqual(t, node1.Host, node3.Host)
+	assert.Empty(t, e.Address("node-4"))
+	assert.Equal(t, []string{"node-1", "node-2", "node-3"}, e.HostNames())
+}
+
+func TestNewEmulatorErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		hosts       []emulator.Host
+		expectedErr error
+	}{
+		{
+			name:        "IPMI",
+			hosts:       []emulator.Host{{Name: "node-1", Address: "ipmi://127.0.0.1:0"}},
+			expectedErr: emulator.ErrUnsupportedAddress{HostName: "node-1", Address: "ipmi://127.0.0.1:0"},
+		},
+		{
+			name:  "NoSystem",
+			hosts: []emulator.Host{{Name: "node-1", Address: "redfish+http://127.0.0.1:0/redfish/v1/Systems/"}},
+			expectedErr: emulator.ErrUnsupportedAddress{HostName: "node-1",
+				Address: "redfish+http://127.0.0.1:0/redfish/v1/Systems/"},
+		},
+		{
+			name: "DuplicateSystem",
+			hosts: []emulator.Host{
+				{Name: "node-1", Address: "redfish+http://127.0.0.1:0/redfish/v1/Systems/node-1"},
+				{Name: "node-2", Address: "redfish+http://127.0.0.1:0/redfish/v1/Systems/node-1"},
+			},
+			expectedErr: emulator.ErrDuplicateSystem{HostName: "node-2",
+				Address: "redfish+http://127.0.0.1:0/redfish/v1/Systems/node-1"},
+		},
+		{
+			name: "MixedSchemes",
+			hosts: []emulator.Host{
+				{Name: "node-1", Address: "redfish+http://127.0.0.1:0/redfish/v1/Systems/node-1"},
+				{Name: "node-2", Address: "redfish+https://127.0.0.1:0/redfish/v1/Systems/node-2"},
+			},
+			expectedErr: emulator.ErrMixedSchemes{HostName: "node-2",
+				Address: "redfish+https://127.0.0.1:0/redfish/v1/Systems/node-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := emulator.NewEmulator("", tt.hosts...)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestEmulatorPower(t *testing.T) {
+	e := newTestEmulator(t)
+	defer e.Close()
+
+	// The emulated systems are driven by the Redfish client, authenticating with a session
+	_, client, err := redfish.NewClient(e.Address("node-1"), nil, false, true, username, password, testPolicy)
+	require.NoError(t, err)
+	defer client.Close()
+
+	var system struct {
+		PowerState string `json:"PowerState"`
+	}
+	request(t, http.MethodGet, e.Address("node-1"), "/redfish/v1/Systems/node-1", true, nil, &system)
+	assert.Equal(t, "Off", system.PowerState)
+
+	require.NoError(t, client.RebootSystemGraceful(context.Background(), time.Second))
+	request(t, http.MethodGet, e.Address("node-1"), "/redfish/v1/Systems/node-1", true, nil, &system)
+	assert.Equal(t, "On", system.PowerState)
+
+	// The state of each system is independent
+	request(t, http.MethodGet, e.Address("node-2"), "/redfish/v1/Systems/node-2", true, nil, &system)
+	assert.Equal(t, "Off", system.PowerState)
+
+	require.NoError(t, client.SystemPowerOffGraceful(context.Background(), time.Second))
+	request(t, http.MethodGet, e.Address("node-1"), "/redfish/v1/Systems/node-1", true, nil, &system)
+	assert.Equal(t, "Off", system.PowerState)
+
+	resp := request(t, http.MethodPost, e.Address("node-1"),
+		"/redfish/v1/Systems/node-1/Actions/ComputerSystem.Reset", true, map[string]string{"ResetType": "Explode"}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestEmulatorBootOverride(t *testing.T) {
+	e := newTestEmulator(t)
+	defer e.Close()
+
+	var system struct {
+		Boot struct {
+			BootSourceOverrideEnabled string `json:"BootSourceOverrideEnabled"`
+			BootSourceOverrideTarget  string `json:"BootSourceOverrideTarget"`
+		} `json:"Boot"`
+	}
+	address := e.Address("node-1")
+
+	resp := request(t, http.MethodPatch, address, "/redfish/v1/Systems/node-1", true,
+		map[string]interface{}{"Boot": map[string]string{"BootSourceOverrideTarget": "Cd"}}, &system)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Cd", system.Boot.BootSourceOverrideTarget)
+	assert.Equal(t, "Once", system.Boot.BootSourceOverrideEnabled)
+
+	// A boot override that applies to a single boot is consumed when the system boots
+	resp = request(t, http.MethodPost, address, "/redfish/v1/Systems/node-1/Actions/ComputerSystem.Reset", true,
+		map[string]string{"ResetType": "On"}, nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	request(t, http.MethodGet, address, "/redfish/v1/Systems/node-1", true, nil, &system)
+	assert.Equal(t, "Disabled", system.Boot.BootSourceOverrideEnabled)
+
+	resp = request(t, http.MethodPatch, address, "/redfish/v1/Systems/node-1", true,
+		map[string]interface{}{"Boot": map[string]string{"BootSourceOverrideTarget": "Floppy"}}, nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestEmulatorVirtualMedia(t *testing.T) {
+	e := newTestEmulator(t)
+	defer e.Close()
+
+	address := e.Address("node-1")
+	mediaURI := "/redfish/v1/Managers/node-1/VirtualMedia/Cd"
+
+	var system struct {
+		Links struct {
+			ManagedBy []struct {
+				OdataID string `json:"@odata.id"`
+			} `json:"ManagedBy"`
+		} `json:"Links"`
+	}
+	request(t, http.MethodGet, address, "/redfish/v1/Systems/node-1", true, nil, &system)
+	require.Len(t, system.Links.ManagedBy, 1)
+	assert.Equal(t, "/redfish/v1/Managers/node-1", system.Links.ManagedBy[0].OdataID)
+
+	var collection struct {
+		Members []struct {
+			OdataID string `json:"@odata.id"`
+		} `json:"Members"`
+	}
+	request(t, http.MethodGet, address, "/redfish/v1/Managers/node-1/VirtualMedia", true, nil, &collection)
+	require.Len(t, collection.Members, 1)
+	assert.Equal(t, mediaURI, collection.Members[0].OdataID)
+
+	resp := request(t, http.MethodPost, address, mediaURI+"/Actions/VirtualMedia.InsertMedia", true,
+		map[string]interface{}{"Image": "http://localhost:8099/ephemeral.iso", "Inserted": true}, nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// Media must be ejected before other media is inserted
+	resp = request(t, http.MethodPost, address, mediaURI+"/Actions/VirtualMedia.InsertMedia", true,
+		map[string]interface{}{"Image": "http://localhost:8099/other.iso", "Inserted": true}, nil)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	var media struct {
+		Image      string   `json:"Image"`
+		Inserted   bool     `json:"Inserted"`
+		MediaTypes []string `json:"MediaTypes"`
+	}
+	request(t, http.MethodGet, address, mediaURI, true, nil, &media)
+	assert.Equal(t, "http://localhost:8099/ephemeral.iso", media.Image)
+	assert.True(t, media.Inserted)
+	assert.Equal(t, []string{"CD", "DVD"}, media.MediaTypes)
+
+	resp = request(t, http.MethodPost, address, mediaURI+"/Actions/VirtualMedia.EjectMedia", true,
+		map[string]interface{}{}, nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	request(t, http.MethodGet, address, mediaURI, true, nil, &media)
+	assert.Empty(t, media.Image)
+	assert.False(t, media.Inserted)
+}
+
+func TestEmulatorAuthentication(t *testing.T) {
+	e := newTestEmulator(t)
+	defer e.Close()
+
+	// The service root is served without authentication
+	resp := request(t, http.MethodGet, e.Address("node-1"), "/redfish/v1/", false, nil, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = request(t, http.MethodGet, e.Address("node-1"), "/redfish/v1/Systems/node-1", false, nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Systems are only visible to the credentials of their own host
+	resp = request(t, http.MethodGet, e.Address("node-3"), "/redfish/v1/Systems/node-3", true, nil, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	var collection struct {
+		Members []struct {
+			OdataID string `json:"@odata.id"`
+		} `json:"Members"`
+	}
+	request(t, http.MethodGet, e.Address("node-1"), "/redfish/v1/Systems", true, nil, &collection)
+	require.Len(t, collection.Members, 2)
+	assert.Equal(t, "/redfish/v1/Systems/node-1", collection.Members[0].OdataID)
+	assert.Equal(t, "/redfish/v1/Systems/node-2", collection.Members[1].OdataID)
+}
+
+func TestEmulatorHTTPS(t *testing.T) {
+	e, err := emulator.NewEmulator("", emulator.Host{Name: "node-1",
+		Address: "redfish+https://127.0.0.1:0/redfish/v1/Systems/node-1", Username: username, Password: password})
+	require.NoError(t, err)
+	defer e.Close()
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true} //nolint:gosec
+	ctx, client, err := redfish.NewClient(e.Address("node-1"), tlsConfig, false, false, username, password, testPolicy)
+	require.NoError(t, err)
+	require.NoError(t, client.SystemPowerOffGraceful(ctx, time.Second))
+
+	// Clients verifying the self-signed certificate of the emulator are rejected
+	target, err := url.Parse(e.Address("node-1"))
+	require.NoError(t, err)
+	target.Scheme = "https"
+
+	_, err = http.Get(target.String()) //nolint:bodyclose
+	assert.Error(t, err)
+
+	insecure := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	resp, err := insecure.Get(target.String())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,532 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+const (
+	serviceRootPath = "/redfish/v1"
+	managersPath    = "/redfish/v1/Managers/"
+	sessionsPath    = "/redfish/v1/SessionService/Sessions"
+
+	headerAuthToken = "X-Auth-Token"
+)
+
+// resource is the JSON representation of a Redfish resource.
+type resource map[string]interface{}
+
+// link returns a reference to the Redfish resource located at uri.
+func link(uri string) resource {
+	return resource{"@odata.id": uri}
+}
+
+// credentials are the username and password a request is authenticated with.
+type credentials struct {
+	username string
+	password string
+}
+
+// matches reports whether two sets of credentials are equal.
+func (c credentials) matches(other credentials) bool {
+	return subtle.ConstantTimeCompare([]byte(c.username), []byte(other.username)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(c.password), []byte(other.password)) == 1
+}
+
+// session is a Redfish session created through the SessionService of an endpoint.
+type session struct {
+	id          string
+	token       string
+	credentials credentials
+}
+
+// endpoint serves the Redfish service of one or more emulated systems on a single host and port. Each system is
+// managed by a manager of the same ID, which provides a virtual CD/DVD device.
+type endpoint struct {
+	listenAddress string
+	hostname      string
+	secure        bool
+
+	listener net.Listener
+	server   *http.Server
+	errs     chan error
+
+	mu            sync.Mutex
+	systems       map[string]*system
+	sessions      map[string]*session
+	nextSessionID int
+}
+
+// newEndpoint creates an endpoint that listens on listenAddress once started. hostname is the host clients use to
+// reach the endpoint.
+func newEndpoint(listenAddress, hostname string, secure bool) *endpoint {
+	return &endpoint{
+		listenAddress: listenAddress,
+		hostname:      hostname,
+		secure:        secure,
+		errs:          make(chan error, 1),
+		systems:       make(map[string]*system),
+		sessions:      make(map[string]*session),
+		nextSessionID: 1,
+	}
+}
+
+// start starts serving the endpoint.
+func (ep *endpoint) start() error {
+	listener, err := net.Listen("tcp", ep.listenAddress)
+	if err != nil {
+		return err
+	}
+
+	ep.listener = listener
+	if ep.secure {
+		cert, certErr := selfSignedCertificate(ep.hostname)
+		if certErr != nil {
+			listener.Close()
+			return certErr
+		}
+
+		listener = tls.NewListener(listener, &tls.Config{Certificates: []tls.Certificate{cert}}) //nolint:gosec
+	}
+
+	ep.server = &http.Server{Handler: ep}
+	go func() {
+		ep.errs <- ep.server.Serve(listener)
+	}()
+
+	return nil
+}
+
+// stop stops serving the endpoint.
+func (ep *endpoint) stop() error {
+	if ep.server == nil {
+		return nil
+	}
+
+	if err := ep.server.Shutdown(context.Background()); err != nil {
+		return err
+	}
+
+	if err := <-ep.errs; err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+// port returns the port the endpoint listens on.
+func (ep *endpoint) port() int {
+	return ep.listener.Addr().(*net.TCPAddr).Port
+}
+
+// ServeHTTP serves the Redfish resources of the endpoint. Requests are served one at a time.
+func (ep *endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log.Debugf("Emulated BMC '%s' received %s %s from '%s'.", ep.listener.Addr(), r.Method, r.URL.Path,
+		r.RemoteAddr)
+
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if path == serviceRootPath {
+		ep.serveServiceRoot(w, r)
+		return
+	}
+
+	if subPath, ok := within(path, sessionsPath); ok {
+		ep.serveSessions(w, r, subPath)
+		return
+	}
+
+	if subPath, ok := within(path, systemsPath); ok {
+		ep.serveSystems(w, r, subPath)
+		return
+	}
+
+	if subPath, ok := within(path, managersPath); ok {
+		ep.serveManagers(w, r, subPath)
+		return
+	}
+
+	writeNotFound(w, r)
+}
+
+// within reports whether path is located within the collection located at collectionPath, and returns path relative
+// to the collection. The relative path is empty when path is the collection itself.
+func within(path, collectionPath string) (string, bool) {
+	collectionPath = strings.TrimSuffix(collectionPath, "/")
+	if path == collectionPath {
+		return "", true
+	}
+
+	if strings.HasPrefix(path, collectionPath+"/") {
+		return strings.TrimPrefix(path, collectionPath+"/"), true
+	}
+
+	return "", false
+}
+
+func (ep *endpoint) serveServiceRoot(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	// The service root is the only resource that is available without authentication
+	writeJSON(w, http.StatusOK, resource{
+		"@odata.id":      serviceRootPath,
+		"@odata.type":    "#ServiceRoot.v1_5_0.ServiceRoot",
+		"Id":             "RootService",
+		"Name":           "Root Service",
+		"RedfishVersion": "1.6.0",
+		"Vendor":         "Airship",
+		"Product":        "airshipctl BMC emulator",
+		"Systems":        link(strings.TrimSuffix(systemsPath, "/")),
+		"Managers":       link(strings.TrimSuffix(managersPath, "/")),
+		"SessionService": link(strings.TrimSuffix(sessionsPath, "/Sessions")),
+		"Links":          resource{"Sessions": link(sessionsPath)},
+	})
+}
+
+// serveSessions serves the sessions collection when sessionID is empty, and a single session otherwise.
+func (ep *endpoint) serveSessions(w http.ResponseWriter, r *http.Request, sessionID string) {
+	if sessionID == "" {
+		if !allowMethods(w, r, http.MethodPost) {
+			return
+		}
+
+		var req struct {
+			UserName string `json:"UserName"`
+			Password string `json:"Password"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			msg := malformedJSON()
+			writeError(w, http.StatusBadRequest, msg.id, msg.message, msg.resolution)
+			return
+		}
+
+		creds := credentials{username: req.UserName, password: req.Password}
+		if !ep.knows(creds) {
+			writeUnauthorized(w)
+			return
+		}
+
+		token, err := randomToken()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "InternalError", err.Error(), "")
+			return
+		}
+
+		s := &session{id: strconv.Itoa(ep.nextSessionID), token: token, credentials: creds}
+		ep.nextSessionID++
+		ep.sessions[s.id] = s
+
+		location := sessionsPath + "/" + s.id
+		w.Header().Set(headerAuthToken, s.token)
+		w.Header().Set("Location", location)
+		writeJSON(w, http.StatusCreated, resource{"@odata.id": location, "Id": s.id, "UserName": req.UserName})
+		return
+	}
+
+	s, ok := ep.sessions[sessionID]
+	if !ok {
+		writeNotFound(w, r)
+		return
+	}
+
+	if !allowMethods(w, r, http.MethodDelete) {
+		return
+	}
+
+	if creds, authenticated := ep.authenticate(r); !authenticated || !creds.matches(s.credentials) {
+		writeUnauthorized(w)
+		return
+	}
+
+	delete(ep.sessions, sessionID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// serveSystems serves the systems collection when subPath is empty, and a system or one of its actions otherwise.
+func (ep *endpoint) serveSystems(w http.ResponseWriter, r *http.Request, subPath string) {
+	creds, authenticated := ep.authenticate(r)
+	if !authenticated {
+		writeUnauthorized(w)
+		return
+	}
+
+	if subPath == "" {
+		if allowMethods(w, r, http.MethodGet) {
+			writeJSON(w, http.StatusOK, ep.collection(systemsPath, "Computer System Collection", creds))
+		}
+		return
+	}
+
+	segments := strings.Split(subPath, "/")
+	sys, ok := ep.systems[segments[0]]
+	if !ok || !creds.matches(sys.credentials) {
+		writeNotFound(w, r)
+		return
+	}
+
+	switch strings.Join(segments[1:], "/") {
+	case "":
+		if !allowMethods(w, r, http.MethodGet, http.MethodPatch) {
+			return
+		}
+
+		if r.Method == http.MethodPatch {
+			if status, msg := sys.patch(r); status != http.StatusOK {
+				writeError(w, status, msg.id, msg.message, msg.resolution)
+				return
+			}
+		}
+
+		writeJSON(w, http.StatusOK, sys.resource())
+	case "Actions/ComputerSystem.Reset":
+		if !allowMethods(w, r, http.MethodPost) {
+			return
+		}
+
+		if status, msg := sys.reset(r); status != http.StatusNoContent {
+			writeError(w, status, msg.id, msg.message, msg.resolution)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeNotFound(w, r)
+	}
+}
+
+// serveManagers serves the managers collection when subPath is empty, and a manager or its virtual media otherwise.
+func (ep *endpoint) serveManagers(w http.ResponseWriter, r *http.Request, subPath string) {
+	creds, authenticated := ep.authenticate(r)
+	if !authenticated {
+		writeUnauthorized(w)
+		return
+	}
+
+	if subPath == "" {
+		if allowMethods(w, r, http.MethodGet) {
+			writeJSON(w, http.StatusOK, ep.collection(managersPath, "Manager Collection", creds))
+		}
+		return
+	}
+
+	segments := strings.Split(subPath, "/")
+	sys, ok := ep.systems[segments[0]]
+	if !ok || !creds.matches(sys.credentials) {
+		writeNotFound(w, r)
+		return
+	}
+
+	var status int
+	var msg message
+	switch strings.Join(segments[1:], "/") {
+	case "":
+		if allowMethods(w, r, http.MethodGet) {
+			writeJSON(w, http.StatusOK, sys.managerResource())
+		}
+		return
+	case "VirtualMedia":
+		if allowMethods(w, r, http.MethodGet) {
+			writeJSON(w, http.StatusOK, resource{
+				"@odata.id":           managersPath + sys.id + "/VirtualMedia",
+				"Name":                "Virtual Media Services",
+				"Members":             []resource{link(sys.virtualMediaPath())},
+				"Members@odata.count": 1,
+			})
+		}
+		return
+	case "VirtualMedia/" + virtualMediaID:
+		if allowMethods(w, r, http.MethodGet) {
+			writeJSON(w, http.StatusOK, sys.virtualMediaResource())
+		}
+		return
+	case "VirtualMedia/" + virtualMediaID + "/Actions/VirtualMedia.InsertMedia":
+		if !allowMethods(w, r, http.MethodPost) {
+			return
+		}
+		status, msg = sys.insertMedia(r)
+	case "VirtualMedia/" + virtualMediaID + "/Actions/VirtualMedia.EjectMedia":
+		if !allowMethods(w, r, http.MethodPost) {
+			return
+		}
+		status, msg = sys.ejectMedia()
+	default:
+		writeNotFound(w, r)
+		return
+	}
+
+	if status != http.StatusNoContent {
+		writeError(w, status, msg.id, msg.message, msg.resolution)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// collection returns a resource collection located at path whose members are the systems, or managers, that creds
+// grant access to.
+func (ep *endpoint) collection(path, name string, creds credentials) resource {
+	var ids []string
+	for id, sys := range ep.systems {
+		if creds.matches(sys.credentials) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	members := make([]resource, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, link(path+id))
+	}
+
+	return resource{
+		"@odata.id":           strings.TrimSuffix(path, "/"),
+		"Name":                name,
+		"Members":             members,
+		"Members@odata.count": len(members),
+	}
+}
+
+// authenticate returns the credentials a request is authenticated with, either directly through basic
+// authentication or through the token of a session. Only credentials of an emulated system are accepted.
+func (ep *endpoint) authenticate(r *http.Request) (credentials, bool) {
+	if token := r.Header.Get(headerAuthToken); token != "" {
+		for _, s := range ep.sessions {
+			if subtle.ConstantTimeCompare([]byte(s.token), []byte(token)) == 1 {
+				return s.credentials, true
+			}
+		}
+
+		return credentials{}, false
+	}
+
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return credentials{}, false
+	}
+
+	creds := credentials{username: username, password: password}
+	return creds, ep.knows(creds)
+}
+
+// knows reports whether creds are the credentials of at least one emulated system.
+func (ep *endpoint) knows(creds credentials) bool {
+	for _, sys := range ep.systems {
+		if creds.matches(sys.credentials) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// message describes the outcome of a failed request as a Redfish message.
+type message struct {
+	id         string
+	message    string
+	resolution string
+}
+
+// allowMethods reports whether the method of a request is one of methods, and responds with an error otherwise.
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "OperationNotAllowed",
+		fmt.Sprintf("The %s method is not allowed on '%s'.", r.Method, r.URL.Path), "")
+	return false
+}
+
+// writeNotFound responds that the resource a request targets does not exist.
+func writeNotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, "ResourceMissingAtURI",
+		fmt.Sprintf("The resource at the URI '%s' was not found.", r.URL.Path),
+		"Place a valid resource at the URI or correct the URI and resubmit the request.")
+}
+
+// writeUnauthorized responds that a request lacks valid credentials.
+func writeUnauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Basic realm="airshipctl BMC emulator"`)
+	writeError(w, http.StatusUnauthorized, "InsufficientPrivilege",
+		"There are insufficient privileges for the account or credentials associated with the current session "+
+			"to perform the requested operation.",
+		"Either abandon the operation or change the associated access rights and resubmit the request.")
+}
+
+// writeError responds with a Redfish error whose extended information holds a single message of the Base registry.
+func writeError(w http.ResponseWriter, status int, messageID, msg, resolution string) {
+	info := resource{
+		"@odata.type": "#Message.v1_0_0.Message",
+		"MessageId":   "Base.1.0." + messageID,
+		"Message":     msg,
+		"Severity":    "Warning",
+	}
+	if resolution != "" {
+		info["Resolution"] = resolution
+	}
+
+	writeJSON(w, status, resource{
+		"error": resource{
+			"code":                  "Base.1.0.GeneralError",
+			"message":               "A general error has occurred. See ExtendedInfo for more information.",
+			"@Message.ExtendedInfo": []resource{info},
+		},
+	})
+}
+
+// writeJSON responds with the JSON representation of body.
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err = w.Write(data); err != nil {
+		log.Debugf("Unable to write response: %v", err)
+	}
+}
+
+// randomToken returns a random session token.
+func randomToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,52 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"fmt"
+)
+
+// ErrUnsupportedAddress is returned when the BMC address of a host cannot be emulated, e.g. because it uses a scheme
+// other than Redfish over HTTP or HTTPS.
+type ErrUnsupportedAddress struct {
+	HostName string
+	Address  string
+}
+
+func (e ErrUnsupportedAddress) Error() string {
+	return fmt.Sprintf("unable to emulate the BMC of host '%s': address '%s' is not a Redfish system URL",
+		e.HostName, e.Address)
+}
+
+// ErrDuplicateSystem is returned when the BMC addresses of two hosts point to the same Redfish system.
+type ErrDuplicateSystem struct {
+	HostName string
+	Address  string
+}
+
+func (e ErrDuplicateSystem) Error() string {
+	return fmt.Sprintf("unable to emulate the BMC of host '%s': system '%s' is already emulated for another host",
+		e.HostName, e.Address)
+}
+
+// ErrMixedSchemes is returned when hosts whose BMC addresses share a host and port do not use the same scheme, since
+// an endpoint serves either HTTP or HTTPS.
+type ErrMixedSchemes struct {
+	HostName string
+	Address  string
+}
+
+func (e ErrMixedSchemes) Error() string {
+	return fmt.Sprintf("unable to emulate the BMC of host '%s': address '%s' mixes HTTP and HTTPS with another host "+
+		"on the same port", e.HostName, e.Address)
+}
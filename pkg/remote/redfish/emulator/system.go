@@ -0,0 +1,337 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	redfishClient "opendev.org/airship/go-redfish/client"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// virtualMediaID is the ID of the virtual CD/DVD device of each emulated manager.
+const virtualMediaID = "Cd"
+
+// bootSources are the boot sources an emulated system can be instructed to boot from.
+var bootSources = []redfishClient.BootSource{
+	redfishClient.BOOTSOURCE_NONE,
+	redfishClient.BOOTSOURCE_PXE,
+	redfishClient.BOOTSOURCE_CD,
+	redfishClient.BOOTSOURCE_HDD,
+	redfishClient.BOOTSOURCE_BIOS_SETUP,
+}
+
+// resetTypes are the reset types an emulated system supports.
+var resetTypes = []redfishClient.ResetType{
+	redfishClient.RESETTYPE_ON,
+	redfishClient.RESETTYPE_FORCE_ON,
+	redfishClient.RESETTYPE_FORCE_OFF,
+	redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN,
+	redfishClient.RESETTYPE_GRACEFUL_RESTART,
+	redfishClient.RESETTYPE_FORCE_RESTART,
+	redfishClient.RESETTYPE_PUSH_POWER_BUTTON,
+	redfishClient.RESETTYPE_NMI,
+}
+
+// system holds the in-memory state of an emulated host: its power state, boot override and virtual media. Power
+// changes take effect immediately and the operating system of an emulated host always honors graceful shutdowns.
+type system struct {
+	id          string
+	name        string
+	credentials credentials
+
+	powerState  redfishClient.PowerState
+	bootTarget  redfishClient.BootSource
+	bootEnabled redfishClient.BootSourceOverrideEnabled
+
+	mediaImage    string
+	mediaInserted bool
+}
+
+// newSystem creates the emulated system of a host, which is powered off and has no boot override or media inserted.
+func newSystem(id string, host Host) *system {
+	return &system{
+		id:          id,
+		name:        host.Name,
+		credentials: credentials{username: host.Username, password: host.Password},
+		powerState:  redfishClient.POWERSTATE_OFF,
+		bootTarget:  redfishClient.BOOTSOURCE_NONE,
+		bootEnabled: redfishClient.BOOTSOURCEOVERRIDEENABLED_DISABLED,
+	}
+}
+
+func (s *system) path() string {
+	return systemsPath + s.id
+}
+
+func (s *system) managerPath() string {
+	return managersPath + s.id
+}
+
+func (s *system) virtualMediaPath() string {
+	return s.managerPath() + "/VirtualMedia/" + virtualMediaID
+}
+
+// resource returns the ComputerSystem resource of the system.
+func (s *system) resource() resource {
+	return resource{
+		"@odata.id":    s.path(),
+		"@odata.type":  "#ComputerSystem.v1_5_0.ComputerSystem",
+		"Id":           s.id,
+		"Name":         s.name,
+		"SystemType":   "Physical",
+		"Manufacturer": "Airship",
+		"Model":        "Emulated System",
+		"PowerState":   s.powerState,
+		"Boot": resource{
+			"BootSourceOverrideEnabled":                        s.bootEnabled,
+			"BootSourceOverrideTarget":                         s.bootTarget,
+			"BootSourceOverrideTarget@Redfish.AllowableValues": bootSources,
+		},
+		"Links": resource{
+			"ManagedBy": []resource{link(s.managerPath())},
+		},
+		"Actions": resource{
+			"#ComputerSystem.Reset": resource{
+				"target":                            s.path() + "/Actions/ComputerSystem.Reset",
+				"ResetType@Redfish.AllowableValues": resetTypes,
+			},
+		},
+	}
+}
+
+// managerResource returns the Manager resource of the BMC of the system.
+func (s *system) managerResource() resource {
+	return resource{
+		"@odata.id":    s.managerPath(),
+		"@odata.type":  "#Manager.v1_3_0.Manager",
+		"Id":           s.id,
+		"Name":         "Emulated BMC",
+		"ManagerType":  "BMC",
+		"Manufacturer": "Airship",
+		"Model":        "airshipctl BMC emulator",
+		"VirtualMedia": link(s.managerPath() + "/VirtualMedia"),
+		"Links": resource{
+			"ManagerForServers": []resource{link(s.path())},
+		},
+	}
+}
+
+// virtualMediaResource returns the VirtualMedia resource of the virtual CD/DVD device of the BMC of the system.
+func (s *system) virtualMediaResource() resource {
+	connectedVia := "NotConnected"
+	if s.mediaInserted {
+		connectedVia = "URI"
+	}
+
+	return resource{
+		"@odata.id":      s.virtualMediaPath(),
+		"@odata.type":    "#VirtualMedia.v1_2_0.VirtualMedia",
+		"Id":             virtualMediaID,
+		"Name":           "Virtual CD",
+		"MediaTypes":     []string{"CD", "DVD"},
+		"Image":          s.mediaImage,
+		"Inserted":       s.mediaInserted,
+		"WriteProtected": true,
+		"ConnectedVia":   connectedVia,
+		"Actions": resource{
+			"#VirtualMedia.InsertMedia": link(s.virtualMediaPath() + "/Actions/VirtualMedia.InsertMedia"),
+			"#VirtualMedia.EjectMedia":  link(s.virtualMediaPath() + "/Actions/VirtualMedia.EjectMedia"),
+		},
+	}
+}
+
+// patch updates the boot override of the system. Other properties are read-only.
+func (s *system) patch(r *http.Request) (int, message) {
+	var req struct {
+		Boot struct {
+			BootSourceOverrideTarget  redfishClient.BootSource                `json:"BootSourceOverrideTarget"`
+			BootSourceOverrideEnabled redfishClient.BootSourceOverrideEnabled `json:"BootSourceOverrideEnabled"`
+		} `json:"Boot"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return http.StatusBadRequest, malformedJSON()
+	}
+
+	target := req.Boot.BootSourceOverrideTarget
+	if target != "" && !containsBootSource(target) {
+		return http.StatusBadRequest, message{
+			id: "PropertyValueNotInList",
+			message: fmt.Sprintf("The value %s for the property BootSourceOverrideTarget is not in the list of "+
+				"acceptable values.", target),
+			resolution: "Choose a value from the enumeration list that the implementation can support and " +
+				"resubmit the request if the operation failed.",
+		}
+	}
+
+	enabled := req.Boot.BootSourceOverrideEnabled
+	switch enabled {
+	case "", redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE, redfishClient.BOOTSOURCEOVERRIDEENABLED_CONTINUOUS,
+		redfishClient.BOOTSOURCEOVERRIDEENABLED_DISABLED:
+	default:
+		return http.StatusBadRequest, message{
+			id: "PropertyValueNotInList",
+			message: fmt.Sprintf("The value %s for the property BootSourceOverrideEnabled is not in the list of "+
+				"acceptable values.", enabled),
+			resolution: "Choose a value from the enumeration list that the implementation can support and " +
+				"resubmit the request if the operation failed.",
+		}
+	}
+
+	if target != "" {
+		s.bootTarget = target
+	}
+
+	// Like most BMCs, the override applies to the next boot only unless requested otherwise
+	switch {
+	case enabled != "":
+		s.bootEnabled = enabled
+	case target != "" && s.bootEnabled == redfishClient.BOOTSOURCEOVERRIDEENABLED_DISABLED:
+		s.bootEnabled = redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE
+	}
+
+	log.Printf("Emulated host '%s' boot override set to '%s' (%s).", s.name, s.bootTarget, s.bootEnabled)
+	return http.StatusOK, message{}
+}
+
+// reset performs a Reset action on the system.
+func (s *system) reset(r *http.Request) (int, message) {
+	var req redfishClient.ResetRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return http.StatusBadRequest, malformedJSON()
+	}
+
+	switch req.ResetType {
+	case redfishClient.RESETTYPE_ON, redfishClient.RESETTYPE_FORCE_ON:
+		if s.powerState != redfishClient.POWERSTATE_ON {
+			s.boot()
+		}
+	case redfishClient.RESETTYPE_FORCE_OFF, redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN:
+		s.powerOff()
+	case redfishClient.RESETTYPE_GRACEFUL_RESTART, redfishClient.RESETTYPE_FORCE_RESTART:
+		s.boot()
+	case redfishClient.RESETTYPE_PUSH_POWER_BUTTON:
+		if s.powerState == redfishClient.POWERSTATE_ON {
+			s.powerOff()
+		} else {
+			s.boot()
+		}
+	case redfishClient.RESETTYPE_NMI:
+	default:
+		return http.StatusBadRequest, message{
+			id: "ActionParameterValueNotInList",
+			message: fmt.Sprintf("The value %s for the parameter ResetType in the action ComputerSystem.Reset is "+
+				"not in the list of acceptable values.", req.ResetType),
+			resolution: "Choose a value from the enumeration list that the implementation can support and " +
+				"resubmit the request if the operation failed.",
+		}
+	}
+
+	return http.StatusNoContent, message{}
+}
+
+// boot powers the system on, or restarts it, and consumes a boot override that applies to a single boot.
+func (s *system) boot() {
+	device := "its default boot device"
+	if s.bootEnabled != redfishClient.BOOTSOURCEOVERRIDEENABLED_DISABLED && s.bootTarget != redfishClient.BOOTSOURCE_NONE {
+		device = string(s.bootTarget)
+	}
+
+	if s.bootTarget == redfishClient.BOOTSOURCE_CD && s.mediaInserted {
+		device = fmt.Sprintf("virtual media '%s'", s.mediaImage)
+	}
+
+	s.powerState = redfishClient.POWERSTATE_ON
+	if s.bootEnabled == redfishClient.BOOTSOURCEOVERRIDEENABLED_ONCE {
+		s.bootEnabled = redfishClient.BOOTSOURCEOVERRIDEENABLED_DISABLED
+	}
+
+	log.Printf("Emulated host '%s' powered on and booted from %s.", s.name, device)
+}
+
+// powerOff powers the system off.
+func (s *system) powerOff() {
+	if s.powerState == redfishClient.POWERSTATE_OFF {
+		return
+	}
+
+	s.powerState = redfishClient.POWERSTATE_OFF
+	log.Printf("Emulated host '%s' powered off.", s.name)
+}
+
+// insertMedia inserts an image into the virtual CD/DVD device of the system.
+func (s *system) insertMedia(r *http.Request) (int, message) {
+	var req redfishClient.InsertMediaRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return http.StatusBadRequest, malformedJSON()
+	}
+
+	if req.Image == "" {
+		return http.StatusBadRequest, message{
+			id: "ActionParameterMissing",
+			message: "The action VirtualMedia.InsertMedia requires the parameter Image to be present in the " +
+				"request body.",
+			resolution: "Supply the action with the required parameter in the request body when the request is " +
+				"resubmitted.",
+		}
+	}
+
+	if s.mediaInserted {
+		return http.StatusConflict, message{
+			id:         "ResourceInUse",
+			message:    fmt.Sprintf("The virtual media already has the image '%s' inserted.", s.mediaImage),
+			resolution: "Eject the inserted image and resubmit the request.",
+		}
+	}
+
+	s.mediaImage = req.Image
+	s.mediaInserted = true
+
+	log.Printf("Emulated host '%s' virtual media '%s' inserted.", s.name, s.mediaImage)
+	return http.StatusNoContent, message{}
+}
+
+// ejectMedia ejects the image inserted into the virtual CD/DVD device of the system, if any.
+func (s *system) ejectMedia() (int, message) {
+	if s.mediaInserted {
+		log.Printf("Emulated host '%s' virtual media '%s' ejected.", s.name, s.mediaImage)
+	}
+
+	s.mediaImage = ""
+	s.mediaInserted = false
+
+	return http.StatusNoContent, message{}
+}
+
+// containsBootSource reports whether an emulated system can boot from source.
+func containsBootSource(source redfishClient.BootSource) bool {
+	for _, bootSource := range bootSources {
+		if bootSource == source {
+			return true
+		}
+	}
+
+	return false
+}
+
+// malformedJSON describes a request whose body is not valid JSON.
+func malformedJSON() message {
+	return message{
+		id:         "MalformedJSON",
+		message:    "The request body submitted was malformed JSON and could not be parsed by the receiving service.",
+		resolution: "Ensure that the request body is valid JSON and resubmit the request.",
+	}
+}
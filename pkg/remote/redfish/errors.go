@@ -16,6 +16,7 @@ package redfish
 
 import (
 	"fmt"
+	"strings"
 
 	aerror "opendev.org/airship/airshipctl/pkg/errors"
 )
@@ -39,16 +40,6 @@ func (e ErrRedfishMissingConfig) Error() string {
 	return "missing configuration: " + e.What
 }
 
-// ErrOperationRetriesExceeded raised if number of operation retries exceeded
-type ErrOperationRetriesExceeded struct {
-	What    string
-	Retries int
-}
-
-func (e ErrOperationRetriesExceeded) Error() string {
-	return fmt.Sprintf("Unable to %s. Maximum retries (%d) exceeded.", e.What, e.Retries)
-}
-
 // ErrUnrecognizedRedfishResponse is a debug error that describes unexpected formats in a Redfish error response.
 type ErrUnrecognizedRedfishResponse struct {
 	aerror.AirshipError
@@ -58,3 +49,61 @@ type ErrUnrecognizedRedfishResponse struct {
 func (e ErrUnrecognizedRedfishResponse) Error() string {
 	return fmt.Sprintf("Unable to decode Redfish response. Key '%s' is missing or has unknown format.", e.Key)
 }
+
+// ErrTaskFailed describes a Redfish task or iDRAC job that finished without completing successfully.
+type ErrTaskFailed struct {
+	aerror.AirshipError
+	TaskURI  string
+	State    string
+	Messages []TaskMessage
+}
+
+func (e ErrTaskFailed) Error() string {
+	message := fmt.Sprintf("Task '%s' finished in state '%s'.", e.TaskURI, e.State)
+	if len(e.Messages) == 0 {
+		return message
+	}
+
+	messages := make([]string, 0, len(e.Messages))
+	for _, m := range e.Messages {
+		messages = append(messages, m.String())
+	}
+
+	return fmt.Sprintf("%s BMC reported: '%s'", message, strings.Join(messages, "; "))
+}
+
+// ErrInvalidCABundle describes a CA bundle that contains no PEM encoded certificates.
+type ErrInvalidCABundle struct {
+	aerror.AirshipError
+	Path string
+}
+
+func (e ErrInvalidCABundle) Error() string {
+	return fmt.Sprintf("CA bundle '%s' contains no PEM encoded certificates", e.Path)
+}
+
+// ErrInvalidFingerprint describes a pinned certificate fingerprint that is not a SHA-256 fingerprint.
+type ErrInvalidFingerprint struct {
+	aerror.AirshipError
+	Fingerprint string
+}
+
+func (e ErrInvalidFingerprint) Error() string {
+	return fmt.Sprintf("'%s' is not a SHA-256 certificate fingerprint", e.Fingerprint)
+}
+
+// ErrCertificateNotPinned describes a BMC that presented a certificate whose fingerprint matches none of the pinned
+// fingerprints.
+type ErrCertificateNotPinned struct {
+	aerror.AirshipError
+	Fingerprint string
+}
+
+func (e ErrCertificateNotPinned) Error() string {
+	if e.Fingerprint == "" {
+		return "BMC presented no certificate to verify against the pinned fingerprints"
+	}
+
+	return fmt.Sprintf("BMC certificate with SHA-256 fingerprint '%s' matches none of the pinned fingerprints",
+		e.Fingerprint)
+}
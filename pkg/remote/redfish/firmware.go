@@ -0,0 +1,168 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
+)
+
+const endpointUpdateService = "/redfish/v1/UpdateService"
+
+// updateServiceResource holds the properties of the UpdateService resource used to list and update firmware.
+type updateServiceResource struct {
+	FirmwareInventory odataID `json:"FirmwareInventory"`
+	Actions           struct {
+		SimpleUpdate struct {
+			Target string `json:"target"`
+		} `json:"#UpdateService.SimpleUpdate"`
+	} `json:"Actions"`
+}
+
+type softwareInventoryResource struct {
+	ID         string         `json:"Id"`
+	Name       string         `json:"Name"`
+	Version    string         `json:"Version"`
+	Updateable bool           `json:"Updateable"`
+	Status     resourceStatus `json:"Status"`
+}
+
+// simpleUpdateRequest holds the parameters of the SimpleUpdate action of the UpdateService.
+type simpleUpdateRequest struct {
+	ImageURI string   `json:"ImageURI"`
+	Targets  []string `json:"Targets,omitempty"`
+}
+
+// FirmwareInventory retrieves the firmware components of a host, along with their versions, from the
+// FirmwareInventory collection of the UpdateService. Components reported as absent are omitted.
+func (c *Client) FirmwareInventory(ctx context.Context) ([]inventory.Firmware, error) {
+	var updateService updateServiceResource
+	if err := c.getResource(ctx, endpointUpdateService, &updateService); err != nil {
+		return nil, err
+	}
+
+	members, err := c.listCollection(ctx, updateService.firmwareInventoryURI())
+	if err != nil {
+		return nil, err
+	}
+
+	var components []inventory.Firmware
+	for _, member := range members {
+		var software softwareInventoryResource
+		if err = c.getResource(ctx, member, &software); err != nil {
+			return nil, err
+		}
+
+		if software.Status.State == stateAbsent {
+			continue
+		}
+
+		components = append(components, inventory.Firmware{
+			ID:         software.ID,
+			Name:       software.Name,
+			Version:    software.Version,
+			Updateable: software.Updateable,
+		})
+	}
+
+	log.Debugf("Retrieved %d firmware component(s) of node '%s'.", len(components), c.nodeID)
+
+	return components, nil
+}
+
+// UpdateFirmware instructs the BMC of a host to download the firmware image located at imageURL and apply it, using
+// the SimpleUpdate action of the UpdateService, and waits for the update task to finish. Targets restricts the update
+// to firmware components, identified by their ID in the firmware inventory or by URI; the BMC selects the components
+// to update from the image when no targets are supplied.
+//
+// Firmware updates take far longer than other operations. When ctx has a deadline, the update task is polled until
+// that deadline rather than until the timeout of the client's retry policy expires.
+func (c *Client) UpdateFirmware(ctx context.Context, imageURL string, targets []string) error {
+	var updateService updateServiceResource
+	if err := c.getResource(ctx, endpointUpdateService, &updateService); err != nil {
+		return err
+	}
+
+	req := simpleUpdateRequest{ImageURI: imageURL}
+	for _, target := range targets {
+		if !strings.Contains(target, "/") {
+			target = updateService.firmwareInventoryURI() + "/" + target
+		}
+
+		req.Targets = append(req.Targets, target)
+	}
+
+	actionURI := updateService.Actions.SimpleUpdate.Target
+	if actionURI == "" {
+		actionURI = endpointUpdateService + "/Actions/UpdateService.SimpleUpdate"
+	}
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Updating firmware of node '%s' from '%s' (targets: %v).", c.nodeID, imageURL, req.Targets)
+
+	httpResp, body, err := c.rawRequestWithBody(ctx, http.MethodPost, actionURI, reqBody)
+	if err != nil {
+		return err
+	}
+
+	switch httpResp.StatusCode {
+	case http.StatusAccepted:
+	case http.StatusOK, http.StatusNoContent:
+		log.Debug("Firmware update completed synchronously.")
+		return nil
+	default:
+		message := fmt.Sprintf("Unable to update firmware of node '%s'. BMC responded '%s'.", c.nodeID,
+			httpResp.Status)
+		return ResponseError(message, body)
+	}
+
+	// The body of the response has already been read; restore it so that the task can be located from it.
+	httpResp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	taskURI, err := c.taskURI(httpResp)
+	if err != nil {
+		return err
+	}
+
+	policy := c.retryPolicy
+	if _, ok := ctx.Deadline(); ok {
+		policy.Timeout = 0
+	}
+
+	if err = c.waitForTaskURI(ctx, taskURI, policy); err != nil {
+		return err
+	}
+
+	log.Debugf("Successfully updated firmware of node '%s'.", c.nodeID)
+	return nil
+}
+
+// firmwareInventoryURI returns the location of the FirmwareInventory collection of the UpdateService.
+func (u updateServiceResource) firmwareInventoryURI() string {
+	if u.FirmwareInventory.OdataID == "" {
+		return endpointUpdateService + "/FirmwareInventory"
+	}
+
+	return u.FirmwareInventory.OdataID
+}
@@ -0,0 +1,171 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
+)
+
+// firmwareResources are the resources served by a fake BMC for a host with BIOS and BMC firmware and an absent NIC.
+var firmwareResources = map[string]string{
+	"/redfish/v1/UpdateService": `{"FirmwareInventory": {"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory"},
+		"Actions": {"#UpdateService.SimpleUpdate": {
+			"target": "/redfish/v1/UpdateService/Actions/UpdateService.SimpleUpdate"}}}`,
+	"/redfish/v1/UpdateService/FirmwareInventory": `{"Members": [
+		{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/BIOS"},
+		{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/BMC"},
+		{"@odata.id": "/redfish/v1/UpdateService/FirmwareInventory/NIC.2"}]}`,
+	"/redfish/v1/UpdateService/FirmwareInventory/BIOS": `{"Id": "BIOS", "Name": "BIOS", "Version": "2.8.1",
+		"Updateable": true, "Status": {"State": "Enabled"}}`,
+	"/redfish/v1/UpdateService/FirmwareInventory/BMC": `{"Id": "BMC", "Name": "Integrated Remote Access Controller",
+		"Version": "4.20.20.20", "Updateable": true}`,
+	"/redfish/v1/UpdateService/FirmwareInventory/NIC.2": `{"Id": "NIC.2", "Status": {"State": "Absent"}}`,
+	"/redfish/v1/TaskService/Tasks/1":                   `{"Id": "1", "TaskState": "Completed", "TaskStatus": "OK"}`,
+}
+
+// updateServer serves firmware resources and records the SimpleUpdate requests it receives. A SimpleUpdate request
+// starts the task /redfish/v1/TaskService/Tasks/1, unless the server rejects updates.
+type updateServer struct {
+	mu        sync.Mutex
+	resources map[string]string
+	updates   []simpleUpdateRequest
+	reject    bool
+}
+
+func (s *updateServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Method == http.MethodPost && s.reject {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			panic(err)
+		}
+
+		var update simpleUpdateRequest
+		if err = json.Unmarshal(body, &update); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		s.updates = append(s.updates, update)
+		w.Header().Set("Location", "/redfish/v1/TaskService/Tasks/1")
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	body, ok := s.resources[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if _, err := w.Write([]byte(body)); err != nil {
+		panic(err)
+	}
+}
+
+func newFirmwareClient(t *testing.T, resources map[string]string) (*Client, *updateServer, func()) {
+	t.Helper()
+
+	update := &updateServer{resources: resources}
+	server := httptest.NewServer(update)
+
+	_, client, err := NewClient(server.URL+"/redfish/v1/Systems/1", nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	client.Sleep = func(_ time.Duration) {}
+
+	return client, update, server.Close
+}
+
+func TestFirmwareInventory(t *testing.T) {
+	client, _, closeServer := newFirmwareClient(t, firmwareResources)
+	defer closeServer()
+
+	components, err := client.FirmwareInventory(context.Background())
+	require.NoError(t, err)
+
+	expected := []inventory.Firmware{
+		{ID: "BIOS", Name: "BIOS", Version: "2.8.1", Updateable: true},
+		{ID: "BMC", Name: "Integrated Remote Access Controller", Version: "4.20.20.20", Updateable: true},
+	}
+	assert.Equal(t, expected, components)
+}
+
+func TestUpdateFirmware(t *testing.T) {
+	client, update, closeServer := newFirmwareClient(t, firmwareResources)
+	defer closeServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	err := client.UpdateFirmware(ctx, "http://images.example.com/bios.exe",
+		[]string{"BIOS", "/redfish/v1/UpdateService/FirmwareInventory/BMC"})
+	require.NoError(t, err)
+
+	expected := []simpleUpdateRequest{{
+		ImageURI: "http://images.example.com/bios.exe",
+		Targets: []string{
+			"/redfish/v1/UpdateService/FirmwareInventory/BIOS",
+			"/redfish/v1/UpdateService/FirmwareInventory/BMC",
+		},
+	}}
+	assert.Equal(t, expected, update.updates)
+}
+
+func TestUpdateFirmwareTaskFailed(t *testing.T) {
+	resources := make(map[string]string)
+	for uri, body := range firmwareResources {
+		resources[uri] = body
+	}
+	resources["/redfish/v1/TaskService/Tasks/1"] = `{"Id": "1", "TaskState": "Exception", "TaskStatus": "Critical",
+		"Messages": [{"MessageId": "Update.1.0.ImageInvalid", "Message": "The image is not valid."}]}`
+
+	client, _, closeServer := newFirmwareClient(t, resources)
+	defer closeServer()
+
+	err := client.UpdateFirmware(context.Background(), "http://images.example.com/bios.exe", nil)
+	require.Error(t, err)
+	assert.IsType(t, ErrTaskFailed{}, err)
+	assert.Contains(t, err.Error(), "The image is not valid.")
+}
+
+func TestUpdateFirmwareRejected(t *testing.T) {
+	client, update, closeServer := newFirmwareClient(t, map[string]string{"/redfish/v1/UpdateService": `{}`})
+	defer closeServer()
+
+	update.reject = true
+
+	err := client.UpdateFirmware(context.Background(), "ftp://images.example.com/bios.exe", nil)
+	_, ok := err.(ErrRedfishClient)
+	assert.True(t, ok)
+	assert.Contains(t, err.Error(), "Unable to update firmware")
+}
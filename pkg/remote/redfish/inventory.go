@@ -0,0 +1,278 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
+)
+
+const (
+	endpointSystems = "/redfish/v1/Systems/"
+
+	stateAbsent = "Absent"
+)
+
+// resourceStatus holds the Status property common to Redfish resources.
+type resourceStatus struct {
+	State  string `json:"State"`
+	Health string `json:"Health"`
+}
+
+// systemResource holds the properties of a ComputerSystem resource used to build a hardware inventory.
+type systemResource struct {
+	Manufacturer       string  `json:"Manufacturer"`
+	Model              string  `json:"Model"`
+	SerialNumber       string  `json:"SerialNumber"`
+	Processors         odataID `json:"Processors"`
+	Memory             odataID `json:"Memory"`
+	EthernetInterfaces odataID `json:"EthernetInterfaces"`
+	Storage            odataID `json:"Storage"`
+	MemorySummary      struct {
+		TotalSystemMemoryGiB float64 `json:"TotalSystemMemoryGiB"`
+	} `json:"MemorySummary"`
+}
+
+type processorResource struct {
+	ID           string         `json:"Id"`
+	Model        string         `json:"Model"`
+	TotalCores   int            `json:"TotalCores"`
+	TotalThreads int            `json:"TotalThreads"`
+	MaxSpeedMHz  int            `json:"MaxSpeedMHz"`
+	Status       resourceStatus `json:"Status"`
+}
+
+type memoryResource struct {
+	ID                string         `json:"Id"`
+	CapacityMiB       int            `json:"CapacityMiB"`
+	MemoryDeviceType  string         `json:"MemoryDeviceType"`
+	OperatingSpeedMhz int            `json:"OperatingSpeedMhz"`
+	Status            resourceStatus `json:"Status"`
+}
+
+type ethernetInterfaceResource struct {
+	ID                  string `json:"Id"`
+	Name                string `json:"Name"`
+	MACAddress          string `json:"MACAddress"`
+	PermanentMACAddress string `json:"PermanentMACAddress"`
+	LinkStatus          string `json:"LinkStatus"`
+	SpeedMbps           int    `json:"SpeedMbps"`
+}
+
+type storageResource struct {
+	ID                 string `json:"Id"`
+	Name               string `json:"Name"`
+	StorageControllers []struct {
+		Name         string `json:"Name"`
+		Manufacturer string `json:"Manufacturer"`
+		Model        string `json:"Model"`
+	} `json:"StorageControllers"`
+	Drives []odataID `json:"Drives"`
+}
+
+type driveResource struct {
+	ID            string         `json:"Id"`
+	Name          string         `json:"Name"`
+	Model         string         `json:"Model"`
+	SerialNumber  string         `json:"SerialNumber"`
+	CapacityBytes int64          `json:"CapacityBytes"`
+	MediaType     string         `json:"MediaType"`
+	Protocol      string         `json:"Protocol"`
+	Status        resourceStatus `json:"Status"`
+}
+
+// Inventory retrieves the processors, memory, network interfaces and storage of a host from the ComputerSystem
+// resource of the BMC and the collections it links to. Components reported as absent are omitted.
+func (c *Client) Inventory(ctx context.Context) (inventory.Inventory, error) {
+	var inv inventory.Inventory
+
+	var system systemResource
+	if err := c.getResource(ctx, endpointSystems+c.nodeID, &system); err != nil {
+		return inv, err
+	}
+
+	inv.Manufacturer = system.Manufacturer
+	inv.Model = system.Model
+	inv.SerialNumber = system.SerialNumber
+	inv.Memory.TotalGiB = system.MemorySummary.TotalSystemMemoryGiB
+
+	var err error
+	if inv.Processors, err = c.processors(ctx, system.Processors.OdataID); err != nil {
+		return inv, err
+	}
+
+	if inv.Memory.Modules, err = c.memoryModules(ctx, system.Memory.OdataID); err != nil {
+		return inv, err
+	}
+
+	if inv.NICs, err = c.nics(ctx, system.EthernetInterfaces.OdataID); err != nil {
+		return inv, err
+	}
+
+	if inv.Storage, err = c.storage(ctx, system.Storage.OdataID); err != nil {
+		return inv, err
+	}
+
+	log.Debugf("Retrieved inventory of node '%s': %d processor(s), %d NIC(s), %d storage subsystem(s).", c.nodeID,
+		len(inv.Processors), len(inv.NICs), len(inv.Storage))
+
+	return inv, nil
+}
+
+func (c *Client) processors(ctx context.Context, uri string) ([]inventory.Processor, error) {
+	members, err := c.listOptionalCollection(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	var processors []inventory.Processor
+	for _, member := range members {
+		var processor processorResource
+		if err = c.getResource(ctx, member, &processor); err != nil {
+			return nil, err
+		}
+
+		if processor.Status.State == stateAbsent {
+			continue
+		}
+
+		processors = append(processors, inventory.Processor{
+			ID:          processor.ID,
+			Model:       processor.Model,
+			Cores:       processor.TotalCores,
+			Threads:     processor.TotalThreads,
+			MaxSpeedMHz: processor.MaxSpeedMHz,
+		})
+	}
+
+	return processors, nil
+}
+
+func (c *Client) memoryModules(ctx context.Context, uri string) ([]inventory.MemoryModule, error) {
+	members, err := c.listOptionalCollection(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []inventory.MemoryModule
+	for _, member := range members {
+		var memory memoryResource
+		if err = c.getResource(ctx, member, &memory); err != nil {
+			return nil, err
+		}
+
+		if memory.Status.State == stateAbsent || memory.CapacityMiB == 0 {
+			continue
+		}
+
+		modules = append(modules, inventory.MemoryModule{
+			ID:          memory.ID,
+			CapacityMiB: memory.CapacityMiB,
+			Type:        memory.MemoryDeviceType,
+			SpeedMHz:    memory.OperatingSpeedMhz,
+		})
+	}
+
+	return modules, nil
+}
+
+func (c *Client) nics(ctx context.Context, uri string) ([]inventory.NIC, error) {
+	members, err := c.listOptionalCollection(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	var nics []inventory.NIC
+	for _, member := range members {
+		var nic ethernetInterfaceResource
+		if err = c.getResource(ctx, member, &nic); err != nil {
+			return nil, err
+		}
+
+		macAddress := nic.MACAddress
+		if macAddress == "" {
+			macAddress = nic.PermanentMACAddress
+		}
+
+		nics = append(nics, inventory.NIC{
+			ID:         nic.ID,
+			Name:       nic.Name,
+			MACAddress: macAddress,
+			LinkStatus: nic.LinkStatus,
+			SpeedMbps:  nic.SpeedMbps,
+		})
+	}
+
+	return nics, nil
+}
+
+func (c *Client) storage(ctx context.Context, uri string) ([]inventory.Storage, error) {
+	members, err := c.listOptionalCollection(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	var subsystems []inventory.Storage
+	for _, member := range members {
+		var storage storageResource
+		if err = c.getResource(ctx, member, &storage); err != nil {
+			return nil, err
+		}
+
+		subsystem := inventory.Storage{ID: storage.ID, Name: storage.Name}
+		for _, controller := range storage.StorageControllers {
+			subsystem.Controllers = append(subsystem.Controllers, inventory.StorageController{
+				Name:         controller.Name,
+				Manufacturer: controller.Manufacturer,
+				Model:        controller.Model,
+			})
+		}
+
+		for _, driveURI := range storage.Drives {
+			var drive driveResource
+			if err = c.getResource(ctx, driveURI.OdataID, &drive); err != nil {
+				return nil, err
+			}
+
+			if drive.Status.State == stateAbsent {
+				continue
+			}
+
+			subsystem.Drives = append(subsystem.Drives, inventory.Drive{
+				ID:            drive.ID,
+				Name:          drive.Name,
+				Model:         drive.Model,
+				SerialNumber:  drive.SerialNumber,
+				CapacityBytes: drive.CapacityBytes,
+				MediaType:     drive.MediaType,
+				Protocol:      drive.Protocol,
+			})
+		}
+
+		subsystems = append(subsystems, subsystem)
+	}
+
+	return subsystems, nil
+}
+
+// listOptionalCollection retrieves the members of a collection linked to by a resource. BMCs omit links to
+// collections they do not implement, in which case no members are returned.
+func (c *Client) listOptionalCollection(ctx context.Context, uri string) ([]string, error) {
+	if uri == "" {
+		return nil, nil
+	}
+
+	return c.listCollection(ctx, uri)
+}
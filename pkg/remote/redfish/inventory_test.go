@@ -0,0 +1,159 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
+)
+
+// inventoryResources are the resources served by a fake BMC for a host with one processor, two DIMM slots (one
+// empty), one NIC and a RAID controller with one drive.
+var inventoryResources = map[string]string{
+	"/redfish/v1/Systems/1": `{"Manufacturer": "Dell Inc.", "Model": "PowerEdge R640", "SerialNumber": "ABC123",
+		"MemorySummary": {"TotalSystemMemoryGiB": 32},
+		"Processors": {"@odata.id": "/redfish/v1/Systems/1/Processors"},
+		"Memory": {"@odata.id": "/redfish/v1/Systems/1/Memory"},
+		"EthernetInterfaces": {"@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces"},
+		"Storage": {"@odata.id": "/redfish/v1/Systems/1/Storage"}}`,
+	"/redfish/v1/Systems/1/Processors": `{"Members": [{"@odata.id": "/redfish/v1/Systems/1/Processors/CPU.1"}]}`,
+	"/redfish/v1/Systems/1/Processors/CPU.1": `{"Id": "CPU.1", "Model": "Intel(R) Xeon(R) Gold 6130",
+		"TotalCores": 16, "TotalThreads": 32, "MaxSpeedMHz": 3700, "Status": {"State": "Enabled"}}`,
+	"/redfish/v1/Systems/1/Memory": `{"Members": [{"@odata.id": "/redfish/v1/Systems/1/Memory/DIMM.A1"},
+		{"@odata.id": "/redfish/v1/Systems/1/Memory/DIMM.A2"}]}`,
+	"/redfish/v1/Systems/1/Memory/DIMM.A1": `{"Id": "DIMM.A1", "CapacityMiB": 32768, "MemoryDeviceType": "DDR4",
+		"OperatingSpeedMhz": 2666, "Status": {"State": "Enabled"}}`,
+	"/redfish/v1/Systems/1/Memory/DIMM.A2": `{"Id": "DIMM.A2", "Status": {"State": "Absent"}}`,
+	"/redfish/v1/Systems/1/EthernetInterfaces": `{"Members": [
+		{"@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces/NIC.1"}]}`,
+	"/redfish/v1/Systems/1/EthernetInterfaces/NIC.1": `{"Id": "NIC.1", "Name": "Integrated NIC 1 Port 1",
+		"MACAddress": "00:3B:8B:0C:EC:8B", "LinkStatus": "LinkUp", "SpeedMbps": 10000}`,
+	"/redfish/v1/Systems/1/Storage": `{"Members": [{"@odata.id": "/redfish/v1/Systems/1/Storage/RAID.1"}]}`,
+	"/redfish/v1/Systems/1/Storage/RAID.1": `{"Id": "RAID.1", "Name": "PERC H730P Mini",
+		"StorageControllers": [{"Name": "PERC H730P Mini", "Manufacturer": "DELL", "Model": "PERC H730P Mini"}],
+		"Drives": [{"@odata.id": "/redfish/v1/Systems/1/Storage/Drives/Disk.0"}]}`,
+	"/redfish/v1/Systems/1/Storage/Drives/Disk.0": `{"Id": "Disk.0", "Name": "Physical Disk 0", "Model": "ST600MM0208",
+		"SerialNumber": "W0K0ABCD", "CapacityBytes": 599550590976, "MediaType": "HDD", "Protocol": "SAS"}`,
+}
+
+func newInventoryClient(t *testing.T, resources map[string]string) (*Client, func()) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := resources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, err := w.Write([]byte(body))
+		if err != nil {
+			panic(err)
+		}
+	}))
+
+	_, client, err := NewClient(server.URL+"/redfish/v1/Systems/1", nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	return client, server.Close
+}
+
+func TestInventory(t *testing.T) {
+	client, closeServer := newInventoryClient(t, inventoryResources)
+	defer closeServer()
+
+	inv, err := client.Inventory(context.Background())
+	require.NoError(t, err)
+
+	expected := inventory.Inventory{
+		Manufacturer: "Dell Inc.",
+		Model:        "PowerEdge R640",
+		SerialNumber: "ABC123",
+		Processors: []inventory.Processor{{
+			ID:          "CPU.1",
+			Model:       "Intel(R) Xeon(R) Gold 6130",
+			Cores:       16,
+			Threads:     32,
+			MaxSpeedMHz: 3700,
+		}},
+		Memory: inventory.Memory{
+			TotalGiB: 32,
+			Modules:  []inventory.MemoryModule{{ID: "DIMM.A1", CapacityMiB: 32768, Type: "DDR4", SpeedMHz: 2666}},
+		},
+		NICs: []inventory.NIC{{
+			ID:         "NIC.1",
+			Name:       "Integrated NIC 1 Port 1",
+			MACAddress: "00:3B:8B:0C:EC:8B",
+			LinkStatus: "LinkUp",
+			SpeedMbps:  10000,
+		}},
+		Storage: []inventory.Storage{{
+			ID:   "RAID.1",
+			Name: "PERC H730P Mini",
+			Controllers: []inventory.StorageController{
+				{Name: "PERC H730P Mini", Manufacturer: "DELL", Model: "PERC H730P Mini"},
+			},
+			Drives: []inventory.Drive{{
+				ID:            "Disk.0",
+				Name:          "Physical Disk 0",
+				Model:         "ST600MM0208",
+				SerialNumber:  "W0K0ABCD",
+				CapacityBytes: 599550590976,
+				MediaType:     "HDD",
+				Protocol:      "SAS",
+			}},
+		}},
+	}
+	assert.Equal(t, expected, inv)
+}
+
+func TestInventoryWithoutStorage(t *testing.T) {
+	resources := map[string]string{
+		"/redfish/v1/Systems/1": `{"MemorySummary": {"TotalSystemMemoryGiB": 8},
+			"EthernetInterfaces": {"@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces"}}`,
+		"/redfish/v1/Systems/1/EthernetInterfaces": `{"Members": [
+			{"@odata.id": "/redfish/v1/Systems/1/EthernetInterfaces/1"}]}`,
+		"/redfish/v1/Systems/1/EthernetInterfaces/1": `{"Id": "1", "PermanentMACAddress": "52:54:00:12:34:56"}`,
+	}
+
+	client, closeServer := newInventoryClient(t, resources)
+	defer closeServer()
+
+	inv, err := client.Inventory(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, inv.Storage)
+	assert.Empty(t, inv.Processors)
+	assert.Equal(t, 8.0, inv.Memory.TotalGiB)
+	assert.Equal(t, []inventory.NIC{{ID: "1", MACAddress: "52:54:00:12:34:56"}}, inv.NICs)
+}
+
+func TestInventoryMissingResource(t *testing.T) {
+	resources := map[string]string{
+		"/redfish/v1/Systems/1": `{"Processors": {"@odata.id": "/redfish/v1/Systems/1/Processors"}}`,
+	}
+
+	client, closeServer := newInventoryClient(t, resources)
+	defer closeServer()
+
+	_, err := client.Inventory(context.Background())
+	_, ok := err.(ErrRedfishClient)
+	assert.True(t, ok)
+}
@@ -0,0 +1,332 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	aerror "opendev.org/airship/airshipctl/pkg/errors"
+)
+
+// Severities of Redfish messages
+const (
+	SeverityOK       = "OK"
+	SeverityWarning  = "Warning"
+	SeverityCritical = "Critical"
+)
+
+// ErrBMCMessage describes a failed Redfish request whose error response carries a message of a Redfish message
+// registry, e.g. Base.1.8.AccessDenied or IDRAC.2.5.RAC0218. Messages listed in the message catalogue are returned
+// as the error type of their category, e.g. ErrBMCAuthentication, which embeds ErrBMCMessage and carries a hint on how
+// to remediate the failure; other messages are returned as ErrBMCMessage.
+type ErrBMCMessage struct {
+	aerror.AirshipError
+	// Description describes the request that failed.
+	Description string
+	// MessageID, Message, Severity and Resolution are those of the most severe message reported by the BMC.
+	MessageID  string
+	Message    string
+	Severity   string
+	Resolution string
+	// Hint describes how to remediate the failure.
+	Hint string
+	// Messages holds every message reported by the BMC, in the order they were reported.
+	Messages []TaskMessage
+}
+
+func (e ErrBMCMessage) Error() string {
+	message := e.Message
+	if e.Resolution != "" {
+		message = fmt.Sprintf("%s %s", message, e.Resolution)
+	}
+
+	result := fmt.Sprintf("redfish client encountered an error: %s BMC responded: '%s' (MessageId %s, severity %s).",
+		e.Description, message, e.MessageID, e.Severity)
+	if e.Hint != "" {
+		result = fmt.Sprintf("%s Hint: %s", result, e.Hint)
+	}
+
+	return result
+}
+
+// BMCMessage returns the BMC message describing the failure.
+func (e ErrBMCMessage) BMCMessage() ErrBMCMessage {
+	return e
+}
+
+// BMCError is implemented by the errors describing a failed Redfish request whose error response carries a message
+// of a Redfish message registry.
+type BMCError interface {
+	error
+	BMCMessage() ErrBMCMessage
+}
+
+// ErrBMCAuthentication describes a request the BMC rejected because the credentials are invalid, the account lacks
+// privileges or is locked out, or the session expired.
+type ErrBMCAuthentication struct {
+	ErrBMCMessage
+}
+
+// ErrBMCUnavailable describes a request the BMC could not serve because it is busy, e.g. the resource is in use or
+// the maximum number of sessions is reached, or because its service is unavailable.
+type ErrBMCUnavailable struct {
+	ErrBMCMessage
+}
+
+// ErrBMCResourceNotFound describes a request for a resource the BMC does not provide, e.g. a system or a virtual
+// media device.
+type ErrBMCResourceNotFound struct {
+	ErrBMCMessage
+}
+
+// ErrBMCUnsupported describes a request for an action, a property or a feature the BMC does not support or is not
+// licensed for.
+type ErrBMCUnsupported struct {
+	ErrBMCMessage
+}
+
+// ErrBMCInvalidRequest describes a request the BMC rejected because its body or parameters are invalid.
+type ErrBMCInvalidRequest struct {
+	ErrBMCMessage
+}
+
+// ErrBMCConnection describes a request that failed because the BMC could not access a remote resource, e.g. the ISO
+// image to insert into a virtual media device.
+type ErrBMCConnection struct {
+	ErrBMCMessage
+}
+
+// ErrBMCInternal describes a request that failed because of an internal error of the BMC.
+type ErrBMCInternal struct {
+	ErrBMCMessage
+}
+
+// catalogueEntry describes how a message of a Redfish message registry is reported.
+type catalogueEntry struct {
+	newError func(ErrBMCMessage) error
+	hint     string
+}
+
+func authentication(hint string) catalogueEntry {
+	return catalogueEntry{func(m ErrBMCMessage) error { return ErrBMCAuthentication{m} }, hint}
+}
+
+func unavailable(hint string) catalogueEntry {
+	return catalogueEntry{func(m ErrBMCMessage) error { return ErrBMCUnavailable{m} }, hint}
+}
+
+func resourceNotFound(hint string) catalogueEntry {
+	return catalogueEntry{func(m ErrBMCMessage) error { return ErrBMCResourceNotFound{m} }, hint}
+}
+
+func unsupported(hint string) catalogueEntry {
+	return catalogueEntry{func(m ErrBMCMessage) error { return ErrBMCUnsupported{m} }, hint}
+}
+
+func invalidRequest(hint string) catalogueEntry {
+	return catalogueEntry{func(m ErrBMCMessage) error { return ErrBMCInvalidRequest{m} }, hint}
+}
+
+func connection(hint string) catalogueEntry {
+	return catalogueEntry{func(m ErrBMCMessage) error { return ErrBMCConnection{m} }, hint}
+}
+
+func internal(hint string) catalogueEntry {
+	return catalogueEntry{func(m ErrBMCMessage) error { return ErrBMCInternal{m} }, hint}
+}
+
+const (
+	hintRetry         = "Retry the operation once the BMC has finished the operations in progress."
+	hintCheckRequest  = "Verify that the BMC supports the requested settings, e.g. with the inventory command."
+	hintCheckFirmware = "Verify that the firmware of the BMC supports the operation, and update it if necessary."
+	hintRemoteURL     = "Verify that the URL is correct and that the BMC can reach it over its management network."
+)
+
+// messageCatalogue maps the messages of the Redfish Base registry and of the Dell iDRAC registry, identified by their
+// registry prefix and message key without the registry version, to the category of their error and a hint on how to
+// remediate it.
+var messageCatalogue = map[string]catalogueEntry{
+	"Base.AccessDenied": authentication("Verify the BMC credentials, and that the BMC account has not been locked " +
+		"out by repeated failed logins."),
+	"Base.InsufficientPrivilege": authentication("Grant the BMC account a role that allows the operation, e.g. " +
+		"Administrator."),
+	"Base.NoValidSession": authentication("Verify the BMC credentials. When session authentication is enabled, the " +
+		"session may have expired or been deleted by the BMC."),
+	"Base.PasswordChangeRequired": authentication("Log in to the BMC to change the password of the BMC account, " +
+		"then update the BMC credentials."),
+
+	"Base.ResourceInUse":                 unavailable(hintRetry),
+	"Base.ServiceInUnknownState":         unavailable("Reset the BMC, then retry the operation."),
+	"Base.ServiceShuttingDown":           unavailable("Retry the operation once the BMC has restarted."),
+	"Base.ServiceTemporarilyUnavailable": unavailable(hintRetry),
+	"Base.SessionLimitExceeded": unavailable("Delete unused sessions on the BMC, or lower the concurrency of " +
+		"airshipctl, then retry the operation."),
+	"IDRAC.RAC0218": unavailable("Delete unused sessions on the iDRAC, or lower the concurrency of airshipctl, then " +
+		"retry the operation."),
+
+	"Base.ResourceMissingAtURI": resourceNotFound("Verify the system ID in the BMC address of the host, and that the " +
+		"BMC provides the resource, e.g. a virtual media device of type CD or DVD."),
+	"Base.ResourceNotFound": resourceNotFound("Verify the system ID in the BMC address of the host, and that the BMC " +
+		"provides the resource, e.g. a virtual media device of type CD or DVD."),
+
+	"Base.ActionNotSupported":          unsupported(hintCheckFirmware),
+	"Base.ActionParameterNotSupported": unsupported(hintCheckFirmware),
+	"Base.OperationNotAllowed":         unsupported(hintCheckFirmware),
+	"Base.PropertyNotWritable":         unsupported("Remove the read-only setting from the request."),
+	"Base.QueryNotSupported":           unsupported(hintCheckFirmware),
+	"IDRAC.LIC501": unsupported("Install an iDRAC license that includes the feature, e.g. an Enterprise license " +
+		"for virtual media."),
+
+	"Base.ActionParameterMissing":          invalidRequest(hintCheckFirmware),
+	"Base.ActionParameterUnknown":          invalidRequest(hintCheckFirmware),
+	"Base.ActionParameterValueFormatError": invalidRequest(hintCheckRequest),
+	"Base.ActionParameterValueTypeError":   invalidRequest(hintCheckRequest),
+	"Base.MalformedJSON":                   invalidRequest(hintCheckFirmware),
+	"Base.PropertyMissing":                 invalidRequest(hintCheckFirmware),
+	"Base.PropertyUnknown":                 invalidRequest(hintCheckRequest),
+	"Base.PropertyValueFormatError":        invalidRequest(hintCheckRequest),
+	"Base.PropertyValueNotInList":          invalidRequest(hintCheckRequest),
+	"Base.PropertyValueTypeError":          invalidRequest(hintCheckRequest),
+
+	"Base.CouldNotEstablishConnection":  connection(hintRemoteURL),
+	"Base.ResourceAtUriInUnknownFormat": connection("Verify that the URL locates a file of the expected format."),
+	"Base.ResourceAtUriUnauthorized": connection("Verify that the remote server allows the BMC to access the URL " +
+		"without credentials."),
+	"Base.SourceDoesNotSupportProtocol": connection("Serve the file over a protocol the BMC supports, e.g. HTTP."),
+
+	"Base.GeneralError":  internal("Inspect the logs of the BMC for the cause of the failure."),
+	"Base.InternalError": internal("Retry the operation. When it keeps failing, reset the BMC."),
+}
+
+// DecodeBMCError decodes the messages of a raw Redfish error response into the error of their category, describing
+// the request that failed with description. The most severe message reported by the BMC determines the error; nil is
+// returned when the response carries no message with a MessageId.
+func DecodeBMCError(description string, rawResponse []byte) error {
+	var response struct {
+		Error struct {
+			ExtendedInfo json.RawMessage `json:"@Message.ExtendedInfo"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(rawResponse, &response); err != nil || len(response.Error.ExtendedInfo) == 0 {
+		return nil
+	}
+
+	// The specification dictates that "@Message.ExtendedInfo" is an array; however, some BMCs return a single object.
+	var messages []bmcMessage
+	if err := json.Unmarshal(response.Error.ExtendedInfo, &messages); err != nil {
+		var message bmcMessage
+		if err = json.Unmarshal(response.Error.ExtendedInfo, &message); err != nil {
+			return nil
+		}
+
+		messages = []bmcMessage{message}
+	}
+
+	bmcErr := ErrBMCMessage{Description: description}
+	primary := -1
+	for i, m := range messages {
+		bmcErr.Messages = append(bmcErr.Messages, m.taskMessage())
+		if m.MessageID == "" {
+			continue
+		}
+
+		if primary < 0 || severityRank(m.severity()) > severityRank(messages[primary].severity()) {
+			primary = i
+		}
+	}
+
+	if primary < 0 {
+		return nil
+	}
+
+	m := messages[primary]
+	bmcErr.MessageID = m.MessageID
+	bmcErr.Message = m.Message
+	bmcErr.Severity = m.severity()
+	bmcErr.Resolution = m.Resolution
+
+	entry, ok := messageCatalogue[messageKey(m.MessageID)]
+	if !ok {
+		return bmcErr
+	}
+
+	bmcErr.Hint = entry.hint
+	return entry.newError(bmcErr)
+}
+
+// ResponseError describes a failed Redfish request with message. When the error response of the BMC carries a
+// message of a Redfish message registry, the error of its category is returned; otherwise the error is an
+// ErrRedfishClient that includes any message decoded from the response. Vendor clients use it to describe the failed
+// requests they make outside of the Redfish API.
+func ResponseError(message string, rawResponse []byte) error {
+	if err := DecodeBMCError(message, rawResponse); err != nil {
+		return err
+	}
+
+	if bmcResponse, decodeErr := DecodeRawError(rawResponse); decodeErr == nil {
+		message = fmt.Sprintf("%s BMC responded: '%s'", message, bmcResponse)
+	}
+
+	return ErrRedfishClient{Message: message}
+}
+
+// bmcMessage holds the properties of a Redfish Message. The Severity property was deprecated in favor of
+// MessageSeverity, and is still returned by many BMCs.
+type bmcMessage struct {
+	MessageID       string `json:"MessageId"`
+	Message         string `json:"Message"`
+	Severity        string `json:"Severity"`
+	MessageSeverity string `json:"MessageSeverity"`
+	Resolution      string `json:"Resolution"`
+}
+
+func (m bmcMessage) severity() string {
+	if m.MessageSeverity != "" {
+		return m.MessageSeverity
+	}
+
+	return m.Severity
+}
+
+func (m bmcMessage) taskMessage() TaskMessage {
+	return TaskMessage{MessageID: m.MessageID, Message: m.Message, Severity: m.severity(), Resolution: m.Resolution}
+}
+
+// severityRank orders severities from the least to the most severe.
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityOK:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// messageKey identifies a message by its registry prefix and message key, without the registry version, e.g.
+// Base.1.8.AccessDenied is identified by Base.AccessDenied and IDRAC.2.5.RAC0218 by IDRAC.RAC0218. The first letter
+// of the prefix is capitalized, since some iDRAC firmware reports messages of the iDRAC registry.
+func messageKey(messageID string) string {
+	parts := strings.Split(messageID, ".")
+	if len(parts) < 2 || parts[0] == "" {
+		return messageID
+	}
+
+	return strings.ToUpper(parts[0][:1]) + parts[0][1:] + "." + parts[len(parts)-1]
+}
@@ -0,0 +1,125 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	accessDeniedResponse = `{"error": {"code": "Base.1.8.GeneralError", "message": "A general error has occurred.",
+		"@Message.ExtendedInfo": [{"MessageId": "Base.1.8.AccessDenied", "MessageSeverity": "Critical",
+			"Message": "While attempting to establish a connection to /redfish/v1/Systems/1, the service denied access.",
+			"Resolution": "Attempt to ensure that the URI is correct and that the service has the appropriate credentials."
+		}]}}`
+	sessionLimitResponse = `{"error": {"@Message.ExtendedInfo": {"MessageId": "IDRAC.2.5.RAC0218", "Severity": "Critical",
+		"Message": "The maximum number of user sessions is reached.",
+		"Resolution": "Close one or more sessions and retry the operation."}}}`
+	mixedSeverityResponse = `{"error": {"@Message.ExtendedInfo": [
+		{"MessageId": "Base.1.8.PropertyValueNotInList", "Severity": "Warning",
+			"Message": "The value Legacy for the property BootMode is not in the list of acceptable values."},
+		{"MessageId": "iDRAC.2.5.LIC501", "Severity": "Critical",
+			"Message": "A required license is missing or expired."}]}}`
+	unknownMessageResponse = `{"error": {"@Message.ExtendedInfo": [{"MessageId": "Contoso.1.0.Overheated",
+		"Severity": "Warning", "Message": "The BMC is too hot."}]}}`
+)
+
+func TestDecodeBMCError(t *testing.T) {
+	err := DecodeBMCError("Unable to retrieve '/redfish/v1/Systems/1'.", []byte(accessDeniedResponse))
+	authErr, ok := err.(ErrBMCAuthentication)
+	require.True(t, ok, "%T", err)
+
+	assert.Equal(t, "Base.1.8.AccessDenied", authErr.MessageID)
+	assert.Equal(t, SeverityCritical, authErr.Severity)
+	assert.Contains(t, authErr.Hint, "locked out")
+	require.Len(t, authErr.Messages, 1)
+	assert.Equal(t, "Base.1.8.AccessDenied", authErr.Messages[0].MessageID)
+
+	assert.Equal(t, "redfish client encountered an error: Unable to retrieve '/redfish/v1/Systems/1'. BMC responded: "+
+		"'While attempting to establish a connection to /redfish/v1/Systems/1, the service denied access. Attempt to "+
+		"ensure that the URI is correct and that the service has the appropriate credentials.' (MessageId "+
+		"Base.1.8.AccessDenied, severity Critical). Hint: "+messageCatalogue["Base.AccessDenied"].hint, err.Error())
+
+	bmcErr, ok := err.(BMCError)
+	require.True(t, ok)
+	assert.Equal(t, "Base.1.8.AccessDenied", bmcErr.BMCMessage().MessageID)
+}
+
+func TestDecodeBMCErrorCategories(t *testing.T) {
+	tests := []struct {
+		response  string
+		expected  interface{}
+		messageID string
+	}{
+		{response: sessionLimitResponse, expected: ErrBMCUnavailable{}, messageID: "IDRAC.2.5.RAC0218"},
+		{response: mixedSeverityResponse, expected: ErrBMCUnsupported{}, messageID: "iDRAC.2.5.LIC501"},
+		{response: unknownMessageResponse, expected: ErrBMCMessage{}, messageID: "Contoso.1.0.Overheated"},
+	}
+
+	for _, tt := range tests {
+		err := DecodeBMCError("Unable to insert virtual media.", []byte(tt.response))
+		require.IsType(t, tt.expected, err, tt.messageID)
+
+		bmcErr, ok := err.(BMCError)
+		require.True(t, ok, tt.messageID)
+		assert.Equal(t, tt.messageID, bmcErr.BMCMessage().MessageID)
+	}
+
+	err := DecodeBMCError("Unable to insert virtual media.", []byte(unknownMessageResponse))
+	assert.Empty(t, err.(ErrBMCMessage).Hint)
+	assert.NotContains(t, err.Error(), "Hint:")
+}
+
+func TestDecodeBMCErrorWithoutMessageID(t *testing.T) {
+	for _, response := range []string{
+		`{"error": {"@Message.ExtendedInfo": [{"Message": "Extended error message."}]}}`,
+		`{"error": {"message": "A general error has occurred."}}`,
+		`not JSON`,
+		``,
+	} {
+		assert.NoError(t, DecodeBMCError("Unable to retrieve '/redfish/v1'.", []byte(response)), response)
+	}
+
+	// A message ID without a registry prefix is reported as is
+	err := DecodeBMCError("Unable to retrieve '/redfish/v1'.",
+		[]byte(`{"error": {"@Message.ExtendedInfo": [{"MessageId": ".Foo", "Message": "Extended error message."}]}}`))
+	require.IsType(t, ErrBMCMessage{}, err)
+	assert.Equal(t, ".Foo", err.(ErrBMCMessage).MessageID)
+}
+
+func TestGetResourceBMCError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte(`{"error": {"@Message.ExtendedInfo": [{"MessageId": "Base.1.8.ResourceMissingAtURI",
+			"Severity": "Critical", "Message": "The resource at the URI /redfish/v1/Managers/1/VirtualMedia/CD was ` +
+			`not found."}]}}`))
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	_, client, err := NewClient(server.URL+"/redfish/v1/Systems/1", nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	var resource odataID
+	err = client.getResource(context.Background(), "/redfish/v1/Managers/1/VirtualMedia/CD", &resource)
+	notFound, ok := err.(ErrBMCResourceNotFound)
+	require.True(t, ok, "%T", err)
+	assert.Equal(t, "Unable to retrieve '/redfish/v1/Managers/1/VirtualMedia/CD'. BMC responded '404 Not Found'.",
+		notFound.Description)
+}
@@ -0,0 +1,194 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	redfishClient "opendev.org/airship/go-redfish/client"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
+)
+
+// systemResetResource holds the properties of a ComputerSystem resource used to reset it. The reset types a system
+// supports are not exposed by the go-redfish API.
+type systemResetResource struct {
+	PowerState redfishClient.PowerState `json:"PowerState"`
+	Actions    struct {
+		Reset struct {
+			Target          string                    `json:"target"`
+			AllowableValues []redfishClient.ResetType `json:"ResetType@Redfish.AllowableValues"`
+		} `json:"#ComputerSystem.Reset"`
+	} `json:"Actions"`
+}
+
+// SystemPowerOffGraceful shuts down a host by requesting a graceful shutdown of its operating system. When the host
+// is not powered off within timeout, or the system does not support graceful shutdowns, it is forced off.
+func (c *Client) SystemPowerOffGraceful(ctx context.Context, timeout time.Duration) error {
+	system, err := c.systemReset(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.shutdown(ctx, system, timeout)
+}
+
+// RebootSystemGraceful restarts a host that is powered on by requesting a graceful restart of its operating system,
+// when its system lists the GracefulRestart reset type. Otherwise, the host is power cycled by requesting a graceful
+// shutdown of its operating system, forcing it off when it is not powered off within timeout, followed by a power on
+// signal. A graceful restart is not bounded by timeout: the power state of a host remains on while it restarts, so a
+// restart that hangs cannot be detected and forced.
+func (c *Client) RebootSystemGraceful(ctx context.Context, timeout time.Duration) error {
+	system, err := c.systemReset(ctx)
+	if err != nil {
+		return err
+	}
+
+	if system.PowerState == redfishClient.POWERSTATE_ON && system.lists(redfishClient.RESETTYPE_GRACEFUL_RESTART) {
+		log.Debugf("Rebooting node '%s': restarting gracefully.", c.nodeID)
+		return c.reset(ctx, system, redfishClient.RESETTYPE_GRACEFUL_RESTART)
+	}
+
+	log.Debugf("Rebooting node '%s': shutting down.", c.nodeID)
+	if err = c.shutdown(ctx, system, timeout); err != nil {
+		log.Debugf("Failed to reboot node '%s': shutdown failure.", c.nodeID)
+		return err
+	}
+
+	log.Debugf("Rebooting node '%s': powering on.", c.nodeID)
+	if err = c.reset(ctx, system, redfishClient.RESETTYPE_ON); err != nil {
+		log.Debugf("Failed to reboot node '%s': startup failure.", c.nodeID)
+		return err
+	}
+
+	return c.waitForSystemPowerState(ctx, redfishClient.POWERSTATE_ON, c.retryPolicy)
+}
+
+// shutdown gracefully shuts down a host that is powered on, falling back to forcing it off.
+func (c *Client) shutdown(ctx context.Context, system systemResetResource, timeout time.Duration) error {
+	if system.PowerState == redfishClient.POWERSTATE_OFF {
+		log.Debugf("Node '%s' is already powered off.", c.nodeID)
+		return nil
+	}
+
+	if system.supports(redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN) {
+		if err := c.reset(ctx, system, redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN); err != nil {
+			return err
+		}
+
+		policy := c.retryPolicy
+		policy.Timeout = timeout
+
+		err := c.waitForSystemPowerState(ctx, redfishClient.POWERSTATE_OFF, policy)
+		if _, timedOut := err.(retry.ErrTimeout); !timedOut {
+			return err
+		}
+
+		log.Printf("Node '%s' did not shut down gracefully within %s. Forcing it off.", c.nodeID, timeout)
+	} else {
+		log.Printf("Node '%s' does not support graceful shutdown. Forcing it off.", c.nodeID)
+	}
+
+	if !system.supports(redfishClient.RESETTYPE_FORCE_OFF) {
+		return ErrRedfishClient{Message: fmt.Sprintf("Unable to shut down node '%s'. The system supports neither "+
+			"reset type '%s' nor '%s'.", c.nodeID, redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN,
+			redfishClient.RESETTYPE_FORCE_OFF)}
+	}
+
+	if err := c.reset(ctx, system, redfishClient.RESETTYPE_FORCE_OFF); err != nil {
+		return err
+	}
+
+	return c.waitForSystemPowerState(ctx, redfishClient.POWERSTATE_OFF, c.retryPolicy)
+}
+
+// systemReset retrieves the power state of a host and the reset types its system supports.
+func (c *Client) systemReset(ctx context.Context) (systemResetResource, error) {
+	var system systemResetResource
+	if err := c.getResource(ctx, endpointSystems+c.nodeID, &system); err != nil {
+		return systemResetResource{}, err
+	}
+
+	if system.Actions.Reset.Target == "" {
+		system.Actions.Reset.Target = endpointSystems + c.nodeID + "/Actions/ComputerSystem.Reset"
+	}
+
+	return system, nil
+}
+
+// reset invokes the Reset action of a system with the supplied reset type.
+func (c *Client) reset(ctx context.Context, system systemResetResource, resetType redfishClient.ResetType) error {
+	reqBody, err := json.Marshal(redfishClient.ResetRequestBody{ResetType: resetType})
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Resetting node '%s' with reset type '%s'.", c.nodeID, resetType)
+
+	httpResp, body, err := c.rawRequestWithBody(ctx, http.MethodPost, system.Actions.Reset.Target, reqBody)
+	if err != nil {
+		return err
+	}
+
+	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
+		message := fmt.Sprintf("Unable to reset node '%s' with reset type '%s'. BMC responded '%s'.", c.nodeID,
+			resetType, httpResp.Status)
+		return ResponseError(message, body)
+	}
+
+	return nil
+}
+
+// waitForSystemPowerState polls the system of a host until it reaches the desired power state or the timeout of
+// policy expires.
+func (c *Client) waitForSystemPowerState(ctx context.Context, desiredState redfishClient.PowerState,
+	policy retry.Policy) error {
+	log.Debugf("Waiting for node '%s' to reach power state '%s'.", c.nodeID, desiredState)
+
+	return c.poll(ctx, policy, fmt.Sprintf("reach desired power state %s", desiredState),
+		func(ctx context.Context) (bool, error) {
+			var system systemResetResource
+			if err := c.getResource(ctx, endpointSystems+c.nodeID, &system); err != nil {
+				return false, err
+			}
+
+			if system.PowerState == desiredState {
+				log.Debugf("Node '%s' reached power state '%s'.", c.nodeID, desiredState)
+				return true, nil
+			}
+
+			return false, nil
+		})
+}
+
+// supports reports whether a system supports a reset type. Systems that do not advertise the reset types they allow
+// are assumed to support every reset type.
+func (s systemResetResource) supports(resetType redfishClient.ResetType) bool {
+	return len(s.Actions.Reset.AllowableValues) == 0 || s.lists(resetType)
+}
+
+// lists reports whether a system advertises a reset type among the reset types it allows.
+func (s systemResetResource) lists(resetType redfishClient.ResetType) bool {
+	for _, allowed := range s.Actions.Reset.AllowableValues {
+		if allowed == resetType {
+			return true
+		}
+	}
+
+	return false
+}
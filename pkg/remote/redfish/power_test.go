@@ -0,0 +1,231 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	redfishClient "opendev.org/airship/go-redfish/client"
+)
+
+const resetTarget = "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"
+
+// resetServer serves a ComputerSystem resource and records the reset types requested through its Reset action. The
+// power state of the system follows the reset requests, except graceful shutdowns when the operating system is hung.
+type resetServer struct {
+	mu              sync.Mutex
+	powerState      redfishClient.PowerState
+	allowableValues []redfishClient.ResetType
+	hung            bool
+	resets          []redfishClient.ResetType
+}
+
+func (s *resetServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/redfish/v1/Systems/1":
+		var system systemResetResource
+		system.PowerState = s.powerState
+		system.Actions.Reset.Target = resetTarget
+		system.Actions.Reset.AllowableValues = s.allowableValues
+
+		body, err := json.Marshal(system)
+		if err != nil {
+			panic(err)
+		}
+
+		if _, err = w.Write(body); err != nil {
+			panic(err)
+		}
+	case r.Method == http.MethodPost && r.URL.Path == resetTarget:
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			panic(err)
+		}
+
+		var req redfishClient.ResetRequestBody
+		if err = json.Unmarshal(body, &req); err != nil || !s.allows(req.ResetType) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		s.resets = append(s.resets, req.ResetType)
+		switch {
+		case req.ResetType == redfishClient.RESETTYPE_ON:
+			s.powerState = redfishClient.POWERSTATE_ON
+		case req.ResetType == redfishClient.RESETTYPE_FORCE_OFF:
+			s.powerState = redfishClient.POWERSTATE_OFF
+		case req.ResetType == redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN && !s.hung:
+			s.powerState = redfishClient.POWERSTATE_OFF
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *resetServer) allows(resetType redfishClient.ResetType) bool {
+	if len(s.allowableValues) == 0 {
+		return true
+	}
+
+	for _, allowed := range s.allowableValues {
+		if allowed == resetType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newResetClient(t *testing.T, system *resetServer) (*Client, func()) {
+	t.Helper()
+
+	server := httptest.NewServer(system)
+
+	_, client, err := NewClient(server.URL+"/redfish/v1/Systems/1", nil, false, false, "", "", timeoutPolicy)
+	require.NoError(t, err)
+
+	client.Sleep = func(_ time.Duration) {}
+
+	return client, server.Close
+}
+
+func TestSystemPowerOffGraceful(t *testing.T) {
+	tests := []struct {
+		name           string
+		system         *resetServer
+		expectedResets []redfishClient.ResetType
+		expectedErr    bool
+	}{
+		{
+			name: "Graceful",
+			system: &resetServer{powerState: redfishClient.POWERSTATE_ON, allowableValues: []redfishClient.ResetType{
+				redfishClient.RESETTYPE_ON, redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN, redfishClient.RESETTYPE_FORCE_OFF,
+			}},
+			expectedResets: []redfishClient.ResetType{redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN},
+		},
+		{
+			name:   "ForcedAfterTimeout",
+			system: &resetServer{powerState: redfishClient.POWERSTATE_ON, hung: true},
+			expectedResets: []redfishClient.ResetType{
+				redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN, redfishClient.RESETTYPE_FORCE_OFF,
+			},
+		},
+		{
+			name: "GracefulNotSupported",
+			system: &resetServer{powerState: redfishClient.POWERSTATE_ON, allowableValues: []redfishClient.ResetType{
+				redfishClient.RESETTYPE_ON, redfishClient.RESETTYPE_FORCE_OFF,
+			}},
+			expectedResets: []redfishClient.ResetType{redfishClient.RESETTYPE_FORCE_OFF},
+		},
+		{
+			name: "ForceNotSupported",
+			system: &resetServer{powerState: redfishClient.POWERSTATE_ON, hung: true,
+				allowableValues: []redfishClient.ResetType{redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN}},
+			expectedResets: []redfishClient.ResetType{redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN},
+			expectedErr:    true,
+		},
+		{
+			name:   "AlreadyOff",
+			system: &resetServer{powerState: redfishClient.POWERSTATE_OFF},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			client, closeServer := newResetClient(t, tt.system)
+			defer closeServer()
+
+			err := client.SystemPowerOffGraceful(context.Background(), 10*time.Millisecond)
+			if tt.expectedErr {
+				assert.IsType(t, ErrRedfishClient{}, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, redfishClient.POWERSTATE_OFF, tt.system.powerState)
+			}
+
+			assert.Equal(t, tt.expectedResets, tt.system.resets)
+		})
+	}
+}
+
+func TestRebootSystemGraceful(t *testing.T) {
+	// Systems that do not list the GracefulRestart reset type are shut down gracefully, then powered on
+	system := &resetServer{powerState: redfishClient.POWERSTATE_ON, hung: true}
+	client, closeServer := newResetClient(t, system)
+	defer closeServer()
+
+	err := client.RebootSystemGraceful(context.Background(), 10*time.Millisecond)
+	require.NoError(t, err)
+
+	assert.Equal(t, redfishClient.POWERSTATE_ON, system.powerState)
+	assert.Equal(t, []redfishClient.ResetType{
+		redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN, redfishClient.RESETTYPE_FORCE_OFF, redfishClient.RESETTYPE_ON,
+	}, system.resets)
+}
+
+func TestRebootSystemGracefulRestart(t *testing.T) {
+	system := &resetServer{powerState: redfishClient.POWERSTATE_ON, allowableValues: []redfishClient.ResetType{
+		redfishClient.RESETTYPE_ON, redfishClient.RESETTYPE_FORCE_OFF, redfishClient.RESETTYPE_GRACEFUL_SHUTDOWN,
+		redfishClient.RESETTYPE_GRACEFUL_RESTART,
+	}}
+	client, closeServer := newResetClient(t, system)
+	defer closeServer()
+
+	err := client.RebootSystemGraceful(context.Background(), 10*time.Millisecond)
+	require.NoError(t, err)
+
+	assert.Equal(t, redfishClient.POWERSTATE_ON, system.powerState)
+	assert.Equal(t, []redfishClient.ResetType{redfishClient.RESETTYPE_GRACEFUL_RESTART}, system.resets)
+}
+
+func TestRebootSystemGracefulRestartPoweredOff(t *testing.T) {
+	// Hosts that are powered off are powered on rather than restarted
+	system := &resetServer{powerState: redfishClient.POWERSTATE_OFF, allowableValues: []redfishClient.ResetType{
+		redfishClient.RESETTYPE_ON, redfishClient.RESETTYPE_GRACEFUL_RESTART,
+	}}
+	client, closeServer := newResetClient(t, system)
+	defer closeServer()
+
+	err := client.RebootSystemGraceful(context.Background(), 10*time.Millisecond)
+	require.NoError(t, err)
+
+	assert.Equal(t, []redfishClient.ResetType{redfishClient.RESETTYPE_ON}, system.resets)
+}
+
+func TestRebootSystemGracefulNotSupported(t *testing.T) {
+	// Hosts whose systems support neither a graceful nor a forced shutdown are left untouched
+	system := &resetServer{powerState: redfishClient.POWERSTATE_ON,
+		allowableValues: []redfishClient.ResetType{redfishClient.RESETTYPE_ON}}
+	client, closeServer := newResetClient(t, system)
+	defer closeServer()
+
+	err := client.RebootSystemGraceful(context.Background(), 10*time.Millisecond)
+	assert.IsType(t, ErrRedfishClient{}, err)
+	assert.Empty(t, system.resets)
+}
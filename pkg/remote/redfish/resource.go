@@ -0,0 +1,143 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	redfishClient "opendev.org/airship/go-redfish/client"
+)
+
+// odataID is a link to a Redfish resource.
+type odataID struct {
+	OdataID string `json:"@odata.id"`
+}
+
+// resourceCollection holds the members of a Redfish resource collection.
+type resourceCollection struct {
+	Members []odataID `json:"Members"`
+}
+
+// getResource retrieves the Redfish resource located at uri and decodes it into resource.
+func (c *Client) getResource(ctx context.Context, uri string, resource interface{}) error {
+	httpResp, body, err := c.rawRequest(ctx, http.MethodGet, uri)
+	if err != nil {
+		return err
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		message := fmt.Sprintf("Unable to retrieve '%s'. BMC responded '%s'.", uri, httpResp.Status)
+		return ResponseError(message, body)
+	}
+
+	if err = json.Unmarshal(body, resource); err != nil {
+		return ErrRedfishClient{Message: fmt.Sprintf("Unable to decode '%s'. %v", uri, err)}
+	}
+
+	return nil
+}
+
+// listCollection retrieves the URIs of the members of the Redfish resource collection located at uri.
+func (c *Client) listCollection(ctx context.Context, uri string) ([]string, error) {
+	var collection resourceCollection
+	if err := c.getResource(ctx, uri, &collection); err != nil {
+		return nil, err
+	}
+
+	members := make([]string, 0, len(collection.Members))
+	for _, member := range collection.Members {
+		members = append(members, member.OdataID)
+	}
+
+	return members, nil
+}
+
+// patchResource updates the Redfish resource located at uri with the properties of resource.
+func (c *Client) patchResource(ctx context.Context, uri string, resource interface{}) error {
+	reqBody, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+
+	httpResp, body, err := c.rawRequestWithBody(ctx, http.MethodPatch, uri, reqBody)
+	if err != nil {
+		return err
+	}
+
+	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusMultipleChoices {
+		message := fmt.Sprintf("Unable to update '%s'. BMC responded '%s'.", uri, httpResp.Status)
+		return ResponseError(message, body)
+	}
+
+	return nil
+}
+
+// rawRequest sends a request to the BMC using the HTTP client of the go-redfish API client. It is used for resources
+// that are not available through the go-redfish API.
+func (c *Client) rawRequest(ctx context.Context, method string, uri string) (*http.Response, []byte, error) {
+	return c.rawRequestWithBody(ctx, method, uri, nil)
+}
+
+// rawRequestWithBody sends a request with a JSON body to the BMC using the HTTP client of the go-redfish API client.
+func (c *Client) rawRequestWithBody(ctx context.Context, method string, uri string,
+	reqBody []byte) (*http.Response, []byte, error) {
+	target := uri
+	if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+		base, err := url.Parse(c.RedfishCFG.BasePath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ref, err := url.Parse(uri)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		target = base.ResolveReference(ref).String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	if reqBody != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	req.Header.Add("User-Agent", c.RedfishCFG.UserAgent)
+
+	if auth, ok := ctx.Value(redfishClient.ContextBasicAuth).(redfishClient.BasicAuth); ok {
+		req.SetBasicAuth(auth.UserName, auth.Password)
+	}
+
+	httpResp, err := c.RedfishCFG.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, ErrRedfishClient{Message: fmt.Sprintf("HTTP request to '%s' failed. %v", uri, err)}
+	}
+	defer httpResp.Body.Close()
+
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return httpResp, body, nil
+}
@@ -0,0 +1,281 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+const (
+	endpointSessions = "/redfish/v1/SessionService/Sessions"
+	headerAuthToken  = "X-Auth-Token"
+	logoutTimeout    = 30 * time.Second
+)
+
+// sessionTransport is an HTTP transport that authenticates Redfish requests with a session token (X-Auth-Token)
+// instead of basic authentication credentials. A session is created using the SessionService of the BMC when the
+// first request is made and is reused for all subsequent requests until it is deleted by logout.
+type sessionTransport struct {
+	base     http.RoundTripper
+	basePath string
+	username string
+	password string
+	dryRun   bool
+
+	mu       sync.Mutex
+	token    string
+	location string
+	loggedIn bool
+}
+
+type sessionRequestBody struct {
+	UserName string `json:"UserName"`
+	Password string `json:"Password"`
+}
+
+type sessionResponseBody struct {
+	OdataID string `json:"@odata.id"`
+}
+
+// RoundTrip sends a request using the session token, creating a session first if none exists. When the BMC rejects
+// the token, e.g. because the session expired, a new session is created and the request is sent again.
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.dryRun {
+		return t.dryRunRoundTrip(req)
+	}
+
+	token, err := t.sessionToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(withAuthToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return resp, err
+	}
+
+	log.Debugf("Redfish session for '%s' was rejected. Creating a new session.", t.basePath)
+	resp.Body.Close()
+	t.invalidate(token)
+
+	if token, err = t.sessionToken(req.Context()); err != nil {
+		return nil, err
+	}
+
+	retry := withAuthToken(req, token)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+
+	return t.base.RoundTrip(retry)
+}
+
+// sessionToken returns the token of the current session, creating a session if none exists.
+func (t *sessionTransport) sessionToken(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" {
+		return t.token, nil
+	}
+
+	req, err := t.newSessionRequest(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	httpResp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return "", ErrRedfishClient{Message: fmt.Sprintf("Unable to create Redfish session. %v", err)}
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return "", ErrRedfishClient{Message: fmt.Sprintf("Unable to create Redfish session. %v", err)}
+	}
+
+	if httpResp.StatusCode != http.StatusCreated && httpResp.StatusCode != http.StatusOK {
+		message := fmt.Sprintf("Unable to create Redfish session. BMC responded '%s'.", httpResp.Status)
+		return "", ResponseError(message, respBody)
+	}
+
+	token := httpResp.Header.Get(headerAuthToken)
+	if token == "" {
+		return "", ErrRedfishClient{Message: "Unable to create Redfish session. BMC did not return a session token."}
+	}
+
+	// The session URI is used to delete the session. It is returned in the Location header, and also in the body of
+	// the response by most BMCs.
+	location := httpResp.Header.Get("Location")
+	if location == "" {
+		var session sessionResponseBody
+		if err = json.Unmarshal(respBody, &session); err == nil {
+			location = session.OdataID
+		}
+	}
+
+	t.token = token
+	t.location = t.resolve(location)
+
+	log.Debugf("Created Redfish session '%s' on '%s'.", t.location, t.basePath)
+
+	return t.token, nil
+}
+
+// dryRunRoundTrip sends a request in dry run. The request that creates the session is passed to the base transport,
+// which records it without sending it, and requests are authenticated with basic authentication credentials instead
+// of a session token, so that no session is created on the BMC.
+func (t *sessionTransport) dryRunRoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	login := !t.loggedIn
+	t.loggedIn = true
+	t.mu.Unlock()
+
+	if login {
+		sessionReq, err := t.newSessionRequest(req.Context())
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(sessionReq)
+		if err != nil {
+			return nil, ErrRedfishClient{Message: fmt.Sprintf("Unable to create Redfish session. %v", err)}
+		}
+		resp.Body.Close()
+	}
+
+	authReq := req.Clone(req.Context())
+	authReq.Header.Del(headerAuthToken)
+	authReq.SetBasicAuth(t.username, t.password)
+
+	return t.base.RoundTrip(authReq)
+}
+
+// newSessionRequest returns a request that creates a session using the credentials of the transport.
+func (t *sessionTransport) newSessionRequest(ctx context.Context) (*http.Request, error) {
+	body, err := json.Marshal(sessionRequestBody{UserName: t.username, Password: t.password})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.basePath+endpointSessions, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("User-Agent", headerUserAgent)
+
+	return req, nil
+}
+
+// invalidate discards the current session if its token matches the supplied token.
+func (t *sessionTransport) invalidate(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == token {
+		t.token = ""
+		t.location = ""
+	}
+}
+
+// logout deletes the current session, if any.
+func (t *sessionTransport) logout() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == "" {
+		return nil
+	}
+
+	token, location := t.token, t.location
+	t.token, t.location = "", ""
+
+	if location == "" {
+		log.Debugf("Unable to delete Redfish session on '%s'. The BMC did not return its URI.", t.basePath)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), logoutTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, location, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("User-Agent", headerUserAgent)
+	req.Header.Set(headerAuthToken, token)
+
+	httpResp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return ErrRedfishClient{Message: fmt.Sprintf("Unable to delete Redfish session '%s'. %v", location, err)}
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusNoContent &&
+		httpResp.StatusCode != http.StatusAccepted {
+		return ErrRedfishClient{
+			Message: fmt.Sprintf("Unable to delete Redfish session '%s'. BMC responded '%s'.", location,
+				httpResp.Status),
+		}
+	}
+
+	log.Debugf("Deleted Redfish session '%s'.", location)
+
+	return nil
+}
+
+// resolve converts a session URI, which may be relative to the BMC, into an absolute URL.
+func (t *sessionTransport) resolve(location string) string {
+	if location == "" {
+		return ""
+	}
+
+	base, err := url.Parse(t.basePath)
+	if err != nil {
+		return location
+	}
+
+	ref, err := url.Parse(location)
+	if err != nil {
+		return location
+	}
+
+	return base.ResolveReference(ref).String()
+}
+
+// withAuthToken returns a copy of a request that is authenticated with a session token. Transports must not modify the
+// requests they send.
+func withAuthToken(req *http.Request, token string) *http.Request {
+	authReq := req.Clone(req.Context())
+	authReq.Header.Del("Authorization")
+	authReq.Header.Set(headerAuthToken, token)
+
+	return authReq
+}
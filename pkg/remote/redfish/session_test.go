@@ -0,0 +1,188 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	redfishClient "opendev.org/airship/go-redfish/client"
+)
+
+// sessionService is a fake Redfish SessionService that issues sequential session tokens.
+type sessionService struct {
+	mu       sync.Mutex
+	sessions map[string]bool
+	created  int
+	deleted  []string
+	basic    int
+}
+
+func (s *sessionService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, password, basic := r.BasicAuth()
+	if basic {
+		s.basic++
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == endpointSessions:
+		var body sessionRequestBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Password != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		s.created++
+		token := fmt.Sprintf("token-%d", s.created)
+		s.sessions[token] = true
+
+		w.Header().Set(headerAuthToken, token)
+		w.Header().Set("Location", fmt.Sprintf("%s/%d", endpointSessions, s.created))
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodDelete:
+		token := r.Header.Get(headerAuthToken)
+		delete(s.sessions, token)
+		s.deleted = append(s.deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	case s.sessions[r.Header.Get(headerAuthToken)], basic && password == "password":
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+}
+
+// newSessionClient creates a client that authenticates with the fake SessionService.
+func newSessionClient(t *testing.T, password string) (*Client, *sessionService, func()) {
+	t.Helper()
+
+	service := &sessionService{sessions: make(map[string]bool)}
+	server := httptest.NewServer(service)
+
+	ctx, client, err := NewClient(server.URL+"/redfish/v1/Systems/1", nil, false, true, "username", password,
+		retryPolicy)
+	require.NoError(t, err)
+
+	// Basic authentication credentials must not be sent when using session authentication
+	assert.Nil(t, ctx.Value(redfishClient.ContextBasicAuth))
+
+	return client, service, server.Close
+}
+
+func TestSessionAuth(t *testing.T) {
+	client, service, closeServer := newSessionClient(t, "password")
+	defer closeServer()
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.RedfishCFG.HTTPClient.Get(client.RedfishCFG.BasePath + "/redfish/v1/Systems/1")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// The session is reused for all requests
+	assert.Equal(t, 1, service.created)
+	assert.Equal(t, 0, service.basic)
+
+	require.NoError(t, client.Close())
+	assert.Equal(t, []string{endpointSessions + "/1"}, service.deleted)
+	assert.Empty(t, service.sessions)
+
+	// Closing a client without a session does nothing
+	require.NoError(t, client.Close())
+	assert.Len(t, service.deleted, 1)
+}
+
+func TestSessionAuthExpired(t *testing.T) {
+	client, service, closeServer := newSessionClient(t, "password")
+	defer closeServer()
+	defer client.Close()
+
+	resp, err := client.RedfishCFG.HTTPClient.Get(client.RedfishCFG.BasePath + "/redfish/v1/Systems/1")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// Simulate the BMC expiring the session
+	service.mu.Lock()
+	service.sessions = make(map[string]bool)
+	service.mu.Unlock()
+
+	resp, err = client.RedfishCFG.HTTPClient.Get(client.RedfishCFG.BasePath + "/redfish/v1/Systems/1")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, service.created)
+}
+
+func TestSessionAuthLoginFailure(t *testing.T) {
+	client, service, closeServer := newSessionClient(t, "bad-password")
+	defer closeServer()
+
+	_, err := client.RedfishCFG.HTTPClient.Get(client.RedfishCFG.BasePath + "/redfish/v1/Systems/1")
+	assert.Error(t, err)
+	assert.Equal(t, 0, service.created)
+
+	require.NoError(t, client.Close())
+	assert.Empty(t, service.deleted)
+}
+
+func TestCloseWithoutSessionAuth(t *testing.T) {
+	_, client, err := NewClient(redfishURL, nil, false, false, "username", "password", retryPolicy)
+	require.NoError(t, err)
+
+	assert.NoError(t, client.Close())
+}
+
+func TestSessionAuthDryRun(t *testing.T) {
+	client, service, closeServer := newSessionClient(t, "password")
+	defer closeServer()
+
+	client.EnableDryRun()
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.RedfishCFG.HTTPClient.Get(client.RedfishCFG.BasePath + "/redfish/v1/Systems/1")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// No session is created on the BMC; information is retrieved using basic authentication instead
+	assert.Equal(t, 0, service.created)
+	assert.Equal(t, 2, service.basic)
+
+	basePath := client.RedfishCFG.BasePath
+	expected := []Call{
+		{
+			Method:  http.MethodPost,
+			URL:     basePath + endpointSessions,
+			Body:    `{"Password":"********","UserName":"username"}`,
+			Pending: true,
+		},
+		{Method: http.MethodGet, URL: basePath + "/redfish/v1/Systems/1"},
+		{Method: http.MethodGet, URL: basePath + "/redfish/v1/Systems/1"},
+	}
+	assert.Equal(t, expected, client.DryRunCalls())
+
+	require.NoError(t, client.Close())
+	assert.Empty(t, service.deleted)
+}
@@ -0,0 +1,211 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
+)
+
+// Redfish task states (TaskState) and iDRAC job states (JobState) that indicate an operation has finished.
+const (
+	taskStateCompleted = "Completed"
+	taskStatusCritical = "Critical"
+
+	jobStateCompleted           = "Completed"
+	jobStateCompletedWithErrors = "CompletedWithErrors"
+	jobStateFailed              = "Failed"
+)
+
+// failedTaskStates holds the states of Redfish tasks and iDRAC jobs that finished without completing successfully.
+var failedTaskStates = map[string]bool{
+	"Exception":                 true,
+	"Killed":                    true,
+	"Cancelled":                 true,
+	"Interrupted":               true,
+	jobStateFailed:              true,
+	jobStateCompletedWithErrors: true,
+}
+
+// TaskMessage is a message reported by a Redfish task, an iDRAC job or a Redfish error response.
+type TaskMessage struct {
+	MessageID  string `json:"MessageId"`
+	Message    string `json:"Message"`
+	Severity   string `json:"Severity,omitempty"`
+	Resolution string `json:"Resolution,omitempty"`
+}
+
+func (m TaskMessage) String() string {
+	message := m.Message
+	if m.MessageID != "" {
+		message = fmt.Sprintf("%s (%s)", message, m.MessageID)
+	}
+
+	if m.Resolution != "" {
+		message = fmt.Sprintf("%s %s", message, m.Resolution)
+	}
+
+	return message
+}
+
+// taskResource holds the fields of a Redfish Task resource and of an iDRAC Job resource used to follow the progress
+// of an asynchronous operation.
+type taskResource struct {
+	OdataID         string        `json:"@odata.id"`
+	ID              string        `json:"Id"`
+	TaskMonitor     string        `json:"TaskMonitor"`
+	TaskState       string        `json:"TaskState"`
+	TaskStatus      string        `json:"TaskStatus"`
+	PercentComplete *int          `json:"PercentComplete"`
+	Messages        []TaskMessage `json:"Messages"`
+
+	JobState  string `json:"JobState"`
+	Message   string `json:"Message"`
+	MessageID string `json:"MessageId"`
+}
+
+// state returns the state of the task or job and whether it has finished.
+func (t taskResource) state() (string, bool) {
+	switch {
+	case t.TaskState != "":
+		return t.TaskState, t.TaskState == taskStateCompleted || failedTaskStates[t.TaskState]
+	case t.JobState != "":
+		return t.JobState, t.JobState == jobStateCompleted || failedTaskStates[t.JobState]
+	default:
+		return "", false
+	}
+}
+
+// messages returns the messages reported by the task or job.
+func (t taskResource) messages() []TaskMessage {
+	messages := append([]TaskMessage{}, t.Messages...)
+	if t.Message != "" {
+		messages = append(messages, TaskMessage{MessageID: t.MessageID, Message: t.Message})
+	}
+
+	return messages
+}
+
+// WaitForTask follows the task started by an asynchronous Redfish request, e.g. one that was answered with "202
+// Accepted", and polls it until it finishes. The task is located using the Location header of the response, or the
+// TaskMonitor or resource URI returned in its body; both Redfish tasks and iDRAC jobs are supported. An ErrTaskFailed
+// error carrying the task's messages is returned when the task does not complete successfully.
+func (c *Client) WaitForTask(ctx context.Context, httpResp *http.Response) error {
+	uri, err := c.taskURI(httpResp)
+	if err != nil {
+		return err
+	}
+
+	return c.WaitForTaskURI(ctx, uri)
+}
+
+// WaitForTaskURI polls the Redfish task, task monitor or iDRAC job located at uri until it finishes.
+func (c *Client) WaitForTaskURI(ctx context.Context, uri string) error {
+	return c.waitForTaskURI(ctx, uri, c.retryPolicy)
+}
+
+// waitForTaskURI polls the Redfish task, task monitor or iDRAC job located at uri according to policy until it
+// finishes.
+func (c *Client) waitForTaskURI(ctx context.Context, uri string, policy retry.Policy) error {
+	log.Debugf("Waiting for task '%s' to complete.", uri)
+
+	err := c.poll(ctx, policy, fmt.Sprintf("wait for task %s to complete", uri),
+		func(ctx context.Context) (bool, error) {
+			return c.pollTask(ctx, uri)
+		})
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Task '%s' completed successfully.", uri)
+	return nil
+}
+
+// pollTask retrieves the status of a task and reports whether it has finished successfully.
+func (c *Client) pollTask(ctx context.Context, uri string) (bool, error) {
+	httpResp, body, err := c.rawRequest(ctx, http.MethodGet, uri)
+	if err != nil {
+		return false, err
+	}
+
+	switch httpResp.StatusCode {
+	case http.StatusAccepted, http.StatusOK:
+	case http.StatusNoContent:
+		// A task monitor returns the response of the completed operation, which may have no content.
+		return true, nil
+	default:
+		message := fmt.Sprintf("Unable to retrieve task '%s'. BMC responded '%s'.", uri, httpResp.Status)
+		return false, ResponseError(message, body)
+	}
+
+	var task taskResource
+	if len(body) > 0 {
+		if err = json.Unmarshal(body, &task); err != nil {
+			return false, ErrUnrecognizedRedfishResponse{Key: "TaskState"}
+		}
+	}
+
+	state, finished := task.state()
+	if state == "" {
+		// A task monitor keeps responding "202 Accepted" while the task is running and returns the response of the
+		// completed operation afterwards.
+		return httpResp.StatusCode != http.StatusAccepted, nil
+	}
+
+	if !finished {
+		if task.PercentComplete != nil {
+			log.Debugf("Task '%s' is in state '%s' (%d%% complete).", uri, state, *task.PercentComplete)
+		} else {
+			log.Debugf("Task '%s' is in state '%s'.", uri, state)
+		}
+
+		return false, nil
+	}
+
+	if failedTaskStates[state] || task.TaskStatus == taskStatusCritical {
+		return false, ErrTaskFailed{TaskURI: uri, State: state, Messages: task.messages()}
+	}
+
+	return true, nil
+}
+
+// taskURI locates the task started by an asynchronous request.
+func (c *Client) taskURI(httpResp *http.Response) (string, error) {
+	if location := httpResp.Header.Get("Location"); location != "" {
+		return location, nil
+	}
+
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var task taskResource
+	if err = json.Unmarshal(body, &task); err == nil {
+		if task.TaskMonitor != "" {
+			return task.TaskMonitor, nil
+		}
+
+		if task.OdataID != "" {
+			return task.OdataID, nil
+		}
+	}
+
+	return "", ErrRedfishClient{Message: "Unable to locate the task started by the BMC. No task URI was returned."}
+}
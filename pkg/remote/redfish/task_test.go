@@ -0,0 +1,199 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redfish
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
+)
+
+// taskServer serves a sequence of responses for a task URI, repeating the last response once exhausted.
+type taskServer struct {
+	mu        sync.Mutex
+	responses []taskServerResponse
+	polls     int
+}
+
+type taskServerResponse struct {
+	status int
+	body   string
+}
+
+func (s *taskServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := s.responses[len(s.responses)-1]
+	if s.polls < len(s.responses) {
+		resp = s.responses[s.polls]
+	}
+	s.polls++
+
+	w.WriteHeader(resp.status)
+	_, err := w.Write([]byte(resp.body))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// newTaskClient creates a client for a BMC serving the supplied task responses.
+func newTaskClient(t *testing.T, policy retry.Policy, responses ...taskServerResponse) (*Client, *taskServer,
+	func()) {
+	t.Helper()
+
+	task := &taskServer{responses: responses}
+	server := httptest.NewServer(task)
+
+	_, client, err := NewClient(server.URL+"/redfish/v1/Systems/1", nil, false, false, "", "", policy)
+	require.NoError(t, err)
+
+	client.Sleep = func(_ time.Duration) {}
+
+	return client, task, server.Close
+}
+
+// acceptedResponse builds a "202 Accepted" response that references a task.
+func acceptedResponse(location string, body string) *http.Response {
+	resp := &http.Response{
+		StatusCode: http.StatusAccepted,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+
+	if location != "" {
+		resp.Header.Set("Location", location)
+	}
+
+	return resp
+}
+
+func TestWaitForTask(t *testing.T) {
+	client, task, closeServer := newTaskClient(t, retryPolicy,
+		taskServerResponse{http.StatusOK, `{"TaskState": "New"}`},
+		taskServerResponse{http.StatusOK, `{"TaskState": "Running", "PercentComplete": 50}`},
+		taskServerResponse{http.StatusOK, `{"TaskState": "Completed", "TaskStatus": "OK"}`},
+	)
+	defer closeServer()
+
+	err := client.WaitForTask(context.Background(), acceptedResponse("/redfish/v1/TaskService/Tasks/1", ""))
+	require.NoError(t, err)
+	assert.Equal(t, 3, task.polls)
+}
+
+func TestWaitForTaskMonitor(t *testing.T) {
+	client, task, closeServer := newTaskClient(t, retryPolicy,
+		taskServerResponse{http.StatusAccepted, ""},
+		taskServerResponse{http.StatusNoContent, ""},
+	)
+	defer closeServer()
+
+	// The task monitor is returned in the body of the response when no Location header is present
+	resp := acceptedResponse("", `{"@odata.id": "/redfish/v1/TaskService/Tasks/1", "TaskMonitor": "/taskmon/1"}`)
+
+	err := client.WaitForTask(context.Background(), resp)
+	require.NoError(t, err)
+	assert.Equal(t, 2, task.polls)
+}
+
+func TestWaitForTaskIDRACJob(t *testing.T) {
+	client, _, closeServer := newTaskClient(t, retryPolicy,
+		taskServerResponse{http.StatusOK, `{"Id": "JID_001", "JobState": "Scheduled"}`},
+		taskServerResponse{http.StatusOK, `{"Id": "JID_001", "JobState": "Completed"}`},
+	)
+	defer closeServer()
+
+	location := "/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_001"
+	err := client.WaitForTask(context.Background(), acceptedResponse(location, ""))
+	assert.NoError(t, err)
+}
+
+func TestWaitForTaskFailed(t *testing.T) {
+	client, _, closeServer := newTaskClient(t, retryPolicy,
+		taskServerResponse{http.StatusOK, `{"TaskState": "Exception", "Messages": [{"MessageId": "Base.1.5.InternalError",
+			"Message": "The request failed due to an internal service error.", "Severity": "Critical"}]}`},
+	)
+	defer closeServer()
+
+	location := "/redfish/v1/TaskService/Tasks/1"
+	err := client.WaitForTask(context.Background(), acceptedResponse(location, ""))
+
+	expected := ErrTaskFailed{
+		TaskURI: location,
+		State:   "Exception",
+		Messages: []TaskMessage{{
+			MessageID: "Base.1.5.InternalError",
+			Message:   "The request failed due to an internal service error.",
+			Severity:  "Critical",
+		}},
+	}
+	assert.Equal(t, expected, err)
+}
+
+func TestWaitForTaskIDRACJobFailed(t *testing.T) {
+	client, _, closeServer := newTaskClient(t, retryPolicy,
+		taskServerResponse{http.StatusOK, `{"Id": "JID_001", "JobState": "Failed", "MessageId": "SYS051",
+			"Message": "Unable to apply the configuration."}`},
+	)
+	defer closeServer()
+
+	location := "/redfish/v1/Managers/iDRAC.Embedded.1/Jobs/JID_001"
+	err := client.WaitForTask(context.Background(), acceptedResponse(location, ""))
+
+	taskErr, ok := err.(ErrTaskFailed)
+	require.True(t, ok)
+	assert.Equal(t, []TaskMessage{{MessageID: "SYS051", Message: "Unable to apply the configuration."}},
+		taskErr.Messages)
+	assert.Contains(t, err.Error(), "Unable to apply the configuration. (SYS051)")
+}
+
+func TestWaitForTaskTimeout(t *testing.T) {
+	client, task, closeServer := newTaskClient(t, timeoutPolicy,
+		taskServerResponse{http.StatusOK, `{"TaskState": "Running"}`})
+	defer closeServer()
+
+	err := client.WaitForTask(context.Background(), acceptedResponse("/redfish/v1/TaskService/Tasks/1", ""))
+	assert.IsType(t, retry.ErrTimeout{}, err)
+
+	task.mu.Lock()
+	defer task.mu.Unlock()
+	assert.True(t, task.polls > 0)
+}
+
+func TestWaitForTaskNotFound(t *testing.T) {
+	client, _, closeServer := newTaskClient(t, retryPolicy, taskServerResponse{http.StatusNotFound, ""})
+	defer closeServer()
+
+	err := client.WaitForTask(context.Background(), acceptedResponse("/redfish/v1/TaskService/Tasks/1", ""))
+	_, ok := err.(ErrRedfishClient)
+	assert.True(t, ok)
+}
+
+func TestWaitForTaskMissingURI(t *testing.T) {
+	_, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	err = client.WaitForTask(context.Background(), acceptedResponse("", "{}"))
+	_, ok := err.(ErrRedfishClient)
+	assert.True(t, ok)
+}
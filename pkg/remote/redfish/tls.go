@@ -0,0 +1,118 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"io/ioutil"
+	"strings"
+)
+
+// TLSOptions describes how the certificates of BMCs are verified and which certificate is presented to BMCs that
+// require client authentication.
+type TLSOptions struct {
+	// Insecure disables the verification of BMC certificates.
+	Insecure bool
+
+	// CABundle is the path of a PEM file of the CA certificates trusted to sign BMC certificates. The CAs trusted by
+	// the system are used when it is empty.
+	CABundle string
+
+	// ClientCertificate and ClientKey are the paths of the PEM certificate and key presented to BMCs.
+	ClientCertificate string
+	ClientKey         string
+
+	// PinnedFingerprints are the SHA-256 fingerprints of the certificates BMCs may present, in hexadecimal with
+	// optional colons, e.g. as printed by "openssl x509 -fingerprint -sha256". When set, a BMC is trusted only when it
+	// presents a certificate with one of these fingerprints, whether or not a trusted CA signed it, so that
+	// self-signed BMC certificates can be verified. Neither the chain nor the host name of a pinned certificate is
+	// verified, so pins are not combined with CABundle.
+	PinnedFingerprints []string
+}
+
+// NewTLSConfig builds the TLS configuration used by Redfish clients from TLS options, reading the CA bundle and client
+// certificate they refer to.
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: opts.Insecure, //nolint:gosec
+	}
+
+	if opts.CABundle != "" {
+		pem, err := ioutil.ReadFile(opts.CABundle)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, ErrInvalidCABundle{Path: opts.CABundle}
+		}
+	}
+
+	if opts.ClientCertificate != "" || opts.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertificate, opts.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(opts.PinnedFingerprints) > 0 {
+		pins := make(map[string]bool, len(opts.PinnedFingerprints))
+		for _, fingerprint := range opts.PinnedFingerprints {
+			pin, err := normalizeFingerprint(fingerprint)
+			if err != nil {
+				return nil, err
+			}
+
+			pins[pin] = true
+		}
+
+		// The pins replace the verification of the certificate chain, which Go performs before calling
+		// VerifyPeerCertificate unless it is skipped.
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return ErrCertificateNotPinned{}
+			}
+
+			sum := sha256.Sum256(rawCerts[0])
+			fingerprint := hex.EncodeToString(sum[:])
+			if !pins[fingerprint] {
+				return ErrCertificateNotPinned{Fingerprint: fingerprint}
+			}
+
+			return nil
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// normalizeFingerprint converts a SHA-256 fingerprint to lower case hexadecimal without colons.
+func normalizeFingerprint(fingerprint string) (string, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+
+	decoded, err := hex.DecodeString(normalized)
+	if err != nil || len(decoded) != sha256.Size {
+		return "", ErrInvalidFingerprint{Fingerprint: fingerprint}
+	}
+
+	return normalized, nil
+}
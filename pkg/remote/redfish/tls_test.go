@@ -0,0 +1,184 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package redfish
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTLSServer starts an HTTPS server whose certificate is signed by no trusted CA.
+func newTLSServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// getWithTLS performs a GET request on a server through a Redfish client configured with TLS options.
+func getWithTLS(t *testing.T, server *httptest.Server, opts TLSOptions) error {
+	t.Helper()
+
+	tlsConfig, err := NewTLSConfig(opts)
+	require.NoError(t, err)
+
+	_, client, err := NewClient(server.URL+"/redfish/v1/Systems/1", tlsConfig, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	resp, err := client.RedfishCFG.HTTPClient.Get(server.URL)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// writePEM writes PEM blocks of the supplied type to a file in dir and returns its path.
+func writePEM(t *testing.T, dir, name, blockType string, blocks ...[]byte) string {
+	t.Helper()
+
+	var data []byte
+	for _, block := range blocks {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: block})...)
+	}
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+
+	return path
+}
+
+func TestNewTLSConfigCABundle(t *testing.T) {
+	server := newTLSServer(t)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "airshipctl-tls-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = getWithTLS(t, server, TLSOptions{})
+	assert.Error(t, err)
+
+	caBundle := writePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw)
+	assert.NoError(t, getWithTLS(t, server, TLSOptions{CABundle: caBundle}))
+
+	_, err = NewTLSConfig(TLSOptions{CABundle: writePEM(t, dir, "empty.pem", "CERTIFICATE")})
+	assert.Equal(t, ErrInvalidCABundle{Path: filepath.Join(dir, "empty.pem")}, err)
+
+	_, err = NewTLSConfig(TLSOptions{CABundle: filepath.Join(dir, "missing.pem")})
+	assert.Error(t, err)
+}
+
+func TestNewTLSConfigInsecure(t *testing.T) {
+	server := newTLSServer(t)
+	defer server.Close()
+
+	assert.NoError(t, getWithTLS(t, server, TLSOptions{Insecure: true}))
+}
+
+func TestNewTLSConfigPinnedFingerprints(t *testing.T) {
+	server := newTLSServer(t)
+	defer server.Close()
+
+	sum := sha256.Sum256(server.Certificate().Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	// Fingerprints are accepted in the colon separated, upper case form printed by openssl
+	var octets []string
+	for i := 0; i < len(fingerprint); i += 2 {
+		octets = append(octets, strings.ToUpper(fingerprint[i:i+2]))
+	}
+
+	err := getWithTLS(t, server, TLSOptions{PinnedFingerprints: []string{strings.Join(octets, ":")}})
+	assert.NoError(t, err)
+
+	otherFingerprint := strings.Repeat("0", 64)
+	err = getWithTLS(t, server, TLSOptions{PinnedFingerprints: []string{otherFingerprint}})
+
+	var notPinned ErrCertificateNotPinned
+	require.True(t, errors.As(err, &notPinned), "unexpected error: %v", err)
+	assert.Equal(t, fingerprint, notPinned.Fingerprint)
+
+	_, err = NewTLSConfig(TLSOptions{PinnedFingerprints: []string{"AB:CD"}})
+	assert.Equal(t, ErrInvalidFingerprint{Fingerprint: "AB:CD"}, err)
+}
+
+func TestNewTLSConfigClientCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airshipctl-tls-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "airshipctl"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	clientCert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	opts := TLSOptions{CABundle: writePEM(t, dir, "ca.pem", "CERTIFICATE", server.Certificate().Raw)}
+	assert.Error(t, getWithTLS(t, server, opts))
+
+	opts.ClientCertificate = writePEM(t, dir, "client.pem", "CERTIFICATE", der)
+	opts.ClientKey = writePEM(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDER)
+	assert.NoError(t, getWithTLS(t, server, opts))
+
+	opts.ClientKey = filepath.Join(dir, "missing.pem")
+	_, err = NewTLSConfig(opts)
+	assert.Error(t, err)
+}
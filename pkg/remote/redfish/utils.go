@@ -19,7 +19,6 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 
 	redfishAPI "opendev.org/airship/go-redfish/api"
 	redfishClient "opendev.org/airship/go-redfish/client"
@@ -146,15 +145,41 @@ func IsIDInList(idRefList []redfishClient.IdRef, id string) bool {
 
 // GetVirtualMediaID retrieves the ID of a Redfish virtual media resource if it supports type "CD" or "DVD".
 func GetVirtualMediaID(ctx context.Context, api redfishAPI.RedfishAPI, systemID string) (string, string, error) {
+	media, err := findVirtualMedia(ctx, api, systemID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if media.ID == "" {
+		return "", "", ErrRedfishClient{Message: fmt.Sprintf("Manager '%s' does not have virtual media type CD or DVD.",
+			media.ManagerID)}
+	}
+
+	return media.ID, media.Type, nil
+}
+
+// virtualMedia describes a Redfish virtual media resource that supports type "CD" or "DVD".
+type virtualMedia struct {
+	ID        string
+	Type      string
+	ManagerID string
+	Inserted  bool
+	Image     string
+}
+
+// findVirtualMedia searches the manager of a Redfish system for a virtual media resource that supports type "CD" or
+// "DVD". The ID of the manager is always returned; the remaining fields are empty when no compatible virtual media
+// resource is found.
+func findVirtualMedia(ctx context.Context, api redfishAPI.RedfishAPI, systemID string) (virtualMedia, error) {
 	log.Debug("Searching for compatible media types.")
 	managerID, err := GetManagerID(ctx, api, systemID)
 	if err != nil {
-		return "", "", err
+		return virtualMedia{}, err
 	}
 
 	mediaCollection, httpResp, err := api.ListManagerVirtualMedia(ctx, managerID)
 	if err = ScreenRedfishError(httpResp, err); err != nil {
-		return "", "", err
+		return virtualMedia{ManagerID: managerID}, err
 	}
 
 	for _, mediaURI := range mediaCollection.Members {
@@ -163,24 +188,30 @@ func GetVirtualMediaID(ctx context.Context, api redfishAPI.RedfishAPI, systemID
 
 		vMedia, httpResp, err := api.GetManagerVirtualMedia(ctx, managerID, mediaID)
 		if err = ScreenRedfishError(httpResp, err); err != nil {
-			return "", "", err
+			return virtualMedia{ManagerID: managerID}, err
 		}
 
 		for _, mediaType := range vMedia.MediaTypes {
 			if mediaType == "CD" || mediaType == "DVD" {
 				log.Debugf("Found virtual media type '%s' with ID '%s' on manager '%s'.", mediaType,
 					mediaID, managerID)
-				return mediaID, mediaType, nil
+				return virtualMedia{
+					ID:        mediaID,
+					Type:      mediaType,
+					ManagerID: managerID,
+					Inserted:  vMedia.Inserted != nil && *vMedia.Inserted,
+					Image:     vMedia.Image,
+				}, nil
 			}
 		}
 	}
 
-	return "", "", ErrRedfishClient{Message: fmt.Sprintf("Manager '%s' does not have virtual media type CD or DVD.",
-		managerID)}
+	return virtualMedia{ManagerID: managerID}, nil
 }
 
 // ScreenRedfishError provides a detailed error message for end user consumption by inspecting all Redfish client
-// responses and errors.
+// responses and errors. When the BMC response carries a message of a Redfish message registry, the error of its
+// category is returned, e.g. ErrBMCAuthentication; see DecodeBMCError.
 func ScreenRedfishError(httpResp *http.Response, clientErr error) error {
 	if httpResp == nil {
 		return ErrRedfishClient{
@@ -219,7 +250,11 @@ func ScreenRedfishError(httpResp *http.Response, clientErr error) error {
 		log.Debug("Unable to decode BMC response.")
 	}
 
-	// Attempt to decode the BMC response from the raw HTTP response
+	// Attempt to decode the messages of the BMC response from the raw HTTP response
+	if bmcErr := DecodeBMCError(finalError.Message, oAPIErr.Body()); bmcErr != nil {
+		return bmcErr
+	}
+
 	if bmcResponse, err := DecodeRawError(oAPIErr.Body()); err == nil {
 		finalError.Message = fmt.Sprintf("%s BMC responded: '%s'", finalError.Message, bmcResponse)
 	} else {
@@ -244,34 +279,32 @@ func getBasePath(redfishURL string) (string, error) {
 		return "", ErrRedfishClient{Message: fmt.Sprintf("Redfish URL malformed %s", err.Error())}
 	}
 
-	baseURL := fmt.Sprintf("%s://%s", parsedURL.Scheme, parsedURL.Host)
+	// Schemes follow metal3, e.g. redfish+http, idrac-virtualmedia+https or ilo5-redfish, where a driver without an
+	// explicit transport is reached over HTTPS.
 	schemeSplit := strings.Split(parsedURL.Scheme, redfishURLSchemeSeparator)
-	if len(schemeSplit) > 1 {
-		baseURL = fmt.Sprintf("%s://%s", schemeSplit[len(schemeSplit)-1], parsedURL.Host)
+	transport := schemeSplit[len(schemeSplit)-1]
+	if transport != "http" && transport != "https" {
+		transport = "https"
 	}
 
-	return baseURL, nil
+	return fmt.Sprintf("%s://%s", transport, parsedURL.Host), nil
 }
 
 func (c Client) waitForPowerState(ctx context.Context, desiredState redfishClient.PowerState) error {
 	log.Debugf("Waiting for node '%s' to reach power state '%s'.", c.nodeID, desiredState)
 
-	for retry := 0; retry <= c.systemActionRetries; retry++ {
-		system, httpResp, err := c.RedfishAPI.GetSystem(ctx, c.NodeID())
-		if err = ScreenRedfishError(httpResp, err); err != nil {
-			return err
-		}
-
-		if system.PowerState == desiredState {
-			log.Debugf("Node '%s' reached power state '%s'.", c.nodeID, desiredState)
-			return nil
-		}
+	return c.poll(ctx, c.retryPolicy, fmt.Sprintf("reach desired power state %s", desiredState),
+		func(ctx context.Context) (bool, error) {
+			system, httpResp, err := c.RedfishAPI.GetSystem(ctx, c.NodeID())
+			if err = ScreenRedfishError(httpResp, err); err != nil {
+				return false, err
+			}
 
-		c.Sleep(time.Duration(c.systemRebootDelay) * time.Second)
-	}
+			if system.PowerState == desiredState {
+				log.Debugf("Node '%s' reached power state '%s'.", c.nodeID, desiredState)
+				return true, nil
+			}
 
-	return ErrOperationRetriesExceeded{
-		What:    fmt.Sprintf("reach desired power state %s", desiredState),
-		Retries: c.systemActionRetries,
-	}
+			return false, nil
+		})
 }
@@ -17,7 +17,7 @@ package dell
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -26,7 +26,9 @@ import (
 	redfishClient "opendev.org/airship/go-redfish/client"
 
 	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
 )
 
 const (
@@ -40,7 +42,7 @@ const (
 	    "ShutdownType": "NoReboot",
 	    "ImportBuffer": "<SystemConfiguration>
 	                       <Component FQDD=\"iDRAC.Embedded.1\">
-	                         <Attribute Name=\"ServerBoot.1#BootOnce\">Enabled</Attribute>
+	                         <Attribute Name=\"ServerBoot.1#BootOnce\">%s</Attribute>
 	                         <Attribute Name=\"ServerBoot.1#FirstBootDevice\">VCD-DVD</Attribute>
 	                       </Component>
 	                     </SystemConfiguration>"
@@ -55,23 +57,25 @@ type Client struct {
 	RedfishCFG *redfishClient.Configuration
 }
 
-type iDRACAPIRespErr struct {
-	Err iDRACAPIErr `json:"error"`
+// SetBootSourceByType sets the boot source of the ephemeral node to a virtual CD, "VCD-DVD".
+func (c *Client) SetBootSourceByType(ctx context.Context) error {
+	return c.setVirtualCDBoot(ctx, false)
 }
 
-type iDRACAPIErr struct {
-	ExtendedInfo []iDRACAPIExtendedInfo `json:"@Message.ExtendedInfo"`
-	Code         string                 `json:"code"`
-	Message      string                 `json:"message"`
-}
+// SetBootOverride instructs a host to boot from the supplied device, either on its next boot only or persistently.
+// The standard Redfish boot source "Cd" does not select the iDRAC virtual CD, which is instead selected using the
+// iDRAC actions API.
+func (c *Client) SetBootOverride(ctx context.Context, override boot.Override) error {
+	if override.Device != boot.DeviceCD {
+		return c.Client.SetBootOverride(ctx, override)
+	}
 
-type iDRACAPIExtendedInfo struct {
-	Message    string `json:"Message"`
-	Resolution string `json:"Resolution,omitempty"`
+	return c.setVirtualCDBoot(ctx, override.Persistent)
 }
 
-// SetBootSourceByType sets the boot source of the ephemeral node to a virtual CD, "VCD-DVD".
-func (c *Client) SetBootSourceByType(ctx context.Context) error {
+// setVirtualCDBoot sets the boot source of a host to a virtual CD, "VCD-DVD", either on its next boot only or
+// persistently.
+func (c *Client) setVirtualCDBoot(ctx context.Context, persistent bool) error {
 	log.Debug("Setting boot device to 'VCD-DVD'.")
 	managerID, err := redfish.GetManagerID(ctx, c.RedfishAPI, c.NodeID())
 	if err != nil {
@@ -83,7 +87,13 @@ func (c *Client) SetBootSourceByType(ctx context.Context) error {
 	// actions API. The request is made below using the same HTTP client used by the Redfish API and exposed by the
 	// standard airshipctl Redfish client. Only iDRAC 9 >= 3.3 is supports this endpoint.
 	url := fmt.Sprintf(endpointImportSysCFG, c.RedfishCFG.BasePath, managerID)
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(vCDBootRequestBody))
+	bootOnce := "Enabled"
+	if persistent {
+		bootOnce = "Disabled"
+	}
+
+	body := fmt.Sprintf(vCDBootRequestBody, bootOnce)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(body))
 	if err != nil {
 		return err
 	}
@@ -96,43 +106,48 @@ func (c *Client) SetBootSourceByType(ctx context.Context) error {
 	}
 
 	httpResp, err := c.RedfishCFG.HTTPClient.Do(req)
+	if err != nil {
+		return redfish.ErrRedfishClient{Message: fmt.Sprintf("Unable to set boot device. %v", err)}
+	}
+	defer httpResp.Body.Close()
+
+	// In dry run the request is recorded rather than sent, so no job is started on the iDRAC.
+	if c.DryRun() {
+		return nil
+	}
+
 	if httpResp.StatusCode != http.StatusAccepted {
-		body, ok := ioutil.ReadAll(httpResp.Body)
-		if ok != nil {
-			log.Debugf("Malformed iDRAC response: %s", body)
-			return redfish.ErrRedfishClient{Message: "Unable to set boot device. Malformed iDRAC response."}
+		body, err := ioutil.ReadAll(httpResp.Body)
+		if err != nil {
+			return redfish.ErrRedfishClient{Message: fmt.Sprintf("Unable to set boot device. %v", err)}
 		}
 
-		var iDRACResp iDRACAPIRespErr
-		ok = json.Unmarshal(body, &iDRACResp)
-		if ok != nil {
-			log.Debugf("Malformed iDRAC response: %s", body)
-			return redfish.ErrRedfishClient{Message: "Unable to set boot device. Malformed iDrac response."}
-		}
+		return redfish.ResponseError(fmt.Sprintf("Unable to set boot device. iDRAC responded '%s'.", httpResp.Status),
+			body)
+	}
 
-		return redfish.ErrRedfishClient{
-			Message: fmt.Sprintf("Unable to set boot device. %s", iDRACResp.Err.ExtendedInfo[0]),
-		}
-	} else if err != nil {
-		return redfish.ErrRedfishClient{Message: fmt.Sprintf("Unable to set boot device. %v", err)}
+	// The iDRAC applies the configuration asynchronously using a job. Wait for the job to complete so that the host
+	// is not rebooted before the boot device has been changed.
+	if err = c.WaitForTask(ctx, httpResp); err != nil {
+		log.Debugf("Failed to set boot device of node '%s'.", c.NodeID())
+		return err
 	}
 
 	log.Debug("Successfully set boot device.")
-	defer httpResp.Body.Close()
 
 	return nil
 }
 
 // NewClient returns a client with the capability to make Redfish requests.
 func NewClient(redfishURL string,
-	insecure bool,
+	tlsConfig *tls.Config,
 	useProxy bool,
+	sessionAuth bool,
 	username string,
 	password string,
-	systemActionRetries int,
-	systemRebootDelay int) (context.Context, *Client, error) {
-	ctx, genericClient, err := redfish.NewClient(redfishURL, insecure, useProxy, username, password,
-		systemActionRetries, systemRebootDelay)
+	retryPolicy retry.Policy) (context.Context, *Client, error) {
+	ctx, genericClient, err := redfish.NewClient(redfishURL, tlsConfig, useProxy, sessionAuth, username, password,
+		retryPolicy)
 	if err != nil {
 		return ctx, nil, err
 	}
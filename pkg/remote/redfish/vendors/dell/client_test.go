@@ -15,39 +15,46 @@
 package dell
 
 import (
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	redfishMocks "opendev.org/airship/go-redfish/api/mocks"
 	redfishClient "opendev.org/airship/go-redfish/client"
-)
 
-const (
-	redfishURL          = "redfish+https://localhost/Systems/System.Embedded.1"
-	systemActionRetries = 0
-	systemRebootDelay   = 0
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
+	testutil "opendev.org/airship/airshipctl/testutil/redfishutils/helpers"
 )
 
+const redfishURL = "redfish+https://localhost/Systems/System.Embedded.1"
+
+var retryPolicy = retry.Policy{Timeout: 10 * time.Second}
+
 func TestNewClient(t *testing.T) {
-	_, _, err := NewClient(redfishURL, false, false, "username", "password", systemActionRetries, systemRebootDelay)
+	_, _, err := NewClient(redfishURL, nil, false, false, "username", "password", retryPolicy)
 	assert.NoError(t, err)
 }
 
 func TestNewClientDefaultValues(t *testing.T) {
-	sysActRetr := 222
-	sysRebDel := 555
-	_, c, err := NewClient(redfishURL, false, false, "", "", sysActRetr, sysRebDel)
-	assert.Equal(t, c.SystemActionRetries(), sysActRetr)
-	assert.Equal(t, c.SystemRebootDelay(), sysRebDel)
+	policy := retry.Policy{Timeout: 222 * time.Second, Interval: 5 * time.Second}
+	_, c, err := NewClient(redfishURL, nil, false, false, "", "", policy)
+	assert.Equal(t, c.RetryPolicy(), policy)
 	assert.NoError(t, err)
 }
 func TestSetBootSourceByTypeGetSystemError(t *testing.T) {
 	m := &redfishMocks.RedfishAPI{}
 	defer m.AssertExpectations(t)
 
-	ctx, client, err := NewClient(redfishURL, false, false, "", "", systemActionRetries, systemRebootDelay)
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
 	assert.NoError(t, err)
 
 	// Mock redfish get system request
@@ -60,3 +67,229 @@ func TestSetBootSourceByTypeGetSystemError(t *testing.T) {
 	err = client.SetBootSourceByType(ctx)
 	assert.Error(t, err)
 }
+
+func TestSetBootSourceByTypeWaitsForJob(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	jobURI := "/redfish/v1/TaskService/Tasks/JID_001"
+	var jobPolls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			w.Header().Set("Location", jobURI)
+			w.WriteHeader(http.StatusAccepted)
+		case r.URL.Path == jobURI:
+			jobPolls++
+			state := "Running"
+			if jobPolls > 1 {
+				state = "Completed"
+			}
+			fmt.Fprintf(w, `{"Id": "JID_001", "JobState": "%s"}`, state)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx, client, err := NewClient(server.URL+"/redfish/v1/Systems/System.Embedded.1", nil, false, false, "", "",
+		retryPolicy)
+	require.NoError(t, err)
+
+	m.On("GetSystem", ctx, client.NodeID()).Times(1).Return(testutil.GetTestSystem(),
+		&http.Response{StatusCode: 200}, nil)
+
+	// Replace normal API client with mocked API client
+	client.RedfishAPI = m
+	client.Sleep = func(_ time.Duration) {}
+
+	err = client.SetBootSourceByType(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, jobPolls)
+}
+
+func TestSetBootSourceByTypeJobFailed(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	jobURI := "/redfish/v1/TaskService/Tasks/JID_001"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Header().Set("Location", jobURI)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		fmt.Fprint(w, `{"Id": "JID_001", "JobState": "Failed", "MessageId": "SYS055", "Message": "Import failed."}`)
+	}))
+	defer server.Close()
+
+	ctx, client, err := NewClient(server.URL+"/redfish/v1/Systems/System.Embedded.1", nil, false, false, "", "",
+		retryPolicy)
+	require.NoError(t, err)
+
+	m.On("GetSystem", ctx, client.NodeID()).Times(1).Return(testutil.GetTestSystem(),
+		&http.Response{StatusCode: 200}, nil)
+	client.RedfishAPI = m
+
+	err = client.SetBootSourceByType(ctx)
+	_, ok := err.(redfish.ErrTaskFailed)
+	assert.True(t, ok)
+}
+
+func TestSetBootSourceByTypeImportRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected interface{}
+	}{
+		{
+			name: "RegistryMessage",
+			response: `{"error": {"@Message.ExtendedInfo": [{"MessageId": "IDRAC.2.5.LIC501", "Severity": ` +
+				`"Critical", "Message": "A required license is missing or expired."}]}}`,
+			expected: redfish.ErrBMCUnsupported{},
+		},
+		{
+			name:     "NoExtendedInfo",
+			response: `{"error": {"@Message.ExtendedInfo": []}}`,
+			expected: redfish.ErrRedfishClient{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			m := &redfishMocks.RedfishAPI{}
+			defer m.AssertExpectations(t)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, tt.response)
+			}))
+			defer server.Close()
+
+			ctx, client, err := NewClient(server.URL+"/redfish/v1/Systems/System.Embedded.1", nil, false, false,
+				"", "", retryPolicy)
+			require.NoError(t, err)
+
+			m.On("GetSystem", ctx, client.NodeID()).Times(1).Return(testutil.GetTestSystem(),
+				&http.Response{StatusCode: 200}, nil)
+			client.RedfishAPI = m
+
+			err = client.SetBootSourceByType(ctx)
+			assert.IsType(t, tt.expected, err)
+			assert.Contains(t, err.Error(), "Unable to set boot device. iDRAC responded '400 Bad Request'.")
+		})
+	}
+}
+
+func TestSetBootSourceByTypeDryRun(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	var posts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts++
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx, client, err := NewClient(server.URL+"/redfish/v1/Systems/System.Embedded.1", nil, false, false, "", "",
+		retryPolicy)
+	require.NoError(t, err)
+
+	m.On("GetSystem", ctx, client.NodeID()).Times(1).Return(testutil.GetTestSystem(),
+		&http.Response{StatusCode: 200}, nil)
+	client.RedfishAPI = m
+	client.EnableDryRun()
+
+	require.NoError(t, client.SetBootSourceByType(ctx))
+
+	// The configuration is not imported and no job is waited for
+	assert.Equal(t, 0, posts)
+
+	calls := client.DryRunCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, http.MethodPost, calls[0].Method)
+	assert.Contains(t, calls[0].URL, "EID_674_Manager.ImportSystemConfiguration")
+	assert.True(t, calls[0].Pending)
+}
+
+func TestSetBootOverrideVirtualCD(t *testing.T) {
+	tests := []struct {
+		name     string
+		override boot.Override
+		bootOnce string
+	}{
+		{
+			name:     "Once",
+			override: boot.Override{Device: boot.DeviceCD},
+			bootOnce: "Enabled",
+		},
+		{
+			name:     "Persistent",
+			override: boot.Override{Device: boot.DeviceCD, Persistent: true},
+			bootOnce: "Disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			m := &redfishMocks.RedfishAPI{}
+			defer m.AssertExpectations(t)
+
+			var requestBody string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					body, err := ioutil.ReadAll(r.Body)
+					if err != nil {
+						panic(err)
+					}
+
+					requestBody = string(body)
+					w.Header().Set("Location", "/redfish/v1/TaskService/Tasks/JID_001")
+					w.WriteHeader(http.StatusAccepted)
+					return
+				}
+
+				fmt.Fprint(w, `{"Id": "JID_001", "JobState": "Completed"}`)
+			}))
+			defer server.Close()
+
+			ctx, client, err := NewClient(server.URL+"/redfish/v1/Systems/System.Embedded.1", nil, false, false,
+				"", "", retryPolicy)
+			require.NoError(t, err)
+
+			m.On("GetSystem", ctx, client.NodeID()).Times(1).Return(testutil.GetTestSystem(),
+				&http.Response{StatusCode: 200}, nil)
+			client.RedfishAPI = m
+
+			require.NoError(t, client.SetBootOverride(ctx, tt.override))
+			assert.Contains(t, requestBody, `<Attribute Name=\"ServerBoot.1#BootOnce\">`+tt.bootOnce+`</Attribute>`)
+			assert.Contains(t, requestBody, `<Attribute Name=\"ServerBoot.1#FirstBootDevice\">VCD-DVD</Attribute>`)
+		})
+	}
+}
+
+func TestSetBootOverrideStandardBootSource(t *testing.T) {
+	m := &redfishMocks.RedfishAPI{}
+	defer m.AssertExpectations(t)
+
+	ctx, client, err := NewClient(redfishURL, nil, false, false, "", "", retryPolicy)
+	require.NoError(t, err)
+
+	httpResp := &http.Response{StatusCode: 200}
+	m.On("GetSystem", ctx, client.NodeID()).Times(1).Return(testutil.GetTestSystem(), httpResp, nil)
+	m.On("SetSystem", ctx, client.NodeID(), mock.MatchedBy(func(system redfishClient.ComputerSystem) bool {
+		return system.Boot.BootSourceOverrideTarget == redfishClient.BOOTSOURCE_PXE
+	})).Times(1).Return(redfishClient.ComputerSystem{}, httpResp, nil)
+
+	// Standard boot sources are set by the generic Redfish client
+	client.Client.RedfishAPI = m
+
+	assert.NoError(t, client.SetBootOverride(ctx, boot.Override{Device: boot.DevicePXE}))
+}
@@ -0,0 +1,201 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vendors selects the vendor specific Redfish client that matches the BMC of a host.
+package vendors
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	redfishClient "opendev.org/airship/go-redfish/client"
+
+	"opendev.org/airship/airshipctl/pkg/log"
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
+	"opendev.org/airship/airshipctl/pkg/remote/retry"
+)
+
+const (
+	// ClientType is used by other packages as the identifier of the management type that detects the vendor of each
+	// BMC and selects the matching Redfish client.
+	ClientType = "auto"
+
+	endpointServiceRoot = "/redfish/v1/"
+	endpointManagers    = "/redfish/v1/Managers"
+	detectionTimeout    = 30 * time.Second
+)
+
+// vendorClientTypes maps identifiers reported by a BMC, in lower case, to the vendor specific client type that
+// manages it. BMCs that do not report any of these identifiers are managed by the generic Redfish client.
+var vendorClientTypes = map[string]string{
+	"dell": redfishdell.ClientType,
+}
+
+var (
+	detectedClientTypes   = make(map[string]string)
+	detectedClientTypesMu sync.Mutex
+)
+
+type odataID struct {
+	OdataID string `json:"@odata.id"`
+}
+
+type serviceRoot struct {
+	Vendor   string                     `json:"Vendor"`
+	Product  string                     `json:"Product"`
+	Managers odataID                    `json:"Managers"`
+	Oem      map[string]json.RawMessage `json:"Oem"`
+}
+
+type collection struct {
+	Members []odataID `json:"Members"`
+}
+
+type manager struct {
+	Manufacturer string                     `json:"Manufacturer"`
+	Model        string                     `json:"Model"`
+	Oem          map[string]json.RawMessage `json:"Oem"`
+}
+
+// DetectClientType probes the Redfish service root and Manager resources of a BMC and returns the type of the Redfish
+// client that matches its vendor. The generic Redfish client type is returned when the vendor is not recognized or
+// cannot be determined. Successful detections are cached for the BMC address.
+//
+// Detection only retrieves resources, authenticated with basic authentication credentials even when the host is
+// managed using session authentication, so that it never creates a session on the BMC. Hosts are detected before
+// their clients are created, and thus before they can be put in dry run, which must not change the state of the BMC.
+func DetectClientType(redfishURL string, tlsConfig *tls.Config, useProxy bool, username string,
+	password string) (string, error) {
+	ctx, client, err := redfish.NewClient(redfishURL, tlsConfig, useProxy, false, username, password, retry.Policy{})
+	if err != nil {
+		return "", err
+	}
+
+	address := client.RedfishCFG.BasePath
+
+	detectedClientTypesMu.Lock()
+	clientType, cached := detectedClientTypes[address]
+	detectedClientTypesMu.Unlock()
+
+	if cached {
+		log.Debugf("Using cached Redfish client type '%s' for BMC '%s'.", clientType, address)
+		return clientType, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, detectionTimeout)
+	defer cancel()
+
+	clientType, err = detectClientType(ctx, client.RedfishCFG)
+	if err != nil {
+		log.Debugf("Unable to detect the vendor of BMC '%s', falling back to client type '%s': %v", address,
+			redfish.ClientType, err)
+		return redfish.ClientType, nil
+	}
+
+	log.Debugf("Detected Redfish client type '%s' for BMC '%s'.", clientType, address)
+
+	detectedClientTypesMu.Lock()
+	detectedClientTypes[address] = clientType
+	detectedClientTypesMu.Unlock()
+
+	return clientType, nil
+}
+
+// detectClientType matches the identifiers reported by the service root, then those reported by each manager, against
+// the known vendors.
+func detectClientType(ctx context.Context, cfg *redfishClient.Configuration) (string, error) {
+	var root serviceRoot
+	if err := getResource(ctx, cfg, endpointServiceRoot, &root); err != nil {
+		return "", err
+	}
+
+	if clientType, ok := matchVendor(root.Oem, root.Vendor, root.Product); ok {
+		return clientType, nil
+	}
+
+	managersURI := root.Managers.OdataID
+	if managersURI == "" {
+		managersURI = endpointManagers
+	}
+
+	var managers collection
+	if err := getResource(ctx, cfg, managersURI, &managers); err != nil {
+		return "", err
+	}
+
+	for _, member := range managers.Members {
+		var mgr manager
+		if err := getResource(ctx, cfg, member.OdataID, &mgr); err != nil {
+			return "", err
+		}
+
+		if clientType, ok := matchVendor(mgr.Oem, mgr.Manufacturer, mgr.Model); ok {
+			return clientType, nil
+		}
+	}
+
+	return redfish.ClientType, nil
+}
+
+// matchVendor returns the vendor specific client type identified by an OEM extension or a vendor string.
+func matchVendor(oem map[string]json.RawMessage, identifiers ...string) (string, bool) {
+	for vendor := range oem {
+		identifiers = append(identifiers, vendor)
+	}
+
+	for _, identifier := range identifiers {
+		identifier = strings.ToLower(identifier)
+		for vendor, clientType := range vendorClientTypes {
+			if strings.Contains(identifier, vendor) {
+				return clientType, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// getResource retrieves a Redfish resource using the HTTP client configured for the go-redfish API client.
+func getResource(ctx context.Context, cfg *redfishClient.Configuration, uri string, resource interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.BasePath+uri, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("User-Agent", cfg.UserAgent)
+
+	if auth, ok := ctx.Value(redfishClient.ContextBasicAuth).(redfishClient.BasicAuth); ok {
+		req.SetBasicAuth(auth.UserName, auth.Password)
+	}
+
+	httpResp, err := cfg.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return redfish.ErrRedfishClient{
+			Message: fmt.Sprintf("Unable to retrieve '%s'. BMC responded '%s'.", uri, httpResp.Status),
+		}
+	}
+
+	return json.NewDecoder(httpResp.Body).Decode(resource)
+}
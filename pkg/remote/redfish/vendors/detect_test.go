@@ -0,0 +1,144 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vendors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/remote/redfish"
+	redfishdell "opendev.org/airship/airshipctl/pkg/remote/redfish/vendors/dell"
+)
+
+// newTestBMC starts a Redfish service that serves the supplied resources and counts the requests it receives.
+func newTestBMC(t *testing.T, resources map[string]string) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		resource, ok := resources[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(resource))
+		assert.NoError(t, err)
+	}))
+
+	return server, &requests
+}
+
+func TestDetectClientTypeServiceRootVendor(t *testing.T) {
+	server, _ := newTestBMC(t, map[string]string{
+		"/redfish/v1/": `{"Vendor": "Dell", "Managers": {"@odata.id": "/redfish/v1/Managers"}}`,
+	})
+	defer server.Close()
+
+	redfishURL := server.URL + "/redfish/v1/Systems/System.Embedded.1"
+	clientType, err := DetectClientType(redfishURL, nil, false, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, redfishdell.ClientType, clientType)
+}
+
+func TestDetectClientTypeManagerOem(t *testing.T) {
+	server, _ := newTestBMC(t, map[string]string{
+		"/redfish/v1/":                          `{"Managers": {"@odata.id": "/redfish/v1/Managers"}}`,
+		"/redfish/v1/Managers":                  `{"Members": [{"@odata.id": "/redfish/v1/Managers/iDRAC.Embedded.1"}]}`,
+		"/redfish/v1/Managers/iDRAC.Embedded.1": `{"Model": "14G Monolithic", "Oem": {"Dell": {}}}`,
+	})
+	defer server.Close()
+
+	redfishURL := server.URL + "/redfish/v1/Systems/System.Embedded.1"
+	clientType, err := DetectClientType(redfishURL, nil, false, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, redfishdell.ClientType, clientType)
+}
+
+func TestDetectClientTypeGeneric(t *testing.T) {
+	server, requests := newTestBMC(t, map[string]string{
+		"/redfish/v1/":             `{"Vendor": "Contoso", "Managers": {"@odata.id": "/redfish/v1/Managers"}}`,
+		"/redfish/v1/Managers":     `{"Members": [{"@odata.id": "/redfish/v1/Managers/BMC"}]}`,
+		"/redfish/v1/Managers/BMC": `{"Manufacturer": "Contoso", "Oem": {"Contoso": {}}}`,
+	})
+	defer server.Close()
+
+	redfishURL := server.URL + "/redfish/v1/Systems/1"
+	clientType, err := DetectClientType(redfishURL, nil, false, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, redfish.ClientType, clientType)
+	assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+
+	// The detection result is cached for the BMC address
+	clientType, err = DetectClientType(redfishURL, nil, false, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, redfish.ClientType, clientType)
+	assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+}
+
+func TestDetectClientTypeFallback(t *testing.T) {
+	server, requests := newTestBMC(t, map[string]string{})
+	defer server.Close()
+
+	redfishURL := server.URL + "/redfish/v1/Systems/1"
+	clientType, err := DetectClientType(redfishURL, nil, false, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, redfish.ClientType, clientType)
+
+	// Failed detections are not cached
+	_, err = DetectClientType(redfishURL, nil, false, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+}
+
+func TestDetectClientTypeMissingSystemID(t *testing.T) {
+	_, err := DetectClientType("redfish+https://localhost", nil, false, "", "")
+	assert.Error(t, err)
+}
+
+func TestDetectClientTypeBasicAuth(t *testing.T) {
+	var methods []string
+	var basicAuth int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if username, password, ok := r.BasicAuth(); ok && username == "username" && password == "password" {
+			basicAuth++
+		}
+
+		if r.URL.Path != "/redfish/v1/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, err := w.Write([]byte(`{"Vendor": "Dell"}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	redfishURL := server.URL + "/redfish/v1/Systems/System.Embedded.1"
+	clientType, err := DetectClientType(redfishURL, nil, false, "username", "password")
+	require.NoError(t, err)
+	assert.Equal(t, redfishdell.ClientType, clientType)
+
+	// No session is created, only resources are retrieved
+	assert.Equal(t, []string{http.MethodGet}, methods)
+	assert.Equal(t, 1, basicAuth)
+}
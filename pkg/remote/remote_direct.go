@@ -15,15 +15,84 @@
 package remote
 
 import (
+	"path/filepath"
+	"strings"
+
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/environment"
 	"opendev.org/airship/airshipctl/pkg/log"
 
+	"opendev.org/airship/airshipctl/pkg/remote/isoserver"
 	"opendev.org/airship/airshipctl/pkg/remote/power"
 )
 
-// DoRemoteDirect bootstraps the ephemeral node.
-func (b baremetalHost) DoRemoteDirect(settings *environment.AirshipCTLSettings) error {
+// ServeEphemeralISO starts the HTTP server that serves the ephemeral ISO image when it is enabled by the isoServer
+// options of the remote direct bootstrap configuration, and returns nil otherwise. The server must keep running until
+// the ephemeral host has booted from the image. In dry run, the image is not served; the returned server only provides
+// the URL the image would be served at.
+func ServeEphemeralISO(settings *environment.AirshipCTLSettings, dryRun bool) (*isoserver.Server, error) {
+	bootstrapSettings, err := settings.Config.CurrentContextBootstrapInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	if bootstrapSettings.RemoteDirect == nil || bootstrapSettings.RemoteDirect.IsoServer == nil {
+		return nil, nil
+	}
+
+	serverConfig := bootstrapSettings.RemoteDirect.IsoServer
+
+	// The ISO builder writes the image to the host side of the container volume by default
+	isoPath := serverConfig.IsoPath
+	if isoPath == "" {
+		if bootstrapSettings.Container == nil || bootstrapSettings.Container.Volume == "" {
+			return nil, ErrMissingBootstrapInfoOption{What: "isoServer.isoPath"}
+		}
+
+		hostVol := strings.Split(bootstrapSettings.Container.Volume, ":")[0]
+		isoPath = filepath.Join(hostVol, config.AirshipDefaultIsoName)
+	}
+
+	port := serverConfig.Port
+	if port == 0 {
+		port = config.AirshipDefaultIsoServerPort
+	}
+
+	if dryRun {
+		server, err := isoserver.NewDryRunServer(isoPath, serverConfig.BindAddress, port)
+		if err != nil {
+			return nil, err
+		}
+
+		server.AdvertiseAddress = serverConfig.AdvertiseAddress
+		log.Printf("Dry run: not serving ISO image '%s' on port %d.", isoPath, server.Port())
+
+		return server, nil
+	}
+
+	server, err := isoserver.NewServer(isoPath, serverConfig.BindAddress, port)
+	if err != nil {
+		return nil, err
+	}
+
+	server.AdvertiseAddress = serverConfig.AdvertiseAddress
+
+	log.Printf("Serving ISO image '%s' on port %d.", isoPath, server.Port())
+
+	return server, nil
+}
+
+// DoRemoteDirect bootstraps the ephemeral node. When isoServer is not nil, the BMC boots the node from the image
+// served by isoServer rather than from the isoUrl of the bootstrap configuration. The outcome is recorded in the audit
+// log of the airshipctl settings unless the host is in dry run.
+func (b baremetalHost) DoRemoteDirect(settings *environment.AirshipCTLSettings, isoServer *isoserver.Server) error {
+	err := b.doRemoteDirect(settings, isoServer)
+	b.record(settings, "remote direct", err)
+
+	return err
+}
+
+func (b baremetalHost) doRemoteDirect(settings *environment.AirshipCTLSettings, isoServer *isoserver.Server) error {
 	cfg := settings.Config
 	bootstrapSettings, err := cfg.CurrentContextBootstrapInfo()
 	if err != nil {
@@ -52,11 +121,20 @@ func (b baremetalHost) DoRemoteDirect(settings *environment.AirshipCTLSettings)
 	}
 
 	// Perform remote direct operations
-	if remoteConfig.IsoURL == "" {
+	isoURL := remoteConfig.IsoURL
+	if isoServer != nil {
+		if isoURL, err = isoServer.URL(b.BMCAddress); err != nil {
+			return err
+		}
+
+		log.Debugf("Serving ISO image to ephemeral host '%s' at '%s'.", b.HostName, isoURL)
+	}
+
+	if isoURL == "" {
 		return ErrMissingBootstrapInfoOption{What: "isoURL"}
 	}
 
-	err = b.SetVirtualMedia(b.Context, remoteConfig.IsoURL)
+	err = b.SetVirtualMedia(b.Context, isoURL)
 	if err != nil {
 		return err
 	}
@@ -71,6 +149,11 @@ func (b baremetalHost) DoRemoteDirect(settings *environment.AirshipCTLSettings)
 		return err
 	}
 
+	if b.DryRun() {
+		log.Printf("Dry run: ephemeral host '%s' was not bootstrapped.", b.HostName)
+		return nil
+	}
+
 	log.Printf("Successfully bootstrapped ephemeral host '%s'.", b.HostName)
 
 	return nil
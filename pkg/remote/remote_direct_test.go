@@ -16,12 +16,18 @@ package remote
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"opendev.org/airship/airshipctl/pkg/config"
 	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/remote/isoserver"
 	"opendev.org/airship/airshipctl/pkg/remote/power"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
 	"opendev.org/airship/airshipctl/testutil/redfishutils"
@@ -52,16 +58,16 @@ func TestDoRemoteDirectMissingConfigOpts(t *testing.T) {
 	assert.NoError(t, err)
 
 	ephemeralHost := baremetalHost{
-		rMock,
-		ctx,
-		redfishURL,
-		"doc-name",
-		username,
-		password,
+		Client:     rMock,
+		Context:    ctx,
+		BMCAddress: redfishURL,
+		HostName:   "doc-name",
+		username:   username,
+		password:   password,
 	}
 
 	settings := initSettings(t, withRemoteDirectConfig(nil), withTestDataPath("base"))
-	err = ephemeralHost.DoRemoteDirect(settings)
+	err = ephemeralHost.DoRemoteDirect(settings, nil)
 	assert.Error(t, err)
 }
 
@@ -73,18 +79,18 @@ func TestDoRemoteDirectMissingISOURL(t *testing.T) {
 	rMock.On("SystemPowerStatus", ctx).Times(1).Return(power.StatusOn, nil)
 
 	ephemeralHost := baremetalHost{
-		rMock,
-		ctx,
-		redfishURL,
-		"doc-name",
-		username,
-		password,
+		Client:     rMock,
+		Context:    ctx,
+		BMCAddress: redfishURL,
+		HostName:   "doc-name",
+		username:   username,
+		password:   password,
 	}
 
 	cfg := &config.RemoteDirect{}
 
 	settings := initSettings(t, withRemoteDirectConfig(cfg), withTestDataPath("base"))
-	err = ephemeralHost.DoRemoteDirect(settings)
+	err = ephemeralHost.DoRemoteDirect(settings, nil)
 	assert.Error(t, err)
 }
 
@@ -100,12 +106,12 @@ func TestDoRemoteDirectRedfish(t *testing.T) {
 	rMock.On("RebootSystem", ctx).Times(1).Return(nil)
 
 	ephemeralHost := baremetalHost{
-		rMock,
-		ctx,
-		redfishURL,
-		"doc-name",
-		username,
-		password,
+		Client:     rMock,
+		Context:    ctx,
+		BMCAddress: redfishURL,
+		HostName:   "doc-name",
+		username:   username,
+		password:   password,
 	}
 
 	cfg := &config.RemoteDirect{
@@ -113,7 +119,7 @@ func TestDoRemoteDirectRedfish(t *testing.T) {
 	}
 
 	settings := initSettings(t, withRemoteDirectConfig(cfg), withTestDataPath("base"))
-	err = ephemeralHost.DoRemoteDirect(settings)
+	err = ephemeralHost.DoRemoteDirect(settings, nil)
 	assert.NoError(t, err)
 }
 
@@ -130,12 +136,12 @@ func TestDoRemoteDirectRedfishNodePoweredOff(t *testing.T) {
 	rMock.On("RebootSystem", ctx).Times(1).Return(nil)
 
 	ephemeralHost := baremetalHost{
-		rMock,
-		ctx,
-		redfishURL,
-		"doc-name",
-		username,
-		password,
+		Client:     rMock,
+		Context:    ctx,
+		BMCAddress: redfishURL,
+		HostName:   "doc-name",
+		username:   username,
+		password:   password,
 	}
 
 	cfg := &config.RemoteDirect{
@@ -143,7 +149,7 @@ func TestDoRemoteDirectRedfishNodePoweredOff(t *testing.T) {
 	}
 
 	settings := initSettings(t, withRemoteDirectConfig(cfg), withTestDataPath("base"))
-	err = ephemeralHost.DoRemoteDirect(settings)
+	err = ephemeralHost.DoRemoteDirect(settings, nil)
 	assert.NoError(t, err)
 }
 
@@ -161,12 +167,12 @@ func TestDoRemoteDirectRedfishVirtualMediaError(t *testing.T) {
 	rMock.On("RebootSystem", ctx).Times(1).Return(nil)
 
 	ephemeralHost := baremetalHost{
-		rMock,
-		ctx,
-		redfishURL,
-		"doc-name",
-		username,
-		password,
+		Client:     rMock,
+		Context:    ctx,
+		BMCAddress: redfishURL,
+		HostName:   "doc-name",
+		username:   username,
+		password:   password,
 	}
 
 	cfg := &config.RemoteDirect{
@@ -175,7 +181,7 @@ func TestDoRemoteDirectRedfishVirtualMediaError(t *testing.T) {
 
 	settings := initSettings(t, withRemoteDirectConfig(cfg), withTestDataPath("base"))
 
-	err = ephemeralHost.DoRemoteDirect(settings)
+	err = ephemeralHost.DoRemoteDirect(settings, nil)
 	_, ok := err.(redfish.ErrRedfishClient)
 	assert.True(t, ok)
 }
@@ -194,12 +200,12 @@ func TestDoRemoteDirectRedfishBootSourceError(t *testing.T) {
 	rMock.On("RebootSystem", ctx).Times(1).Return(nil)
 
 	ephemeralHost := baremetalHost{
-		rMock,
-		ctx,
-		redfishURL,
-		"doc-name",
-		username,
-		password,
+		Client:     rMock,
+		Context:    ctx,
+		BMCAddress: redfishURL,
+		HostName:   "doc-name",
+		username:   username,
+		password:   password,
 	}
 
 	cfg := &config.RemoteDirect{
@@ -208,7 +214,7 @@ func TestDoRemoteDirectRedfishBootSourceError(t *testing.T) {
 
 	settings := initSettings(t, withRemoteDirectConfig(cfg), withTestDataPath("base"))
 
-	err = ephemeralHost.DoRemoteDirect(settings)
+	err = ephemeralHost.DoRemoteDirect(settings, nil)
 	_, ok := err.(redfish.ErrRedfishClient)
 	assert.True(t, ok)
 }
@@ -228,12 +234,12 @@ func TestDoRemoteDirectRedfishRebootError(t *testing.T) {
 	rMock.On("RebootSystem", ctx).Times(1).Return(expectedErr)
 
 	ephemeralHost := baremetalHost{
-		rMock,
-		ctx,
-		redfishURL,
-		"doc-name",
-		username,
-		password,
+		Client:     rMock,
+		Context:    ctx,
+		BMCAddress: redfishURL,
+		HostName:   "doc-name",
+		username:   username,
+		password:   password,
 	}
 
 	cfg := &config.RemoteDirect{
@@ -242,7 +248,146 @@ func TestDoRemoteDirectRedfishRebootError(t *testing.T) {
 
 	settings := initSettings(t, withRemoteDirectConfig(cfg), withTestDataPath("base"))
 
-	err = ephemeralHost.DoRemoteDirect(settings)
+	err = ephemeralHost.DoRemoteDirect(settings, nil)
 	_, ok := err.(redfish.ErrRedfishClient)
 	assert.True(t, ok)
 }
+
+func TestDoRemoteDirectISOServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airshipctl-remote-direct-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	isoPath := filepath.Join(dir, "ephemeral.iso")
+	require.NoError(t, ioutil.WriteFile(isoPath, []byte("ISO"), 0600))
+
+	isoServer, err := isoserver.NewServer(isoPath, "127.0.0.1", 0)
+	require.NoError(t, err)
+	defer isoServer.Close()
+
+	servedURL := fmt.Sprintf("http://127.0.0.1:%d/ephemeral.iso", isoServer.Port())
+
+	ctx, rMock, err := redfishutils.NewClient(redfishURL, false, false, username, password)
+	require.NoError(t, err)
+
+	rMock.On("NodeID").Return(systemID)
+	rMock.On("SystemPowerStatus", ctx).Times(1).Return(power.StatusOn, nil)
+	rMock.On("SetVirtualMedia", ctx, servedURL).Times(1).Return(nil)
+	rMock.On("SetBootSourceByType", ctx).Times(1).Return(nil)
+	rMock.On("RebootSystem", ctx).Times(1).Return(nil)
+
+	ephemeralHost := baremetalHost{
+		Client:     rMock,
+		Context:    ctx,
+		BMCAddress: redfishURL,
+		HostName:   "doc-name",
+		username:   username,
+		password:   password,
+	}
+
+	// The URL of the served image takes precedence over the configured one
+	cfg := &config.RemoteDirect{
+		IsoURL:    isoURL,
+		IsoServer: &config.IsoServer{BindAddress: "127.0.0.1"},
+	}
+
+	settings := initSettings(t, withRemoteDirectConfig(cfg), withTestDataPath("base"))
+	err = ephemeralHost.DoRemoteDirect(settings, isoServer)
+	assert.NoError(t, err)
+
+	rMock.AssertExpectations(t)
+}
+
+func TestServeEphemeralISO(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         *config.RemoteDirect
+		volume      string
+		expectedErr error
+	}{
+		{
+			name: "NoRemoteDirectConfig",
+		},
+		{
+			name: "ServerDisabled",
+			cfg:  &config.RemoteDirect{IsoURL: isoURL},
+		},
+		{
+			name:        "NoISOPath",
+			cfg:         &config.RemoteDirect{IsoServer: &config.IsoServer{}},
+			expectedErr: ErrMissingBootstrapInfoOption{What: "isoServer.isoPath"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			settings := initSettings(t, withRemoteDirectConfig(tt.cfg), withContainerVolume(tt.volume))
+
+			isoServer, err := ServeEphemeralISO(settings, false)
+			assert.Equal(t, tt.expectedErr, err)
+			assert.Nil(t, isoServer)
+		})
+	}
+}
+
+func TestServeEphemeralISOMissingImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airshipctl-remote-direct-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The image is looked up in the host side of the container volume
+	cfg := &config.RemoteDirect{IsoServer: &config.IsoServer{BindAddress: "127.0.0.1"}}
+	settings := initSettings(t, withRemoteDirectConfig(cfg), withContainerVolume(dir+":/config"))
+
+	isoServer, err := ServeEphemeralISO(settings, false)
+	require.Error(t, err)
+	assert.Nil(t, isoServer)
+
+	pathErr, ok := err.(*os.PathError)
+	require.True(t, ok)
+	assert.Equal(t, filepath.Join(dir, config.AirshipDefaultIsoName), pathErr.Path)
+}
+
+func TestServeEphemeralISODryRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "airshipctl-remote-direct-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	isoPath := filepath.Join(dir, "ephemeral.iso")
+	require.NoError(t, ioutil.WriteFile(isoPath, []byte("ISO"), 0600))
+
+	// Find a free port the image would be served on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	cfg := &config.RemoteDirect{IsoServer: &config.IsoServer{IsoPath: isoPath, BindAddress: "127.0.0.1", Port: port}}
+	settings := initSettings(t, withRemoteDirectConfig(cfg))
+
+	isoServer, err := ServeEphemeralISO(settings, true)
+	require.NoError(t, err)
+	defer isoServer.Close()
+
+	isoURL, err := isoServer.URL(redfishURL)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("http://127.0.0.1:%d/ephemeral.iso", port), isoURL)
+
+	// The image is not served, so the port is still free
+	listener, err = net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	require.NoError(t, err)
+	assert.NoError(t, listener.Close())
+}
+
+// withContainerVolume sets the ISO builder container volume when used as an argument to "initSettings".
+func withContainerVolume(volume string) Configuration {
+	return func(settings *environment.AirshipCTLSettings) {
+		bootstrapInfo, err := settings.Config.CurrentContextBootstrapInfo()
+		if err != nil {
+			panic(fmt.Sprintf("Unable to initialize remote direct tests. Current Context error %q", err))
+		}
+
+		bootstrapInfo.Container = &config.Container{Volume: volume}
+	}
+}
@@ -0,0 +1,146 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retry polls out-of-band operations, e.g. power state changes, until they complete or a deadline expires.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Policy describes how long and how often an operation is polled until it completes. Polls are separated by an
+// interval that starts at Interval and is multiplied by Multiplier after every poll, up to MaxInterval. Each interval
+// is randomly spread by up to Jitter, a fraction of the interval, so that many hosts are not polled in lock step.
+type Policy struct {
+	// Timeout bounds how long to wait for an operation to complete. No timeout is applied when it is zero, in which
+	// case only the deadline of the context, if any, ends polling.
+	Timeout time.Duration
+	// Interval is the time to wait before polling an operation again for the first time.
+	Interval time.Duration
+	// MaxInterval caps the time to wait between polls. Intervals are not capped when it is zero.
+	MaxInterval time.Duration
+	// Multiplier is the factor applied to the interval after every poll. Values lower than 1 keep the interval
+	// constant.
+	Multiplier float64
+	// Jitter is the fraction, between 0 and 1, by which each interval is randomly lengthened or shortened.
+	Jitter float64
+}
+
+// ErrTimeout is returned when an operation does not complete before the timeout of a policy or the deadline of its
+// context.
+type ErrTimeout struct {
+	What    string
+	Timeout time.Duration
+}
+
+func (e ErrTimeout) Error() string {
+	if e.Timeout > 0 {
+		return fmt.Sprintf("Unable to %s. Operation timed out after %s.", e.What, e.Timeout)
+	}
+
+	return fmt.Sprintf("Unable to %s. Operation deadline exceeded.", e.What)
+}
+
+// Poll invokes condition until it reports that the operation described by what is done, it returns an error, or the
+// timeout of the policy expires. The context passed to condition carries the deadline of the policy, so that requests
+// made by condition are cancelled once it expires. sleep is used to wait between polls; it is never asked to wait past
+// the deadline.
+func (p Policy) Poll(ctx context.Context, what string, sleep func(time.Duration),
+	condition func(ctx context.Context) (bool, error)) error {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+
+	interval := p.Interval
+	for {
+		done, err := condition(ctx)
+		if err != nil {
+			if expired(ctx) {
+				return ErrTimeout{What: what, Timeout: p.Timeout}
+			}
+
+			return err
+		}
+
+		if done {
+			return nil
+		}
+
+		wait := p.jitter(interval)
+		if deadline, ok := ctx.Deadline(); ok {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return ErrTimeout{What: what, Timeout: p.Timeout}
+			}
+
+			if wait > remaining {
+				wait = remaining
+			}
+		}
+
+		sleep(wait)
+
+		switch ctx.Err() {
+		case nil:
+		case context.DeadlineExceeded:
+			return ErrTimeout{What: what, Timeout: p.Timeout}
+		default:
+			return ctx.Err()
+		}
+
+		interval = p.next(interval)
+	}
+}
+
+// expired reports whether the deadline of ctx has passed. A request failing because of the deadline may return before
+// the context itself reports it, so the deadline is checked as well.
+func expired(ctx context.Context) bool {
+	if ctx.Err() == context.DeadlineExceeded {
+		return true
+	}
+
+	deadline, ok := ctx.Deadline()
+	return ok && !time.Now().Before(deadline)
+}
+
+// next returns the interval that follows interval.
+func (p Policy) next(interval time.Duration) time.Duration {
+	if p.Multiplier > 1 {
+		interval = time.Duration(float64(interval) * p.Multiplier)
+	}
+
+	if p.MaxInterval > 0 && interval > p.MaxInterval {
+		interval = p.MaxInterval
+	}
+
+	return interval
+}
+
+// jitter randomly spreads interval by up to the jitter fraction of the policy.
+func (p Policy) jitter(interval time.Duration) time.Duration {
+	if p.Jitter <= 0 || interval <= 0 {
+		return interval
+	}
+
+	jitter := p.Jitter
+	if jitter > 1 {
+		jitter = 1
+	}
+
+	delta := float64(interval) * jitter * (2*rand.Float64() - 1) //nolint:gosec
+	return interval + time.Duration(delta)
+}
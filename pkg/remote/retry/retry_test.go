@@ -0,0 +1,128 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPollBackoff(t *testing.T) {
+	policy := Policy{Interval: time.Second, MaxInterval: 5 * time.Second, Multiplier: 2}
+
+	var waits []time.Duration
+	sleep := func(d time.Duration) { waits = append(waits, d) }
+
+	polls := 0
+	err := policy.Poll(context.Background(), "test", sleep, func(_ context.Context) (bool, error) {
+		polls++
+		return polls == 5, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, polls)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}, waits)
+}
+
+func TestPollConstantInterval(t *testing.T) {
+	policy := Policy{Interval: time.Second}
+
+	var waits []time.Duration
+	sleep := func(d time.Duration) { waits = append(waits, d) }
+
+	polls := 0
+	err := policy.Poll(context.Background(), "test", sleep, func(_ context.Context) (bool, error) {
+		polls++
+		return polls == 3, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, time.Second}, waits)
+}
+
+func TestPollJitter(t *testing.T) {
+	policy := Policy{Interval: 10 * time.Second, Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		wait := policy.jitter(policy.Interval)
+		assert.True(t, wait >= 5*time.Second && wait <= 15*time.Second, "wait %s out of range", wait)
+	}
+}
+
+func TestPollTimeout(t *testing.T) {
+	policy := Policy{Timeout: 20 * time.Millisecond, Interval: time.Millisecond}
+
+	err := policy.Poll(context.Background(), "reach power state On", time.Sleep,
+		func(ctx context.Context) (bool, error) {
+			_, ok := ctx.Deadline()
+			assert.True(t, ok)
+			return false, nil
+		})
+
+	assert.Equal(t, ErrTimeout{What: "reach power state On", Timeout: 20 * time.Millisecond}, err)
+}
+
+func TestPollSleepCappedAtDeadline(t *testing.T) {
+	policy := Policy{Timeout: 10 * time.Millisecond, Interval: time.Hour}
+
+	var waits []time.Duration
+	err := policy.Poll(context.Background(), "test", func(d time.Duration) {
+		waits = append(waits, d)
+		time.Sleep(d)
+	}, func(_ context.Context) (bool, error) {
+		return false, nil
+	})
+
+	assert.IsType(t, ErrTimeout{}, err)
+	assert.Len(t, waits, 1)
+	assert.True(t, waits[0] <= 10*time.Millisecond)
+}
+
+func TestPollContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := Policy{Interval: time.Millisecond}.Poll(ctx, "test", time.Sleep, func(_ context.Context) (bool, error) {
+		return false, nil
+	})
+
+	assert.Equal(t, ErrTimeout{What: "test"}, err)
+	assert.Contains(t, err.Error(), "deadline exceeded")
+}
+
+func TestPollContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := Policy{}.Poll(ctx, "test", func(_ time.Duration) { cancel() }, func(_ context.Context) (bool, error) {
+		return false, nil
+	})
+
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestPollConditionError(t *testing.T) {
+	expected := errors.New("BMC unavailable")
+
+	polls := 0
+	err := Policy{}.Poll(context.Background(), "test", func(_ time.Duration) {}, func(_ context.Context) (bool, error) {
+		polls++
+		return false, expected
+	})
+
+	assert.Equal(t, expected, err)
+	assert.Equal(t, 1, polls)
+}
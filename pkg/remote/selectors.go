@@ -0,0 +1,158 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"regexp"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// HostSelector selects the baremetal host documents of a phase that match selection criteria. Selecting no documents
+// is not an error; consumers such as NewManager report ErrNoHostsFound once all selectors have been applied.
+type HostSelector func(document.Bundle) ([]document.Document, error)
+
+// AllHosts selects every baremetal host of a phase.
+func AllHosts() HostSelector {
+	return bySelector(document.NewSelector().ByKind(document.BareMetalHostKind))
+}
+
+// ByName selects the hosts whose documents meet the specified name. Documents of the same name may exist in several
+// namespaces; combine ByName with ByNamespace to select only one of them.
+func ByName(name string) HostSelector {
+	return bySelector(document.NewSelector().ByKind(document.BareMetalHostKind).ByName(name))
+}
+
+// ByNamespace selects the hosts whose documents belong to the specified namespace.
+func ByNamespace(namespace string) HostSelector {
+	return bySelector(document.NewSelector().ByKind(document.BareMetalHostKind).ByNamespace(namespace))
+}
+
+// ByLabel selects the hosts whose documents match a supplied label selector, e.g. "airshipit.org/ephemeral-node=true".
+func ByLabel(label string) HostSelector {
+	return bySelector(document.NewSelector().ByKind(document.BareMetalHostKind).ByLabel(label))
+}
+
+// ByAnnotation selects the hosts whose documents match a supplied annotation selector, using the same syntax as
+// label selectors.
+func ByAnnotation(annotation string) HostSelector {
+	return bySelector(document.NewSelector().ByKind(document.BareMetalHostKind).ByAnnotation(annotation))
+}
+
+// ByBMCAddress selects the hosts whose BMC addresses match a regular expression, e.g. "10\.23\.25\." or "^redfish\+".
+// Hosts without a BMC address are never selected.
+func ByBMCAddress(pattern string) HostSelector {
+	return func(docBundle document.Bundle) ([]document.Document, error) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, ErrInvalidBMCAddressPattern{Pattern: pattern, Err: err}
+		}
+
+		docs, err := AllHosts()(docBundle)
+		if err != nil {
+			return nil, err
+		}
+
+		var selected []document.Document
+		for _, doc := range docs {
+			address, err := document.GetBMHBMCAddress(doc)
+			if err != nil {
+				log.Debugf("Not matching host '%s' by BMC address: %v", doc.GetName(), err)
+				continue
+			}
+
+			if re.MatchString(address) {
+				selected = append(selected, doc)
+			}
+		}
+
+		return selected, nil
+	}
+}
+
+// AnyOf selects the hosts selected by at least one of the supplied selectors, i.e. the union of their selections.
+// Hosts are ordered by the first selector that selects them.
+func AnyOf(selectors ...HostSelector) HostSelector {
+	return func(docBundle document.Bundle) ([]document.Document, error) {
+		var selected []document.Document
+		seen := make(map[string]bool)
+		for _, selector := range selectors {
+			docs, err := selector(docBundle)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, doc := range docs {
+				if key := hostKey(doc); !seen[key] {
+					seen[key] = true
+					selected = append(selected, doc)
+				}
+			}
+		}
+
+		return selected, nil
+	}
+}
+
+// AllOf selects the hosts selected by every one of the supplied selectors, i.e. the intersection of their
+// selections, in the order of the first selector. No hosts are selected when no selectors are supplied; use AllHosts
+// to select every host.
+func AllOf(selectors ...HostSelector) HostSelector {
+	return func(docBundle document.Bundle) ([]document.Document, error) {
+		if len(selectors) == 0 {
+			return nil, nil
+		}
+
+		selected, err := selectors[0](docBundle)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, selector := range selectors[1:] {
+			docs, err := selector(docBundle)
+			if err != nil {
+				return nil, err
+			}
+
+			matches := make(map[string]bool)
+			for _, doc := range docs {
+				matches[hostKey(doc)] = true
+			}
+
+			var remaining []document.Document
+			for _, doc := range selected {
+				if matches[hostKey(doc)] {
+					remaining = append(remaining, doc)
+				}
+			}
+			selected = remaining
+		}
+
+		return selected, nil
+	}
+}
+
+// bySelector selects the documents of a phase that match a document selector.
+func bySelector(selector document.Selector) HostSelector {
+	return func(docBundle document.Bundle) ([]document.Document, error) {
+		return docBundle.Select(selector)
+	}
+}
+
+// hostKey identifies the document of a host within a phase.
+func hostKey(doc document.Document) string {
+	return doc.GetNamespace() + "/" + doc.GetName()
+}
@@ -0,0 +1,125 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/document"
+)
+
+const selectionDocsPath = "testdata/selection/manifests/site/test-site/ephemeral/bootstrap"
+
+func TestHostSelectors(t *testing.T) {
+	docBundle, err := document.NewBundleByPath(selectionDocsPath)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		selector HostSelector
+		expected []string
+	}{
+		{
+			name:     "all",
+			selector: AllHosts(),
+			expected: []string{"site-a/node-1", "site-a/node-2", "site-b/node-1", "site-b/node-3"},
+		},
+		{
+			name:     "name in several namespaces",
+			selector: ByName("node-1"),
+			expected: []string{"site-a/node-1", "site-b/node-1"},
+		},
+		{
+			name:     "namespace",
+			selector: ByNamespace("site-b"),
+			expected: []string{"site-b/node-1", "site-b/node-3"},
+		},
+		{
+			name:     "label",
+			selector: ByLabel("airshipit.org/role=worker"),
+			expected: []string{"site-a/node-2", "site-b/node-1", "site-b/node-3"},
+		},
+		{
+			name:     "annotation",
+			selector: ByAnnotation("airshipit.org/power-domain=pdu-1"),
+			expected: []string{"site-a/node-1", "site-b/node-1"},
+		},
+		{
+			name:     "BMC address",
+			selector: ByBMCAddress(`^redfish\+https://10\.23\.25\.`),
+			expected: []string{"site-a/node-1", "site-a/node-2"},
+		},
+		{
+			name:     "no match",
+			selector: ByName("does-not-exist"),
+		},
+		{
+			name:     "intersection",
+			selector: AllOf(ByName("node-1"), ByNamespace("site-b")),
+			expected: []string{"site-b/node-1"},
+		},
+		{
+			name:     "intersection keeps the order of the first selector",
+			selector: AllOf(ByLabel("airshipit.org/role=worker"), ByBMCAddress("10.23")),
+			expected: []string{"site-a/node-2", "site-b/node-1"},
+		},
+		{
+			name:     "intersection without selectors",
+			selector: AllOf(),
+		},
+		{
+			name:     "union",
+			selector: AnyOf(ByNamespace("site-b"), ByAnnotation("airshipit.org/power-domain=pdu-1")),
+			expected: []string{"site-b/node-1", "site-b/node-3", "site-a/node-1"},
+		},
+		{
+			name:     "union of intersections",
+			selector: AnyOf(AllOf(ByName("node-1"), ByNamespace("site-a")), ByName("node-2")),
+			expected: []string{"site-a/node-1", "site-a/node-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := tt.selector(docBundle)
+			require.NoError(t, err)
+
+			var selected []string
+			for _, doc := range docs {
+				selected = append(selected, hostKey(doc))
+			}
+
+			assert.Equal(t, tt.expected, selected)
+		})
+	}
+}
+
+func TestHostSelectorsInvalidBMCAddressPattern(t *testing.T) {
+	docBundle, err := document.NewBundleByPath(selectionDocsPath)
+	require.NoError(t, err)
+
+	for _, selector := range []HostSelector{
+		ByBMCAddress("[10.23"),
+		AllOf(AllHosts(), ByBMCAddress("[10.23")),
+		AnyOf(AllHosts(), ByBMCAddress("[10.23")),
+	} {
+		_, err = selector(docBundle)
+		assert.IsType(t, ErrInvalidBMCAddressPattern{}, err)
+	}
+}
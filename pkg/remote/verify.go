@@ -0,0 +1,328 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/environment"
+	"opendev.org/airship/airshipctl/pkg/log"
+)
+
+// Checks performed when verifying that a baremetal host is ready for remote direct, in the order they are performed
+const (
+	CheckCredentials  = "credentials"
+	CheckReachable    = "reachable"
+	CheckLogin        = "login"
+	CheckVirtualMedia = "virtual-media"
+)
+
+// Outcomes of a verification check
+const (
+	CheckPassed  = "PASS"
+	CheckFailed  = "FAIL"
+	CheckSkipped = "SKIP"
+)
+
+const (
+	// verifyDialTimeout bounds the time spent connecting to a BMC when checking that it is reachable
+	verifyDialTimeout = 10 * time.Second
+	// verifyRequestTimeout bounds the time spent on each request made to a BMC while verifying a host
+	verifyRequestTimeout = 30 * time.Second
+)
+
+// Checks lists the checks performed when verifying a baremetal host, in the order they are performed.
+var Checks = []string{CheckCredentials, CheckReachable, CheckLogin, CheckVirtualMedia}
+
+// CheckResult records the outcome of a single verification check performed on a baremetal host. Failed checks carry
+// a reason describing how to correct the failure.
+type CheckResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// HostVerification records the outcome of every verification check performed on a baremetal host.
+type HostVerification struct {
+	HostName   string        `json:"name"`
+	BMCAddress string        `json:"bmcAddress"`
+	Checks     []CheckResult `json:"checks"`
+}
+
+// Passed reports whether none of the checks performed on the host failed.
+func (v HostVerification) Passed() bool {
+	for _, check := range v.Checks {
+		if check.Status == CheckFailed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Check returns the result of the named check.
+func (v HostVerification) Check(name string) CheckResult {
+	for _, check := range v.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+
+	return CheckResult{Name: name, Status: CheckSkipped}
+}
+
+// Verify checks that the baremetal hosts selected by the selector are ready for remote direct: their BMC
+// credentials resolve, their BMCs are reachable and accept the credentials, and their BMCs provide a virtual media
+// device that can boot an ISO image. Unlike a manager, which fails as soon as a host cannot be managed, every
+// selected host is verified, acting on up to concurrency hosts at once. When any check fails on any host, an
+// ErrHostVerificationFailed error is returned alongside the results.
+func Verify(settings *environment.AirshipCTLSettings, phase string, selector HostSelector,
+	concurrency int) ([]HostVerification, error) {
+	mgmtCfg, docBundle, err := loadPhase(settings, phase)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := selector(docBundle)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(docs) == 0 {
+		return nil, ErrNoHostsFound{}
+	}
+
+	v := hostVerifier{
+		mgmtCfg:   *mgmtCfg,
+		docBundle: docBundle,
+		phase:     phase,
+		dial:      net.DialTimeout,
+		newHost:   newBaremetalHostWithCredentials,
+	}
+
+	return v.verifyAll(docs, concurrency)
+}
+
+// hostVerifier performs verification checks on baremetal hosts. Connections to BMCs and management clients are
+// created by its dial and newHost functions so that they can be replaced in tests.
+type hostVerifier struct {
+	mgmtCfg   config.ManagementConfiguration
+	docBundle document.Bundle
+	phase     string
+	dial      func(network, address string, timeout time.Duration) (net.Conn, error)
+	newHost   func(mgmtCfg config.ManagementConfiguration, hostDoc document.Document, username,
+		password string) (baremetalHost, error)
+}
+
+// verifyAll verifies the hosts described by docs, acting on up to concurrency hosts at once.
+func (v hostVerifier) verifyAll(docs []document.Document, concurrency int) ([]HostVerification, error) {
+	results := make([]HostVerification, len(docs))
+	fanOut(len(docs), concurrency, func(i int) {
+		results[i] = v.verify(docs[i])
+	})
+
+	var failures []HostVerification
+	for _, result := range results {
+		if !result.Passed() {
+			failures = append(failures, result)
+		}
+	}
+
+	if len(failures) > 0 {
+		return results, ErrHostVerificationFailed{Hosts: len(results), Failures: failures}
+	}
+
+	return results, nil
+}
+
+// verify performs every verification check on a single host. Checks that depend on a failed check are skipped.
+func (v hostVerifier) verify(hostDoc document.Document) HostVerification {
+	result := HostVerification{HostName: hostDoc.GetName()}
+	log.Debugf("Verifying host '%s'.", result.HostName)
+
+	record := func(name string, reason string, passed bool) {
+		status := CheckPassed
+		if !passed {
+			status = CheckFailed
+		}
+
+		result.Checks = append(result.Checks, CheckResult{Name: name, Status: status, Reason: reason})
+	}
+
+	skip := func(reason string, names ...string) {
+		for _, name := range names {
+			result.Checks = append(result.Checks, CheckResult{Name: name, Status: CheckSkipped, Reason: reason})
+		}
+	}
+
+	credentials, username, password, credReason := v.checkCredentials(hostDoc)
+	if credReason != "" {
+		record(CheckCredentials, credReason, false)
+	} else {
+		record(CheckCredentials, "", true)
+	}
+
+	address, err := document.GetBMHBMCAddress(hostDoc)
+	if err != nil {
+		record(CheckReachable, fmt.Sprintf("Unable to retrieve the BMC address: %v. Set spec.bmc.address in the "+
+			"baremetal host document.", err), false)
+		skip("Skipped because the BMC address is unknown.", CheckLogin, CheckVirtualMedia)
+		return result
+	}
+
+	result.BMCAddress = address
+
+	reachable, reason := v.checkReachable(address)
+	if reason == "" {
+		skip(fmt.Sprintf("Reachability of BMC address '%s' is verified by the login check.", address),
+			CheckReachable)
+	} else {
+		record(CheckReachable, reason, reachable)
+	}
+
+	switch {
+	case credReason != "":
+		skip("Skipped because the BMC credentials could not be resolved.", CheckLogin, CheckVirtualMedia)
+		return result
+	case !reachable:
+		skip("Skipped because the BMC is unreachable.", CheckLogin, CheckVirtualMedia)
+		return result
+	}
+
+	host, err := v.newHost(v.mgmtCfg, hostDoc, username, password)
+	if err != nil {
+		record(CheckLogin, fmt.Sprintf("Unable to create a management client: %v. Verify the management type of "+
+			"the current context and the BMC address.", err), false)
+		skip("Skipped because the login check failed.", CheckVirtualMedia)
+		return result
+	}
+	defer closeHost(host)
+
+	if err = v.checkLogin(host); err != nil {
+		record(CheckLogin, fmt.Sprintf("Unable to query the BMC: %s. Verify the username and password provided by "+
+			"%s and that the BMC account is enabled.", collapse(err), credentials.Source()), false)
+		skip("Skipped because the login check failed.", CheckVirtualMedia)
+		return result
+	}
+
+	record(CheckLogin, "", true)
+
+	supported, err := v.checkVirtualMedia(host)
+	switch {
+	case err != nil:
+		record(CheckVirtualMedia, fmt.Sprintf("Unable to query virtual media devices: %s.", collapse(err)), false)
+	case !supported:
+		record(CheckVirtualMedia, "The BMC does not provide a CD or DVD virtual media device, which remote direct "+
+			"requires to boot the host. Verify that virtual media is licensed and enabled on the BMC, and that the "+
+			"management type supports virtual media.", false)
+	default:
+		record(CheckVirtualMedia, "", true)
+	}
+
+	return result
+}
+
+// checkCredentials resolves the BMC credentials of a host with the credential provider of the management
+// configuration, returning the provider and the credentials, or the reason they cannot be resolved. Credentials are
+// resolved once per host, since providers may run external commands to resolve them.
+func (v hostVerifier) checkCredentials(hostDoc document.Document) (CredentialProvider, string, string, string) {
+	credentials, err := NewCredentialProvider(v.mgmtCfg.Credentials)
+	if err != nil {
+		return nil, "", "", fmt.Sprintf("Unable to resolve BMC credentials: %v. Fix the credentials section of the "+
+			"management configuration.", err)
+	}
+
+	username, password, err := credentials.Credentials(hostDoc, v.docBundle)
+	_, fromSecret := credentials.(secretCredentials)
+	switch {
+	case err == nil:
+		return credentials, username, password, ""
+	case fromSecret:
+		return credentials, "", "", fmt.Sprintf("Unable to resolve BMC credentials: %v. Ensure "+
+			"spec.bmc.credentialsName references a Secret with username and password data in phase '%s'.", err,
+			v.phase)
+	default:
+		return credentials, "", "", fmt.Sprintf("Unable to resolve BMC credentials: %v.", err)
+	}
+}
+
+// checkReachable attempts to connect to the BMC at address. When the BMC is unreachable, a reason describing the
+// failure is returned. BMC addresses whose transport cannot be verified by connecting to them, e.g. IPMI addresses,
+// are considered reachable and no reason is returned.
+func (v hostVerifier) checkReachable(address string) (bool, string) {
+	target, ok := dialAddress(address)
+	if !ok {
+		return true, ""
+	}
+
+	conn, err := v.dial("tcp", target, verifyDialTimeout)
+	if err != nil {
+		return false, fmt.Sprintf("Unable to connect to %s: %v. Verify the BMC address and that the BMC network is "+
+			"reachable from this machine.", target, err)
+	}
+
+	if err = conn.Close(); err != nil {
+		log.Debugf("Failed to close connection to %s: %v", target, err)
+	}
+
+	return true, fmt.Sprintf("Connected to %s.", target)
+}
+
+func (v hostVerifier) checkLogin(host baremetalHost) error {
+	ctx, cancel := context.WithTimeout(host.Context, verifyRequestTimeout)
+	defer cancel()
+
+	_, err := host.SystemPowerStatus(ctx)
+	return err
+}
+
+func (v hostVerifier) checkVirtualMedia(host baremetalHost) (bool, error) {
+	ctx, cancel := context.WithTimeout(host.Context, verifyRequestTimeout)
+	defer cancel()
+
+	return host.SupportsVirtualMedia(ctx)
+}
+
+// dialAddress derives the TCP address of a BMC from an HTTP(S)-based BMC address, e.g.
+// redfish+https://10.23.25.1/redfish/v1/Systems/1. Addresses with any other transport are not dialed.
+func dialAddress(address string) (string, bool) {
+	u, err := url.Parse(address)
+	if err != nil || u.Hostname() == "" {
+		return "", false
+	}
+
+	var defaultPort string
+	switch _, transport := parseBMCScheme(address); transport {
+	case transportHTTPS:
+		defaultPort = "443"
+	case transportHTTP:
+		defaultPort = "80"
+	default:
+		return "", false
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	return net.JoinHostPort(u.Hostname(), port), true
+}
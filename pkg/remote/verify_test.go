@@ -0,0 +1,240 @@
+/*
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     https://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package remote
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"opendev.org/airship/airshipctl/pkg/config"
+	"opendev.org/airship/airshipctl/pkg/document"
+	"opendev.org/airship/airshipctl/pkg/remote/power"
+	"opendev.org/airship/airshipctl/testutil/redfishutils"
+)
+
+const bootstrapDocsPath = "testdata/base/manifests/site/test-site/ephemeral/bootstrap"
+
+// newTestVerifier creates a verifier for the documents of the base test site whose connections to BMCs succeed and
+// whose hosts are managed by the supplied mock client.
+func newTestVerifier(t *testing.T, rMock *redfishutils.MockClient) (hostVerifier, document.Bundle) {
+	t.Helper()
+
+	docBundle, err := document.NewBundleByPath(bootstrapDocsPath)
+	require.NoError(t, err)
+
+	v := hostVerifier{
+		docBundle: docBundle,
+		phase:     config.BootstrapPhase,
+		dial: func(network, address string, timeout time.Duration) (net.Conn, error) {
+			client, server := net.Pipe()
+			server.Close()
+			return client, nil
+		},
+		newHost: func(_ config.ManagementConfiguration, doc document.Document, _, _ string) (baremetalHost, error) {
+			host, _ := newMockHost(t, doc.GetName())
+			host.Client = rMock
+			return host, nil
+		},
+	}
+
+	return v, docBundle
+}
+
+func selectHostDoc(t *testing.T, docBundle document.Bundle, name string) document.Document {
+	t.Helper()
+
+	doc, err := docBundle.SelectOne(document.NewSelector().ByKind(document.BareMetalHostKind).ByName(name))
+	require.NoError(t, err)
+
+	return doc
+}
+
+func TestVerify(t *testing.T) {
+	rMock := &redfishutils.MockClient{}
+	rMock.On("SystemPowerStatus", mock.Anything).Times(2).Return(power.StatusOn, nil)
+	rMock.On("SupportsVirtualMedia", mock.Anything).Times(2).Return(true, nil)
+	defer rMock.AssertExpectations(t)
+
+	v, docBundle := newTestVerifier(t, rMock)
+	docs := []document.Document{selectHostDoc(t, docBundle, "master-1"), selectHostDoc(t, docBundle, "master-2")}
+
+	results, err := v.verifyAll(docs, 2)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "master-1", results[0].HostName)
+	assert.Equal(t, "master-2", results[1].HostName)
+	for _, result := range results {
+		assert.True(t, result.Passed())
+		for _, check := range Checks {
+			assert.Equal(t, CheckPassed, result.Check(check).Status)
+		}
+	}
+
+	assert.Equal(t, "Connected to nolocalhost:8888.", results[0].Check(CheckReachable).Reason)
+}
+
+func TestVerifyMissingCredentials(t *testing.T) {
+	rMock := &redfishutils.MockClient{}
+	defer rMock.AssertExpectations(t)
+
+	v, docBundle := newTestVerifier(t, rMock)
+
+	results, err := v.verifyAll([]document.Document{selectHostDoc(t, docBundle, "no-creds")}, 1)
+	_, ok := err.(ErrHostVerificationFailed)
+	assert.True(t, ok)
+
+	result := results[0]
+	assert.False(t, result.Passed())
+	assert.Equal(t, CheckFailed, result.Check(CheckCredentials).Status)
+	assert.Contains(t, result.Check(CheckCredentials).Reason, "spec.bmc.credentialsName")
+	assert.Equal(t, CheckPassed, result.Check(CheckReachable).Status)
+	assert.Equal(t, CheckSkipped, result.Check(CheckLogin).Status)
+	assert.Equal(t, CheckSkipped, result.Check(CheckVirtualMedia).Status)
+}
+
+func TestVerifyUnreachable(t *testing.T) {
+	rMock := &redfishutils.MockClient{}
+	defer rMock.AssertExpectations(t)
+
+	v, docBundle := newTestVerifier(t, rMock)
+	v.dial = func(network, address string, timeout time.Duration) (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	results, err := v.verifyAll([]document.Document{selectHostDoc(t, docBundle, "master-1")}, 1)
+	_, ok := err.(ErrHostVerificationFailed)
+	assert.True(t, ok)
+
+	result := results[0]
+	assert.Equal(t, CheckPassed, result.Check(CheckCredentials).Status)
+	assert.Equal(t, CheckFailed, result.Check(CheckReachable).Status)
+	assert.Contains(t, result.Check(CheckReachable).Reason, "Unable to connect to nolocalhost:8888")
+	assert.Equal(t, CheckSkipped, result.Check(CheckLogin).Status)
+	assert.Equal(t, CheckSkipped, result.Check(CheckVirtualMedia).Status)
+}
+
+func TestVerifyLoginFailure(t *testing.T) {
+	rMock := &redfishutils.MockClient{}
+	rMock.On("SystemPowerStatus", mock.Anything).Times(1).Return(nil, nil, errors.New("401 Unauthorized"))
+	defer rMock.AssertExpectations(t)
+
+	v, docBundle := newTestVerifier(t, rMock)
+
+	results, err := v.verifyAll([]document.Document{selectHostDoc(t, docBundle, "master-1")}, 1)
+	require.Error(t, err)
+
+	result := results[0]
+	assert.Equal(t, CheckFailed, result.Check(CheckLogin).Status)
+	assert.Contains(t, result.Check(CheckLogin).Reason, "401 Unauthorized")
+	assert.Equal(t, CheckSkipped, result.Check(CheckVirtualMedia).Status)
+}
+
+func TestVerifyLoginFailureNamesProvider(t *testing.T) {
+	rMock := &redfishutils.MockClient{}
+	rMock.On("SystemPowerStatus", mock.Anything).Times(1).Return(nil, nil, errors.New("401 Unauthorized"))
+	defer rMock.AssertExpectations(t)
+
+	dir, err := ioutil.TempDir("", "airshipctl-verify-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The command records each of its runs, so that credentials resolved more than once are detected
+	runs := filepath.Join(dir, "runs")
+	v, docBundle := newTestVerifier(t, rMock)
+	v.mgmtCfg.Credentials = &config.CredentialsConfiguration{
+		Provider: config.CredentialProviderExec,
+		Command: []string{"sh", "-c", `echo "$1" >> "$0"; printf '{"username": "admin", "password": "vault"}'`,
+			runs},
+	}
+
+	newHost := v.newHost
+	v.newHost = func(mgmtCfg config.ManagementConfiguration, doc document.Document, username,
+		password string) (baremetalHost, error) {
+		assert.Equal(t, "admin", username)
+		assert.Equal(t, "vault", password)
+		return newHost(mgmtCfg, doc, username, password)
+	}
+
+	results, err := v.verifyAll([]document.Document{selectHostDoc(t, docBundle, "master-1")}, 1)
+	require.Error(t, err)
+
+	assert.Contains(t, results[0].Check(CheckLogin).Reason, "Verify the username and password provided by "+
+		"credentials command 'sh -c")
+
+	data, err := ioutil.ReadFile(runs)
+	require.NoError(t, err)
+	assert.Equal(t, "master-1\n", string(data))
+}
+
+func TestVerifyVirtualMediaUnsupported(t *testing.T) {
+	rMock := &redfishutils.MockClient{}
+	rMock.On("SystemPowerStatus", mock.Anything).Times(1).Return(power.StatusOff, nil)
+	rMock.On("SupportsVirtualMedia", mock.Anything).Times(1).Return(false, nil)
+	defer rMock.AssertExpectations(t)
+
+	v, docBundle := newTestVerifier(t, rMock)
+
+	results, err := v.verifyAll([]document.Document{selectHostDoc(t, docBundle, "master-1")}, 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "master-1 (redfish+http://nolocalhost:8888/redfish/v1/Systems/node-master-1): "+
+		"virtual-media: The BMC does not provide a CD or DVD virtual media device")
+
+	result := results[0]
+	assert.Equal(t, CheckPassed, result.Check(CheckLogin).Status)
+	assert.Equal(t, CheckFailed, result.Check(CheckVirtualMedia).Status)
+}
+
+func TestVerifyNoHostsFound(t *testing.T) {
+	settings := initSettings(t, withTestDataPath("base"))
+
+	_, err := Verify(settings, config.BootstrapPhase, ByName("does-not-exist"), 1)
+	assert.Equal(t, ErrNoHostsFound{}, err)
+}
+
+func TestDialAddress(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected string
+		dialed   bool
+	}{
+		{address: "redfish+https://10.23.25.1/redfish/v1/Systems/1", expected: "10.23.25.1:443", dialed: true},
+		{address: "redfish+http://10.23.25.1:8000/redfish/v1/Systems/1", expected: "10.23.25.1:8000", dialed: true},
+		{address: "https://[fd00::1]/redfish/v1/Systems/1", expected: "[fd00::1]:443", dialed: true},
+		{address: "redfish://10.23.25.1/redfish/v1/Systems/1", expected: "10.23.25.1:443", dialed: true},
+		{
+			address:  "idrac-virtualmedia+http://10.23.25.1:8000/redfish/v1/Systems/System.Embedded.1",
+			expected: "10.23.25.1:8000",
+			dialed:   true,
+		},
+		{address: "ipmi://10.23.25.1"},
+		{address: "10.23.25.1"},
+	}
+
+	for _, tt := range tests {
+		target, dialed := dialAddress(tt.address)
+		assert.Equal(t, tt.expected, target, tt.address)
+		assert.Equal(t, tt.dialed, dialed, tt.address)
+	}
+}
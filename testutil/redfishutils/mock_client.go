@@ -14,10 +14,14 @@ package redfishutils
 
 import (
 	"context"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	redfishClient "opendev.org/airship/go-redfish/client"
 
+	"opendev.org/airship/airshipctl/pkg/remote/bios"
+	"opendev.org/airship/airshipctl/pkg/remote/boot"
+	"opendev.org/airship/airshipctl/pkg/remote/inventory"
 	"opendev.org/airship/airshipctl/pkg/remote/power"
 	"opendev.org/airship/airshipctl/pkg/remote/redfish"
 )
@@ -41,6 +45,24 @@ func (m *MockClient) NodeID() string {
 	return args.String(0)
 }
 
+// BootOverride provides a stubbed method that can be mocked to test functions that use the Redfish client without
+// making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("BootOverride").Return(<return values>)
+//
+//         override, err := client.BootOverride(<args>)
+func (m *MockClient) BootOverride(ctx context.Context) (boot.Override, error) {
+	args := m.Called(ctx)
+	override, ok := args.Get(0).(boot.Override)
+	if !ok {
+		return boot.Override{}, args.Error(1)
+	}
+
+	return override, args.Error(1)
+}
+
 // EjectVirtualMedia provides a stubbed method that can be mocked to test functions that use the
 // Redfish client without making any Redfish API calls or requiring the appropriate Redfish client
 // settings.
@@ -55,6 +77,99 @@ func (m *MockClient) EjectVirtualMedia(ctx context.Context) error {
 	return args.Error(0)
 }
 
+// InsertVirtualMedia provides a stubbed method that can be mocked to test functions that use the Redfish client
+// without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("InsertVirtualMedia").Return(<return values>)
+//
+//         mediaID, err := client.InsertVirtualMedia(<args>)
+func (m *MockClient) InsertVirtualMedia(ctx context.Context, isoPath string) (string, error) {
+	args := m.Called(ctx, isoPath)
+	return args.String(0), args.Error(1)
+}
+
+// BIOSSettings provides a stubbed method that can be mocked to test functions that use the Redfish client without
+// making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("BIOSSettings").Return(<return values>)
+//
+//         settings, err := client.BIOSSettings(<args>)
+func (m *MockClient) BIOSSettings(ctx context.Context) (bios.Settings, error) {
+	args := m.Called(ctx)
+	settings, ok := args.Get(0).(bios.Settings)
+	if !ok {
+		return bios.Settings{}, args.Error(1)
+	}
+
+	return settings, args.Error(1)
+}
+
+// SetBIOSAttributes provides a stubbed method that can be mocked to test functions that use the Redfish client
+// without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("SetBIOSAttributes").Return(<return values>)
+//
+//         err := client.SetBIOSAttributes(<args>)
+func (m *MockClient) SetBIOSAttributes(ctx context.Context, attributes bios.Attributes) error {
+	args := m.Called(ctx, attributes)
+	return args.Error(0)
+}
+
+// FirmwareInventory provides a stubbed method that can be mocked to test functions that use the Redfish client without
+// making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("FirmwareInventory").Return(<return values>)
+//
+//         components, err := client.FirmwareInventory(<args>)
+func (m *MockClient) FirmwareInventory(ctx context.Context) ([]inventory.Firmware, error) {
+	args := m.Called(ctx)
+	components, ok := args.Get(0).([]inventory.Firmware)
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return components, args.Error(1)
+}
+
+// Inventory provides a stubbed method that can be mocked to test functions that use the Redfish client without
+// making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("Inventory").Return(<return values>)
+//
+//         inv, err := client.Inventory(<args>)
+func (m *MockClient) Inventory(ctx context.Context) (inventory.Inventory, error) {
+	args := m.Called(ctx)
+	inv, ok := args.Get(0).(inventory.Inventory)
+	if !ok {
+		return inventory.Inventory{}, args.Error(1)
+	}
+
+	return inv, args.Error(1)
+}
+
+// UpdateFirmware provides a stubbed method that can be mocked to test functions that use the Redfish client without
+// making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("UpdateFirmware").Return(<return values>)
+//
+//         err := client.UpdateFirmware(<args>)
+func (m *MockClient) UpdateFirmware(ctx context.Context, imageURL string, targets []string) error {
+	args := m.Called(ctx, imageURL, targets)
+	return args.Error(0)
+}
+
 // RebootSystem provides a stubbed method that can be mocked to test functions that use the Redfish client without
 // making any Redfish API calls or requiring the appropriate Redfish client settings.
 //
@@ -68,6 +183,32 @@ func (m *MockClient) RebootSystem(ctx context.Context) error {
 	return args.Error(0)
 }
 
+// RebootSystemGraceful provides a stubbed method that can be mocked to test functions that use the Redfish client
+// without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("RebootSystemGraceful").Return(<return values>)
+//
+//         err := client.RebootSystemGraceful(<args>)
+func (m *MockClient) RebootSystemGraceful(ctx context.Context, timeout time.Duration) error {
+	args := m.Called(ctx, timeout)
+	return args.Error(0)
+}
+
+// SetBootOverride provides a stubbed method that can be mocked to test functions that use the Redfish client without
+// making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("SetBootOverride").Return(<return values>)
+//
+//         err := client.SetBootOverride(<args>)
+func (m *MockClient) SetBootOverride(ctx context.Context, override boot.Override) error {
+	args := m.Called(ctx, override)
+	return args.Error(0)
+}
+
 // SetBootSourceByType provides a stubbed method that can be mocked to test functions that use the
 // Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
 //
@@ -94,6 +235,19 @@ func (m *MockClient) SetVirtualMedia(ctx context.Context, isoPath string) error
 	return args.Error(0)
 }
 
+// SupportsVirtualMedia provides a stubbed method that can be mocked to test functions that use the Redfish client
+// without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("SupportsVirtualMedia").Return(<return values>)
+//
+//         supported, err := client.SupportsVirtualMedia(<args>)
+func (m *MockClient) SupportsVirtualMedia(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
 // SystemPowerOff provides a stubbed method that can be mocked to test functions that use the
 // Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
 //
@@ -107,6 +261,19 @@ func (m *MockClient) SystemPowerOff(ctx context.Context) error {
 	return args.Error(0)
 }
 
+// SystemPowerOffGraceful provides a stubbed method that can be mocked to test functions that use the Redfish client
+// without making any Redfish API calls or requiring the appropriate Redfish client settings.
+//
+//     Example usage:
+//         client := redfishutils.NewClient()
+//         client.On("SystemPowerOffGraceful").Return(<return values>)
+//
+//         err := client.SystemPowerOffGraceful(<args>)
+func (m *MockClient) SystemPowerOffGraceful(ctx context.Context, timeout time.Duration) error {
+	args := m.Called(ctx, timeout)
+	return args.Error(0)
+}
+
 // SystemPowerOn provides a stubbed method that can be mocked to test functions that use the
 // Redfish client without making any Redfish API calls or requiring the appropriate Redfish client settings.
 //